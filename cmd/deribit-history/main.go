@@ -0,0 +1,115 @@
+// Command deribit-history downloads historical market data from Deribit into
+// CSV, JSON Lines or Parquet files.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/common"
+	"github.com/KyberNetwork/deribit-api/pkg/history"
+	ws "github.com/KyberNetwork/deribit-api/pkg/websocket"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	requestsPerSecond = 10
+	requestsBurst     = 20
+)
+
+var (
+	debug       = flag.Bool("debug", false, "Enable debug logs")
+	wsEndpoint  = flag.String("websocket", ws.RealBaseURL, "Websocket API endpoint")
+	instruments = flag.String("instruments", "", "Comma separated instrument names, e.g. BTC-PERPETUAL,ETH-PERPETUAL")
+	currencies  = flag.String("currencies", "", "Comma separated currencies whose instruments are downloaded")
+	kind        = flag.String("kind", "future", "Instrument kind used to expand currencies")
+	datasets    = flag.String("datasets", "candles,trades", "Comma separated datasets: candles,trades,funding,volatility")
+	start       = flag.String("start", "", "Start time in RFC3339, e.g. 2022-08-01T00:00:00Z")
+	end         = flag.String("end", "", "End time in RFC3339, defaults to now")
+	resolution  = flag.String("resolution", "60", "Candle resolution in minutes or 1D")
+	window      = flag.Duration("window", 0, "Time range covered by one output file, 0 for the dataset default")
+	format      = flag.String("format", "csv", "Output format: csv, jsonl or parquet")
+	outputDir   = flag.String("output", "data", "Output directory")
+)
+
+func setupLogger(debug bool) *zap.SugaredLogger {
+	pConf := zap.NewProductionEncoderConfig()
+	pConf.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoder := zapcore.NewConsoleEncoder(pConf)
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	if debug {
+		level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+	l := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(os.Stdout), level), zap.AddCaller())
+	zap.ReplaceGlobals(l)
+	return zap.S()
+}
+
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func main() {
+	flag.Parse()
+	log := setupLogger(*debug)
+
+	startTime, err := time.Parse(time.RFC3339, *start)
+	if err != nil {
+		log.Fatalw("Invalid start time", "start", *start, "error", err)
+	}
+	endTime := time.Now()
+	if *end != "" {
+		if endTime, err = time.Parse(time.RFC3339, *end); err != nil {
+			log.Fatalw("Invalid end time", "end", *end, "error", err)
+		}
+	}
+
+	var ds []history.Dataset
+	for _, d := range splitList(*datasets) {
+		ds = append(ds, history.Dataset(d))
+	}
+
+	client := ws.New(log, &ws.Configuration{
+		Addr:        *wsEndpoint,
+		RateLimiter: common.NewRateLimiter(requestsPerSecond, requestsBurst),
+	})
+	downloader, err := history.NewDownloader(client, history.Config{
+		Instruments: splitList(*instruments),
+		Currencies:  splitList(*currencies),
+		Kind:        *kind,
+		Datasets:    ds,
+		Start:       startTime,
+		End:         endTime,
+		Resolution:  *resolution,
+		Window:      *window,
+		Format:      history.Format(*format),
+		OutputDir:   *outputDir,
+	})
+	if err != nil {
+		log.Fatalw("Invalid downloader config", "error", err)
+	}
+
+	if err := client.Start(); err != nil {
+		log.Fatalw("Fail to start websocket client", "error", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	err = downloader.Run(ctx)
+	cancel()
+	client.Stop()
+	if err != nil {
+		log.Fatalw("Download stopped", "error", err)
+	}
+	log.Infow("Download completed")
+}
@@ -0,0 +1,716 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+// Generate returns the formatted Go files of a schema by file name. source is the schema file
+// name written in the header of the files.
+func Generate(s *Schema, pkg, source string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	add := func(name string, body *code) error {
+		src, err := body.file(pkg, source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		files[name] = src
+		return nil
+	}
+
+	if err := add("schema.go", schemaFile(s)); err != nil {
+		return nil, err
+	}
+	for _, c := range s.Composites {
+		if err := add(fileName(c.Name), compositeFile(c)); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range s.Enums {
+		if err := add(fileName(e.Name), enumFile(e)); err != nil {
+			return nil, err
+		}
+	}
+	for _, m := range s.Messages {
+		if err := add(fileName(m.Name), messageFile(m)); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+type code struct {
+	bytes.Buffer
+}
+
+// p writes a line, the arguments are formatted as by fmt.Sprintf.
+func (c *code) p(format string, args ...interface{}) {
+	fmt.Fprintf(c, format, args...)
+	c.WriteByte('\n')
+}
+
+func (c *code) file(pkg, source string) ([]byte, error) {
+	body := c.String()
+
+	var imports []string
+	for _, name := range []string{"fmt", "io", "io/ioutil", "math"} {
+		selector := name[strings.LastIndex(name, "/")+1:] + "."
+		if strings.Contains(body, selector) {
+			imports = append(imports, name)
+		}
+	}
+	sort.Strings(imports)
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by sbegen from %s. DO NOT EDIT.\n\npackage %s\n\n", source, pkg)
+	if len(imports) > 0 {
+		src.WriteString("import (\n")
+		for _, name := range imports {
+			fmt.Fprintf(&src, "\t%q\n", name)
+		}
+		src.WriteString(")\n\n")
+	}
+	src.WriteString(body)
+	return format.Source(src.Bytes())
+}
+
+func receiver(typeName string) string {
+	return strings.ToLower(typeName[:1])
+}
+
+func schemaFile(s *Schema) *code {
+	var c code
+	c.p("const (")
+	c.p("SchemaId uint16 = %d", s.ID)
+	c.p("SchemaVersion uint16 = %d", s.Version)
+	c.p(")")
+	return &c
+}
+
+func enumFile(e *Enum) *code {
+	var c code
+	r := receiver(e.Name)
+	typeName := e.Name + "Enum"
+
+	c.p("type %s %s", typeName, e.Primitive.goType)
+	c.p("type %sValues struct {", e.Name)
+	values := make([]string, 0, len(e.Values)+1)
+	for _, v := range e.Values {
+		c.p("%s %s", v.Name, typeName)
+		values = append(values, fmt.Sprint(v.Value))
+	}
+	c.p("NullValue %s", typeName)
+	c.p("}")
+	c.p("")
+	c.p("var %s = %sValues{%s, %d}", e.Name, e.Name, strings.Join(values, ", "), e.NullValue)
+	c.p("")
+
+	c.p("func (%s %s) Encode(_m *SbeGoMarshaller, _w io.Writer) error {", r, typeName)
+	c.p("if err := _m.Write%s(_w, %s(%s)); err != nil {", e.Primitive.method, e.Primitive.goType, r)
+	c.p("return err")
+	c.p("}")
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) Decode(_m *SbeGoMarshaller, _r io.Reader) error {", r, typeName)
+	c.p("if err := _m.Read%s(_r, (*%s)(%s)); err != nil {", e.Primitive.method, e.Primitive.goType, r)
+	c.p("return err")
+	c.p("}")
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	cases := make([]string, 0, len(e.Values)+1)
+	for _, v := range e.Values {
+		cases = append(cases, e.Name+"."+v.Name)
+	}
+	cases = append(cases, e.Name+".NullValue")
+	c.p("func (%s %s) RangeCheck() error {", r, typeName)
+	c.p("switch %s {", r)
+	c.p("case %s:", strings.Join(cases, ", "))
+	c.p("return nil")
+	c.p("}")
+	c.p(`return fmt.Errorf("%%w on %s, unknown enumeration value %%d", ErrRangeCheck, %s)`, e.Name, r)
+	c.p("}")
+	return &c
+}
+
+func compositeFile(comp *Composite) *code {
+	var c code
+	r := receiver(comp.Name)
+
+	c.p("type %s struct {", comp.Name)
+	for _, f := range comp.Fields {
+		c.p("%s %s", f.Name, f.goType())
+	}
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) Encode(_m *SbeGoMarshaller, _w io.Writer) error {", r, comp.Name)
+	for _, f := range comp.Fields {
+		writeField(&c, r, f)
+	}
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) Decode(_m *SbeGoMarshaller, _r io.Reader) error {", r, comp.Name)
+	for _, f := range comp.Fields {
+		readField(&c, r, f)
+	}
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) DecodeBytes(_d *SbeGoDecoder) error {", r, comp.Name)
+	for _, f := range comp.Fields {
+		readFieldBytes(&c, r, f)
+	}
+	c.p("return _d.Err()")
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) RangeCheck() error {", r, comp.Name)
+	for _, f := range comp.Fields {
+		checkField(&c, r, f)
+	}
+	c.p("return nil")
+	c.p("}")
+
+	fieldHelpers(&c, comp.Name, comp.Fields)
+	return &c
+}
+
+// container is a message or a repeating group entry.
+type container struct {
+	typeName        string
+	blockLength     int
+	blockLengthType string
+	fields          []*Field
+	groups          []*Group
+	data            []*Data
+}
+
+func messageFile(m *Message) *code {
+	var c code
+	root := container{
+		typeName:        m.Name,
+		blockLength:     m.BlockLength,
+		blockLengthType: "uint16",
+		fields:          m.Fields,
+		groups:          m.Groups,
+		data:            m.Data,
+	}
+	groups := flattenGroups(m.Groups)
+
+	structType(&c, root)
+	for _, g := range groups {
+		structType(&c, groupContainer(g))
+	}
+
+	r := receiver(m.Name)
+	c.p("func (%s *%s) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {", r, m.Name)
+	c.p("if doRangeCheck {")
+	c.p("if err := %s.RangeCheck(); err != nil {", r)
+	c.p("return err")
+	c.p("}")
+	c.p("}")
+	c.p("")
+	encodeBody(&c, root)
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	c.p("func (%s *%s) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {",
+		r, m.Name)
+	c.p("return %s.DecodeVersion(_m, _r, %s.SbeSchemaVersion(), blockLength, doRangeCheck)", r, r)
+	c.p("}")
+	c.p("")
+
+	c.p("// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.")
+	c.p("func (%s *%s) DecodeVersion(", r, m.Name)
+	c.p("_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,")
+	c.p(") error {")
+	decodeBody(&c, root)
+	c.p("if doRangeCheck {")
+	c.p("if err := %s.RangeCheck(); err != nil {", r)
+	c.p("return err")
+	c.p("}")
+	c.p("}")
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	c.p("// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.")
+	c.p("// The group and var data slices of %s are reused, decoding into the same %s does not allocate once", r, r)
+	c.p("// they are large enough.")
+	c.p("func (%s *%s) DecodeBytes(", r, m.Name)
+	c.p("_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,")
+	c.p(") error {")
+	decodeBytesBody(&c, root)
+	c.p("if err := _d.Err(); err != nil {")
+	c.p("return err")
+	c.p("}")
+	c.p("if doRangeCheck {")
+	c.p("if err := %s.RangeCheck(); err != nil {", r)
+	c.p("return err")
+	c.p("}")
+	c.p("}")
+	c.p("return nil")
+	c.p("}")
+	c.p("")
+
+	rangeCheck(&c, root)
+
+	for _, g := range groups {
+		gc := groupContainer(g)
+		gr := receiver(g.TypeName)
+
+		c.p("")
+		c.p("func (%s *%s) Encode(_m *SbeGoMarshaller, _w io.Writer) error {", gr, g.TypeName)
+		encodeBody(&c, gc)
+		c.p("return nil")
+		c.p("}")
+		c.p("")
+
+		c.p("func (%s *%s) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint) error {", gr, g.TypeName)
+		c.p("return %s.DecodeVersion(_m, _r, SchemaVersion, blockLength)", gr)
+		c.p("}")
+		c.p("")
+
+		c.p("func (%s *%s) DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint) error {",
+			gr, g.TypeName)
+		decodeBody(&c, gc)
+		c.p("return nil")
+		c.p("}")
+		c.p("")
+
+		c.p("func (%s *%s) DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint) error {",
+			gr, g.TypeName)
+		decodeBytesBody(&c, gc)
+		c.p("return _d.Err()")
+		c.p("}")
+		c.p("")
+
+		rangeCheck(&c, gc)
+	}
+
+	c.p("")
+	c.p("func (*%s) SbeBlockLength() (blockLength uint16) {", m.Name)
+	c.p("return %d", m.BlockLength)
+	c.p("}")
+	c.p("")
+	c.p("func (*%s) SbeTemplateId() (templateId uint16) {", m.Name)
+	c.p("return %d", m.TemplateID)
+	c.p("}")
+	c.p("")
+	c.p("func (*%s) SbeSchemaId() (schemaId uint16) {", m.Name)
+	c.p("return SchemaId")
+	c.p("}")
+	c.p("")
+	c.p("func (*%s) SbeSchemaVersion() (schemaVersion uint16) {", m.Name)
+	c.p("return SchemaVersion")
+	c.p("}")
+
+	fieldHelpers(&c, m.Name, m.Fields)
+	for _, g := range groups {
+		fieldHelpers(&c, g.TypeName, g.Fields)
+		c.p("")
+		c.p("func (*%s) SbeBlockLength() (blockLength uint) {", g.TypeName)
+		c.p("return %d", g.BlockLength)
+		c.p("}")
+	}
+	return &c
+}
+
+func groupContainer(g *Group) container {
+	return container{
+		typeName:        g.TypeName,
+		blockLength:     g.BlockLength,
+		blockLengthType: "uint",
+		fields:          g.Fields,
+		groups:          g.Groups,
+		data:            g.Data,
+	}
+}
+
+func flattenGroups(groups []*Group) []*Group {
+	var result []*Group
+	for _, g := range groups {
+		result = append(result, g)
+		result = append(result, flattenGroups(g.Groups)...)
+	}
+	return result
+}
+
+func structType(c *code, ct container) {
+	c.p("type %s struct {", ct.typeName)
+	for _, f := range ct.fields {
+		c.p("%s %s", f.Name, f.goType())
+	}
+	for _, g := range ct.groups {
+		c.p("%s []%s", g.Name, g.TypeName)
+	}
+	for _, d := range ct.data {
+		c.p("%s []uint8", d.Name)
+	}
+	c.p("}")
+	c.p("")
+}
+
+func (f *Field) goType() string {
+	switch f.Kind {
+	case kindEnum:
+		return f.EnumName + "Enum"
+	case kindCharArray:
+		return fmt.Sprintf("[%d]byte", f.Length)
+	default:
+		return f.Primitive.goType
+	}
+}
+
+// loopVar returns a loop variable which does not shadow the receiver.
+func loopVar(r string) string {
+	if r == "i" {
+		return "j"
+	}
+	return "i"
+}
+
+func encodeBody(c *code, ct container) {
+	r := receiver(ct.typeName)
+	for _, f := range ct.fields {
+		writeField(c, r, f)
+	}
+
+	for _, g := range ct.groups {
+		c.p("if len(%s.%s) > math.MaxUint16 {", r, g.Name)
+		c.p(`return fmt.Errorf("%%w on %s.%s length (%%v > %%v)", ErrRangeCheck, len(%s.%s), math.MaxUint16)`,
+			r, g.Name, r, g.Name)
+		c.p("}")
+		c.p("")
+		writeValue(c, "Uint16", fmt.Sprint(g.BlockLength))
+		writeValue(c, "Uint16", fmt.Sprintf("uint16(len(%s.%s))", r, g.Name))
+		if g.DimensionSize > 4 {
+			writeValue(c, "Uint16", fmt.Sprint(len(g.Groups)))
+			writeValue(c, "Uint16", fmt.Sprint(len(g.Data)))
+		}
+		i := loopVar(r)
+		c.p("for %s := range %s.%s {", i, r, g.Name)
+		c.p("if err := %s.%s[%s].Encode(_m, _w); err != nil {", r, g.Name, i)
+		c.p("return err")
+		c.p("}")
+		c.p("}")
+		c.p("")
+	}
+
+	for _, d := range ct.data {
+		c.p("if len(%s.%s) > %s {", r, d.Name, d.Length.nullValue)
+		c.p(`return fmt.Errorf("%%w on %s.%s length (%%v > %%v)", ErrRangeCheck, len(%s.%s), %s)`,
+			r, d.Name, r, d.Name, d.Length.nullValue)
+		c.p("}")
+		c.p("")
+		writeValue(c, d.Length.method, fmt.Sprintf("%s(len(%s.%s))", d.Length.goType, r, d.Name))
+		c.p("if err := _m.WriteBytes(_w, %s.%s); err != nil {", r, d.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("")
+	}
+}
+
+func writeValue(c *code, method, value string) {
+	c.p("if err := _m.Write%s(_w, %s); err != nil {", method, value)
+	c.p("return err")
+	c.p("}")
+	c.p("")
+}
+
+func writeField(c *code, r string, f *Field) {
+	switch f.Kind {
+	case kindEnum:
+		c.p("if err := %s.%s.Encode(_m, _w); err != nil {", r, f.Name)
+	case kindCharArray:
+		c.p("if err := _m.WriteBytes(_w, %s.%s[:]); err != nil {", r, f.Name)
+	default:
+		c.p("if err := _m.Write%s(_w, %s.%s); err != nil {", f.Primitive.method, r, f.Name)
+	}
+	c.p("return err")
+	c.p("}")
+	c.p("")
+}
+
+func readField(c *code, r string, f *Field) {
+	if f.SinceVersion > 0 {
+		c.p("if actingVersion >= %s.%sSinceVersion() {", r, f.Name)
+	}
+
+	switch f.Kind {
+	case kindEnum:
+		c.p("if err := %s.%s.Decode(_m, _r); err != nil {", r, f.Name)
+	case kindCharArray:
+		c.p("if err := _m.ReadBytes(_r, %s.%s[:]); err != nil {", r, f.Name)
+	default:
+		c.p("if err := _m.Read%s(_r, &%s.%s); err != nil {", f.Primitive.method, r, f.Name)
+	}
+	c.p("return err")
+	c.p("}")
+
+	if f.SinceVersion > 0 {
+		c.p("} else {")
+		switch f.Kind {
+		case kindEnum:
+			c.p("%s.%s = %s.NullValue", r, f.Name, f.EnumName)
+		case kindCharArray:
+			c.p("%s.%s = %s{}", r, f.Name, f.goType())
+		default:
+			c.p("%s.%s = %s.%sNullValue()", r, f.Name, r, f.Name)
+		}
+		c.p("}")
+	}
+	c.p("")
+}
+
+func decodeBody(c *code, ct container) {
+	r := receiver(ct.typeName)
+	for _, f := range ct.fields {
+		readField(c, r, f)
+	}
+
+	c.p("if blockLength > %s.SbeBlockLength() {", r)
+	c.p("_, _ = io.CopyN(ioutil.Discard, _r, int64(blockLength-%s.SbeBlockLength()))", r)
+	c.p("}")
+	c.p("")
+
+	for _, g := range ct.groups {
+		if g.SinceVersion > 0 {
+			c.p("if actingVersion >= %d {", g.SinceVersion)
+		}
+		c.p("var %sBlockLength uint16", g.Name)
+		c.p("if err := _m.ReadUint16(_r, &%sBlockLength); err != nil {", g.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("")
+		c.p("var %sNumInGroup uint16", g.Name)
+		c.p("if err := _m.ReadUint16(_r, &%sNumInGroup); err != nil {", g.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("")
+		if g.DimensionSize > 4 {
+			c.p("// Discard numGroups and numVars.")
+			c.p("_, _ = io.CopyN(ioutil.Discard, _r, %d)", g.DimensionSize-4)
+			c.p("")
+		}
+		c.p("if cap(%s.%s) < int(%sNumInGroup) {", r, g.Name, g.Name)
+		c.p("%s.%s = make([]%s, %sNumInGroup)", r, g.Name, g.TypeName, g.Name)
+		c.p("}")
+		c.p("%s.%s = %s.%s[:%sNumInGroup]", r, g.Name, r, g.Name, g.Name)
+		i := loopVar(r)
+		c.p("for %s := range %s.%s {", i, r, g.Name)
+		c.p("if err := %s.%s[%s].DecodeVersion(_m, _r, actingVersion, uint(%sBlockLength)); err != nil {",
+			r, g.Name, i, g.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("}")
+		if g.SinceVersion > 0 {
+			c.p("} else {")
+			c.p("%s.%s = %s.%s[:0]", r, g.Name, r, g.Name)
+			c.p("}")
+		}
+		c.p("")
+	}
+
+	for _, d := range ct.data {
+		if d.SinceVersion > 0 {
+			c.p("if actingVersion >= %d {", d.SinceVersion)
+		}
+		c.p("var %sLength %s", d.Name, d.Length.goType)
+		c.p("if err := _m.Read%s(_r, &%sLength); err != nil {", d.Length.method, d.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("if cap(%s.%s) < int(%sLength) {", r, d.Name, d.Name)
+		c.p("%s.%s = make([]uint8, %sLength)", r, d.Name, d.Name)
+		c.p("}")
+		c.p("%s.%s = %s.%s[:%sLength]", r, d.Name, r, d.Name, d.Name)
+		c.p("if err := _m.ReadBytes(_r, %s.%s); err != nil {", r, d.Name)
+		c.p("return err")
+		c.p("}")
+		if d.SinceVersion > 0 {
+			c.p("} else {")
+			c.p("%s.%s = %s.%s[:0]", r, d.Name, r, d.Name)
+			c.p("}")
+		}
+		c.p("")
+	}
+}
+
+func readFieldBytes(c *code, r string, f *Field) {
+	if f.SinceVersion > 0 {
+		c.p("if actingVersion >= %s.%sSinceVersion() {", r, f.Name)
+	}
+
+	switch f.Kind {
+	case kindEnum:
+		c.p("%s.%s = %s(_d.%s())", r, f.Name, f.goType(), f.Primitive.method)
+	case kindCharArray:
+		c.p("_d.Bytes(%s.%s[:])", r, f.Name)
+	default:
+		c.p("%s.%s = _d.%s()", r, f.Name, f.Primitive.method)
+	}
+
+	if f.SinceVersion > 0 {
+		c.p("} else {")
+		switch f.Kind {
+		case kindEnum:
+			c.p("%s.%s = %s.NullValue", r, f.Name, f.EnumName)
+		case kindCharArray:
+			c.p("%s.%s = %s{}", r, f.Name, f.goType())
+		default:
+			c.p("%s.%s = %s.%sNullValue()", r, f.Name, r, f.Name)
+		}
+		c.p("}")
+	}
+}
+
+func decodeBytesBody(c *code, ct container) {
+	r := receiver(ct.typeName)
+	for _, f := range ct.fields {
+		readFieldBytes(c, r, f)
+	}
+	c.p("")
+
+	c.p("if blockLength > %s.SbeBlockLength() {", r)
+	c.p("_d.Skip(int(blockLength - %s.SbeBlockLength()))", r)
+	c.p("}")
+	c.p("")
+
+	for _, g := range ct.groups {
+		if g.SinceVersion > 0 {
+			c.p("if actingVersion >= %d {", g.SinceVersion)
+		}
+		c.p("%sBlockLength := _d.Uint16()", g.Name)
+		c.p("%sNumInGroup := _d.Uint16()", g.Name)
+		if g.DimensionSize > 4 {
+			c.p("_d.Skip(%d) // numGroups and numVars", g.DimensionSize-4)
+		}
+		c.p("if err := _d.Need(int(%sNumInGroup) * int(%sBlockLength)); err != nil {", g.Name, g.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("if cap(%s.%s) < int(%sNumInGroup) {", r, g.Name, g.Name)
+		c.p("%s.%s = make([]%s, %sNumInGroup)", r, g.Name, g.TypeName, g.Name)
+		c.p("}")
+		c.p("%s.%s = %s.%s[:%sNumInGroup]", r, g.Name, r, g.Name, g.Name)
+		i := loopVar(r)
+		c.p("for %s := range %s.%s {", i, r, g.Name)
+		c.p("if err := %s.%s[%s].DecodeBytes(_d, actingVersion, uint(%sBlockLength)); err != nil {",
+			r, g.Name, i, g.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("}")
+		if g.SinceVersion > 0 {
+			c.p("} else {")
+			c.p("%s.%s = %s.%s[:0]", r, g.Name, r, g.Name)
+			c.p("}")
+		}
+		c.p("")
+	}
+
+	for _, d := range ct.data {
+		if d.SinceVersion > 0 {
+			c.p("if actingVersion >= %d {", d.SinceVersion)
+		}
+		c.p("%sLength := _d.%s()", d.Name, d.Length.method)
+		c.p("if err := _d.Need(int(%sLength)); err != nil {", d.Name)
+		c.p("return err")
+		c.p("}")
+		c.p("if cap(%s.%s) < int(%sLength) {", r, d.Name, d.Name)
+		c.p("%s.%s = make([]uint8, %sLength)", r, d.Name, d.Name)
+		c.p("}")
+		c.p("%s.%s = %s.%s[:%sLength]", r, d.Name, r, d.Name, d.Name)
+		c.p("_d.Bytes(%s.%s)", r, d.Name)
+		if d.SinceVersion > 0 {
+			c.p("} else {")
+			c.p("%s.%s = %s.%s[:0]", r, d.Name, r, d.Name)
+			c.p("}")
+		}
+		c.p("")
+	}
+}
+
+func rangeCheck(c *code, ct container) {
+	r := receiver(ct.typeName)
+	c.p("func (%s *%s) RangeCheck() error {", r, ct.typeName)
+	for _, f := range ct.fields {
+		checkField(c, r, f)
+	}
+	for _, g := range ct.groups {
+		c.p("for _, prop := range %s.%s {", r, g.Name)
+		c.p("if err := prop.RangeCheck(); err != nil {")
+		c.p("return err")
+		c.p("}")
+		c.p("}")
+		c.p("")
+	}
+	c.p("return nil")
+	c.p("}")
+}
+
+func checkField(c *code, r string, f *Field) {
+	v := r + "." + f.Name
+	switch f.Kind {
+	case kindEnum:
+		c.p("if err := %s.RangeCheck(); err != nil {", v)
+		c.p("return err")
+		c.p("}")
+	case kindCharArray:
+		c.p("for idx := 0; idx < %d; idx++ {", f.Length)
+		c.p("if %s[idx] == byte(0) {", v)
+		c.p("break")
+		c.p("}")
+		c.p("if %s[idx] < %sMinValue() || %s[idx] > %sMaxValue() {", v, v, v, v)
+		c.p(`return fmt.Errorf("%%w on %s[%%d] (%%v < %%v > %%v)", ErrRangeCheck, idx, %sMinValue(), %s[idx], %sMaxValue())`,
+			v, v, v, v)
+		c.p("}")
+		c.p("}")
+	default:
+		outOfRange := fmt.Sprintf("%s < %sMinValue() || %s > %sMaxValue()", v, v, v, v)
+		if f.Optional {
+			c.p("if %s != %sNullValue() && (%s) {", v, v, outOfRange)
+		} else {
+			c.p("if %s {", outOfRange)
+		}
+		c.p(`return fmt.Errorf("%%w on %s (%%v < %%v > %%v)", ErrRangeCheck, %sMinValue(), %s, %sMaxValue())`,
+			v, v, v, v)
+		c.p("}")
+	}
+	c.p("")
+}
+
+func fieldHelpers(c *code, typeName string, fields []*Field) {
+	for _, f := range fields {
+		if f.Kind == kindEnum {
+			continue
+		}
+		goType := f.Primitive.goType
+		helper(c, typeName, f.Name+"MinValue", goType, f.MinValue)
+		helper(c, typeName, f.Name+"MaxValue", goType, f.MaxValue)
+		if f.Optional && f.Kind == kindPrimitive {
+			helper(c, typeName, f.Name+"NullValue", goType, f.NullValue)
+		}
+	}
+	for _, f := range fields {
+		if f.SinceVersion > 0 {
+			helper(c, typeName, f.Name+"SinceVersion", "uint16", fmt.Sprint(f.SinceVersion))
+		}
+	}
+}
+
+func helper(c *code, typeName, name, goType, value string) {
+	c.p("")
+	c.p("func (*%s) %s() %s {", typeName, name, goType)
+	c.p("return %s", value)
+	c.p("}")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `<?xml version="1.0" encoding="UTF-8"?>
+<sbe:messageSchema xmlns:sbe="http://fixprotocol.io/2016/sbe" package="test" id="7" version="2"
+                   byteOrder="littleEndian" headerType="messageHeader">
+    <types>
+        <composite name="messageHeader">
+            <type name="blockLength" primitiveType="uint16"/>
+            <type name="templateId" primitiveType="uint16"/>
+            <type name="schemaId" primitiveType="uint16"/>
+            <type name="version" primitiveType="uint16"/>
+        </composite>
+        <composite name="groupSizeEncoding">
+            <type name="blockLength" primitiveType="uint16"/>
+            <type name="numInGroup" primitiveType="uint16"/>
+        </composite>
+        <composite name="varString">
+            <type name="length" primitiveType="uint8"/>
+            <type name="varData" primitiveType="uint8" length="0"/>
+        </composite>
+        <enum name="side" encodingType="uint8">
+            <validValue name="buy">0</validValue>
+            <validValue name="sell">1</validValue>
+        </enum>
+    </types>
+    <sbe:message name="order" id="1">
+        <field name="orderId" id="1" type="uint64"/>
+        <field name="side" id="2" type="side"/>
+        <field name="price" id="3" type="double"/>
+        <field name="fee" id="4" type="double" sinceVersion="2"/>
+        <group name="fills" id="5" dimensionType="groupSizeEncoding">
+            <field name="amount" id="6" type="double"/>
+        </group>
+        <data name="note" id="7" type="varString"/>
+    </sbe:message>
+</sbe:messageSchema>
+`
+
+func TestGenerateMatchesPackage(t *testing.T) {
+	dir := filepath.Join("..", "..", "pkg", "multicast", "sbe")
+	f, err := os.Open(filepath.Join(dir, "deribit_multicast.xml"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	schema, err := ParseSchema(f)
+	require.NoError(t, err)
+
+	files, err := Generate(schema, "sbe", "deribit_multicast.xml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for name, src := range files {
+		onDisk, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err, name)
+		assert.Equal(t, string(onDisk), string(src), "%s is out of date, run go generate", name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	schema, err := ParseSchema(strings.NewReader(testSchema))
+	require.NoError(t, err)
+	require.EqualValues(t, 7, schema.ID)
+	require.EqualValues(t, 2, schema.Version)
+
+	files, err := Generate(schema, "test", "test.xml")
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	assert.ElementsMatch(t, []string{"schema.go", "message_header.go", "side.go", "order.go"}, names)
+
+	order := string(files["order.go"])
+	for _, want := range []string{
+		"// Code generated by sbegen from test.xml. DO NOT EDIT.",
+		"package test",
+		"type Order struct {",
+		"Fills   []OrderFills",
+		"Note    []uint8",
+		"func (o *Order) DecodeVersion(",
+		"if actingVersion >= o.FeeSinceVersion() {",
+		"func (*Order) FeeSinceVersion() uint16 {",
+		"func (*Order) FeeNullValue() float64 {",
+		"func (*Order) SbeTemplateId() (templateId uint16) {",
+		"func (o *Order) DecodeBytes(",
+		"o.Fee = _d.Float64()",
+		"if err := _d.Need(int(FillsNumInGroup) * int(FillsBlockLength)); err != nil {",
+	} {
+		assert.Contains(t, order, want)
+	}
+	assert.NotContains(t, order, "PriceSinceVersion")
+	assert.Contains(t, string(files["schema.go"]), "SchemaVersion uint16 = 2")
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		old, new string
+	}{
+		{`type="uint64"`, `type="uint128"`},
+		{`headerType="messageHeader"`, `headerType="header"`},
+		{`<type name="numInGroup" primitiveType="uint16"/>`, `<type name="numInGroup" primitiveType="uint32"/>`},
+		{`<validValue name="sell">1</validValue>`, `<validValue name="sell">x</validValue>`},
+	}
+
+	for _, test := range tests {
+		_, err := ParseSchema(strings.NewReader(strings.Replace(testSchema, test.old, test.new, 1)))
+		assert.ErrorIs(t, err, errInvalidSchema, test.new)
+	}
+}
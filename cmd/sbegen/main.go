@@ -0,0 +1,53 @@
+// Command sbegen generates the Go types of a SBE XML schema, e.g. the Deribit multicast types
+// of pkg/multicast/sbe:
+//
+//	go run ./cmd/sbegen -schema pkg/multicast/sbe/deribit_multicast.xml -out pkg/multicast/sbe
+//
+// It writes a file per composite, enum and message, the SbeGoMarshaller and ErrRangeCheck are
+// expected to be defined in the output package.
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+var (
+	schemaPath = flag.String("schema", "", "SBE XML schema")
+	outDir     = flag.String("out", ".", "Output directory")
+	pkg        = flag.String("package", "sbe", "Package name of the generated files")
+)
+
+func main() {
+	flag.Parse()
+	if *schemaPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(*schemaPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+
+	schema, err := ParseSchema(f)
+	if err != nil {
+		log.Fatalf("Fail to parse %s: %v", *schemaPath, err)
+	}
+
+	files, err := Generate(schema, *pkg, filepath.Base(*schemaPath))
+	if err != nil {
+		log.Fatalf("Fail to generate %s: %v", *schemaPath, err)
+	}
+
+	for name, src := range files {
+		// nolint:gosec
+		if err := ioutil.WriteFile(filepath.Join(*outDir, name), src, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
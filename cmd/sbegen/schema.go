@@ -0,0 +1,476 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var errInvalidSchema = errors.New("invalid schema")
+
+type xmlSchema struct {
+	XMLName    xml.Name     `xml:"messageSchema"`
+	ID         uint16       `xml:"id,attr"`
+	Version    uint16       `xml:"version,attr"`
+	HeaderType string       `xml:"headerType,attr"`
+	Types      []xmlTypes   `xml:"types"`
+	Messages   []xmlMessage `xml:"message"`
+}
+
+type xmlTypes struct {
+	Types      []xmlType      `xml:"type"`
+	Composites []xmlComposite `xml:"composite"`
+	Enums      []xmlEnum      `xml:"enum"`
+}
+
+type xmlType struct {
+	Name          string `xml:"name,attr"`
+	PrimitiveType string `xml:"primitiveType,attr"`
+	Length        *int   `xml:"length,attr"`
+	Presence      string `xml:"presence,attr"`
+	MinValue      string `xml:"minValue,attr"`
+	MaxValue      string `xml:"maxValue,attr"`
+	NullValue     string `xml:"nullValue,attr"`
+}
+
+type xmlComposite struct {
+	Name  string    `xml:"name,attr"`
+	Types []xmlType `xml:"type"`
+}
+
+type xmlEnum struct {
+	Name         string          `xml:"name,attr"`
+	EncodingType string          `xml:"encodingType,attr"`
+	ValidValues  []xmlValidValue `xml:"validValue"`
+}
+
+type xmlValidValue struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlMessage struct {
+	Name        string     `xml:"name,attr"`
+	ID          uint16     `xml:"id,attr"`
+	BlockLength int        `xml:"blockLength,attr"`
+	Fields      []xmlField `xml:"field"`
+	Groups      []xmlGroup `xml:"group"`
+	Data        []xmlField `xml:"data"`
+}
+
+type xmlGroup struct {
+	Name          string     `xml:"name,attr"`
+	DimensionType string     `xml:"dimensionType,attr"`
+	SinceVersion  uint16     `xml:"sinceVersion,attr"`
+	BlockLength   int        `xml:"blockLength,attr"`
+	Fields        []xmlField `xml:"field"`
+	Groups        []xmlGroup `xml:"group"`
+	Data          []xmlField `xml:"data"`
+}
+
+type xmlField struct {
+	Name         string `xml:"name,attr"`
+	Type         string `xml:"type,attr"`
+	Presence     string `xml:"presence,attr"`
+	SinceVersion uint16 `xml:"sinceVersion,attr"`
+}
+
+// primitive describes how a SBE primitive type is read, written and range checked.
+type primitive struct {
+	goType    string
+	size      int
+	method    string // suffix of the SbeGoMarshaller Read and Write methods
+	minValue  string
+	maxValue  string
+	nullValue string
+}
+
+// nolint:gochecknoglobals
+var primitives = map[string]primitive{
+	"char":   {"byte", 1, "Uint8", "byte(32)", "byte(126)", "byte(0)"},
+	"int8":   {"int8", 1, "Int8", "math.MinInt8 + 1", "math.MaxInt8", "math.MinInt8"},
+	"int16":  {"int16", 2, "Int16", "math.MinInt16 + 1", "math.MaxInt16", "math.MinInt16"},
+	"int32":  {"int32", 4, "Int32", "math.MinInt32 + 1", "math.MaxInt32", "math.MinInt32"},
+	"int64":  {"int64", 8, "Int64", "math.MinInt64 + 1", "math.MaxInt64", "math.MinInt64"},
+	"uint8":  {"uint8", 1, "Uint8", "0", "math.MaxUint8 - 1", "math.MaxUint8"},
+	"uint16": {"uint16", 2, "Uint16", "0", "math.MaxUint16 - 1", "math.MaxUint16"},
+	"uint32": {"uint32", 4, "Uint32", "0", "math.MaxUint32 - 1", "math.MaxUint32"},
+	"uint64": {"uint64", 8, "Uint64", "0", "math.MaxUint64 - 1", "math.MaxUint64"},
+	"float":  {"float32", 4, "Float32", "-math.MaxFloat32", "math.MaxFloat32", "float32(math.NaN())"},
+	"double": {"float64", 8, "Float64", "-math.MaxFloat64", "math.MaxFloat64", "math.NaN()"},
+}
+
+type fieldKind int
+
+const (
+	kindPrimitive fieldKind = iota
+	kindCharArray
+	kindEnum
+)
+
+// Schema is a parsed SBE schema with the Go names of its types.
+type Schema struct {
+	ID         uint16
+	Version    uint16
+	Header     *Composite
+	Composites []*Composite
+	Enums      []*Enum
+	Messages   []*Message
+}
+
+type Composite struct {
+	Name   string
+	Fields []*Field
+}
+
+type Enum struct {
+	Name      string
+	Primitive primitive
+	Values    []EnumValue
+	NullValue uint64
+}
+
+type EnumValue struct {
+	Name  string
+	Value uint64
+}
+
+type Field struct {
+	Name         string
+	Kind         fieldKind
+	Primitive    primitive
+	Length       int    // of a char array
+	EnumName     string // of an enum field
+	Optional     bool
+	SinceVersion uint16
+	MinValue     string
+	MaxValue     string
+	NullValue    string
+}
+
+type Group struct {
+	Name          string // of the field
+	TypeName      string
+	SinceVersion  uint16
+	BlockLength   int
+	DimensionSize int
+	Fields        []*Field
+	Groups        []*Group
+	Data          []*Data
+}
+
+type Data struct {
+	Name         string
+	Length       primitive
+	SinceVersion uint16
+}
+
+type Message struct {
+	Name        string
+	TemplateID  uint16
+	BlockLength int
+	Fields      []*Field
+	Groups      []*Group
+	Data        []*Data
+}
+
+// ParseSchema reads a SBE XML schema.
+func ParseSchema(r io.Reader) (*Schema, error) {
+	var raw xmlSchema
+	if err := xml.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	p := parser{
+		types:      make(map[string]xmlType),
+		composites: make(map[string]xmlComposite),
+		enums:      make(map[string]*Enum),
+	}
+	schema := &Schema{ID: raw.ID, Version: raw.Version}
+
+	for _, types := range raw.Types {
+		for _, t := range types.Types {
+			p.types[t.Name] = t
+		}
+		for _, c := range types.Composites {
+			p.composites[c.Name] = c
+		}
+		for _, e := range types.Enums {
+			enum, err := parseEnum(e)
+			if err != nil {
+				return nil, err
+			}
+			p.enums[e.Name] = enum
+			schema.Enums = append(schema.Enums, enum)
+		}
+	}
+
+	// The dimension and var data composites are inlined in the messages, the other ones are types.
+	inlined := make(map[string]bool)
+	for _, m := range raw.Messages {
+		markInlined(inlined, m.Groups, m.Data)
+	}
+	for _, types := range raw.Types {
+		for _, c := range types.Composites {
+			if inlined[c.Name] {
+				continue
+			}
+			composite, err := p.composite(c)
+			if err != nil {
+				return nil, err
+			}
+			schema.Composites = append(schema.Composites, composite)
+			if c.Name == raw.HeaderType {
+				schema.Header = composite
+			}
+		}
+	}
+	if schema.Header == nil {
+		return nil, fmt.Errorf("%w: header type %q not found", errInvalidSchema, raw.HeaderType)
+	}
+
+	for _, m := range raw.Messages {
+		message, err := p.message(m)
+		if err != nil {
+			return nil, err
+		}
+		schema.Messages = append(schema.Messages, message)
+	}
+	return schema, nil
+}
+
+func markInlined(inlined map[string]bool, groups []xmlGroup, data []xmlField) {
+	for _, g := range groups {
+		inlined[g.DimensionType] = true
+		markInlined(inlined, g.Groups, g.Data)
+	}
+	for _, d := range data {
+		inlined[d.Type] = true
+	}
+}
+
+type parser struct {
+	types      map[string]xmlType
+	composites map[string]xmlComposite
+	enums      map[string]*Enum
+}
+
+func parseEnum(e xmlEnum) (*Enum, error) {
+	prim, ok := primitives[e.EncodingType]
+	if !ok || prim.size != 1 {
+		return nil, fmt.Errorf("%w: unsupported encoding type %q of enum %s", errInvalidSchema, e.EncodingType, e.Name)
+	}
+
+	enum := &Enum{Name: exportedName(e.Name), Primitive: prim, NullValue: enumNullValue(e.EncodingType)}
+	for _, v := range e.ValidValues {
+		value := strings.TrimSpace(v.Value)
+		number, err := strconv.ParseUint(value, 10, 8)
+		if err != nil {
+			if e.EncodingType != "char" || len(value) != 1 {
+				return nil, fmt.Errorf("%w: value %q of %s.%s", errInvalidSchema, value, e.Name, v.Name)
+			}
+			number = uint64(value[0])
+		}
+		enum.Values = append(enum.Values, EnumValue{Name: exportedName(v.Name), Value: number})
+	}
+	return enum, nil
+}
+
+func enumNullValue(encodingType string) uint64 {
+	if encodingType == "char" {
+		return 0
+	}
+	return 255
+}
+
+func (p *parser) composite(c xmlComposite) (*Composite, error) {
+	composite := &Composite{Name: exportedName(c.Name)}
+	for _, t := range c.Types {
+		f, err := primitiveField(t.Name, t, "")
+		if err != nil {
+			return nil, err
+		}
+		composite.Fields = append(composite.Fields, f)
+	}
+	return composite, nil
+}
+
+func (p *parser) message(m xmlMessage) (*Message, error) {
+	name := exportedName(m.Name)
+	message := &Message{Name: name, TemplateID: m.ID}
+
+	var err error
+	if message.Fields, message.BlockLength, err = p.fields(m.Fields); err != nil {
+		return nil, err
+	}
+	if m.BlockLength > message.BlockLength {
+		message.BlockLength = m.BlockLength
+	}
+	if message.Groups, err = p.groups(name, m.Groups); err != nil {
+		return nil, err
+	}
+	if message.Data, err = p.data(m.Data); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+func (p *parser) groups(parent string, groups []xmlGroup) ([]*Group, error) {
+	result := make([]*Group, 0, len(groups))
+	for _, g := range groups {
+		// blockLength and numInGroup, optionally followed by numGroups and numVarDataFields
+		dimension, ok := p.composites[g.DimensionType]
+		if !ok || (len(dimension.Types) != 2 && len(dimension.Types) != 4) {
+			return nil, fmt.Errorf("%w: unsupported dimension type %q of group %s",
+				errInvalidSchema, g.DimensionType, g.Name)
+		}
+		for _, t := range dimension.Types {
+			if t.PrimitiveType != "uint16" {
+				return nil, fmt.Errorf("%w: unsupported dimension type %q of group %s",
+					errInvalidSchema, g.DimensionType, g.Name)
+			}
+		}
+		dimensionSize := 2 * len(dimension.Types)
+
+		group := &Group{
+			Name:          exportedName(g.Name),
+			TypeName:      parent + exportedName(g.Name),
+			SinceVersion:  g.SinceVersion,
+			DimensionSize: dimensionSize,
+		}
+
+		var err error
+		if group.Fields, group.BlockLength, err = p.fields(g.Fields); err != nil {
+			return nil, err
+		}
+		if g.BlockLength > group.BlockLength {
+			group.BlockLength = g.BlockLength
+		}
+		if group.Groups, err = p.groups(group.TypeName, g.Groups); err != nil {
+			return nil, err
+		}
+		if group.Data, err = p.data(g.Data); err != nil {
+			return nil, err
+		}
+		result = append(result, group)
+	}
+	return result, nil
+}
+
+func (p *parser) data(data []xmlField) ([]*Data, error) {
+	result := make([]*Data, 0, len(data))
+	for _, d := range data {
+		encoding, ok := p.composites[d.Type]
+		if !ok || len(encoding.Types) != 2 {
+			return nil, fmt.Errorf("%w: unsupported var data type %q of %s", errInvalidSchema, d.Type, d.Name)
+		}
+		length, ok := primitives[encoding.Types[0].PrimitiveType]
+		if !ok || strings.HasPrefix(length.goType, "int") || strings.HasPrefix(length.goType, "float") {
+			return nil, fmt.Errorf("%w: unsupported length type of %s", errInvalidSchema, d.Type)
+		}
+		result = append(result, &Data{Name: exportedName(d.Name), Length: length, SinceVersion: d.SinceVersion})
+	}
+	return result, nil
+}
+
+// fields resolves the types of fields and returns their encoded length.
+func (p *parser) fields(fields []xmlField) ([]*Field, int, error) {
+	result := make([]*Field, 0, len(fields))
+	blockLength := 0
+	for _, xf := range fields {
+		var (
+			f    *Field
+			size int
+			err  error
+		)
+		if enum, ok := p.enums[xf.Type]; ok {
+			f = &Field{Name: exportedName(xf.Name), Kind: kindEnum, EnumName: enum.Name, Primitive: enum.Primitive}
+			size = enum.Primitive.size
+		} else {
+			t, ok := p.types[xf.Type]
+			if !ok {
+				if _, ok := primitives[xf.Type]; !ok {
+					return nil, 0, fmt.Errorf("%w: unknown type %q of field %s", errInvalidSchema, xf.Type, xf.Name)
+				}
+				t = xmlType{Name: xf.Type, PrimitiveType: xf.Type}
+			}
+			if f, err = primitiveField(xf.Name, t, xf.Presence); err != nil {
+				return nil, 0, err
+			}
+			size = f.Primitive.size
+			if f.Kind == kindCharArray {
+				size *= f.Length
+			}
+		}
+
+		// fields added after the first version are missing from older messages
+		f.SinceVersion = xf.SinceVersion
+		if f.SinceVersion > 0 {
+			f.Optional = true
+		}
+		result = append(result, f)
+		blockLength += size
+	}
+	return result, blockLength, nil
+}
+
+func primitiveField(name string, t xmlType, presence string) (*Field, error) {
+	prim, ok := primitives[t.PrimitiveType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported primitive type %q of %s", errInvalidSchema, t.PrimitiveType, name)
+	}
+
+	f := &Field{
+		Name:      exportedName(name),
+		Kind:      kindPrimitive,
+		Primitive: prim,
+		Optional:  presence == "optional" || t.Presence == "optional",
+		MinValue:  valueOr(t.MinValue, prim.minValue),
+		MaxValue:  valueOr(t.MaxValue, prim.maxValue),
+		NullValue: valueOr(t.NullValue, prim.nullValue),
+	}
+	if t.Length != nil && *t.Length != 1 {
+		if t.PrimitiveType != "char" || *t.Length < 1 {
+			return nil, fmt.Errorf("%w: unsupported array type %q of %s", errInvalidSchema, t.Name, name)
+		}
+		f.Kind = kindCharArray
+		f.Length = *t.Length
+	}
+	return f, nil
+}
+
+func valueOr(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+// exportedName returns the Go name of a schema name, e.g. "instrumentId" is "InstrumentId".
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// fileName returns the name of the file of a type, e.g. "instrument_v2.go" for "InstrumentV2".
+func fileName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + ".go"
+}
@@ -0,0 +1,196 @@
+// Package api defines the request/response API surface shared by the Deribit
+// transports, so code written against it can use either websocket.Client or rest.Client.
+package api
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+// Client covers the Deribit methods available over both websocket and HTTP.
+// Connection scoped methods such as subscriptions, heartbeat and cancel on disconnect
+// are only available on websocket.Client.
+type Client interface {
+	// Account management
+	GetAnnouncements(ctx context.Context) ([]models.Announcement, error)
+	ChangeSubaccountName(ctx context.Context, params *models.ChangeSubaccountNameParams) (string, error)
+	CreateSubaccount(ctx context.Context) (models.Subaccount, error)
+	DisableTfaForSubaccount(ctx context.Context, params *models.DisableTfaForSubaccountParams) (string, error)
+	GetAccountSummary(
+		ctx context.Context,
+		params *models.GetAccountSummaryParams,
+	) (models.AccountSummary, error)
+	GetEmailLanguage(ctx context.Context) (string, error)
+	GetNewAnnouncements(ctx context.Context) ([]models.Announcement, error)
+	GetPosition(ctx context.Context, params *models.GetPositionParams) (models.Position, error)
+	GetPositions(ctx context.Context, params *models.GetPositionsParams) ([]models.Position, error)
+	GetSubaccounts(ctx context.Context, params *models.GetSubaccountsParams) ([]models.Subaccount, error)
+	SetAnnouncementAsRead(ctx context.Context, params *models.SetAnnouncementAsReadParams) (string, error)
+	SetEmailForSubaccount(ctx context.Context, params *models.SetEmailForSubaccountParams) (string, error)
+	SetEmailLanguage(ctx context.Context, params *models.SetEmailLanguageParams) (string, error)
+	SetPasswordForSubaccount(ctx context.Context, params *models.SetPasswordForSubaccountParams) (string, error)
+	ToggleNotificationsFromSubaccount(
+		ctx context.Context,
+		params *models.ToggleNotificationsFromSubaccountParams,
+	) (string, error)
+	ToggleSubaccountLogin(ctx context.Context, params *models.ToggleSubaccountLoginParams) (string, error)
+
+	// Market data
+	GetBookSummaryByCurrency(
+		ctx context.Context,
+		params *models.GetBookSummaryByCurrencyParams,
+	) ([]models.BookSummary, error)
+	GetBookSummaryByInstrument(
+		ctx context.Context,
+		params *models.GetBookSummaryByInstrumentParams,
+	) ([]models.BookSummary, error)
+	GetContractSize(
+		ctx context.Context,
+		params *models.GetContractSizeParams,
+	) (models.GetContractSizeResponse, error)
+	GetCurrencies(ctx context.Context) ([]models.Currency, error)
+	GetFundingChartData(
+		ctx context.Context,
+		params *models.GetFundingChartDataParams,
+	) (models.GetFundingChartDataResponse, error)
+	GetFundingRateHistory(
+		ctx context.Context,
+		params *models.GetFundingRateHistoryParams,
+	) (models.GetFundingRateHistoryResponse, error)
+	GetHistoricalVolatility(
+		ctx context.Context,
+		params *models.GetHistoricalVolatilityParams,
+	) (models.GetHistoricalVolatilityResponse, error)
+	GetIndex(ctx context.Context, params *models.GetIndexParams) (models.GetIndexResponse, error)
+	GetInstrument(ctx context.Context, params *models.GetInstrumentParams) (models.Instrument, error)
+	GetInstruments(ctx context.Context, params *models.GetInstrumentsParams) ([]models.Instrument, error)
+	GetLastSettlementsByCurrency(
+		ctx context.Context,
+		params *models.GetLastSettlementsByCurrencyParams,
+	) (models.GetLastSettlementsResponse, error)
+	GetLastSettlementsByInstrument(
+		ctx context.Context,
+		params *models.GetLastSettlementsByInstrumentParams,
+	) (models.GetLastSettlementsResponse, error)
+	GetLastTradesByCurrency(
+		ctx context.Context,
+		params *models.GetLastTradesByCurrencyParams,
+	) (models.GetLastTradesResponse, error)
+	GetLastTradesByCurrencyAndTime(
+		ctx context.Context,
+		params *models.GetLastTradesByCurrencyAndTimeParams,
+	) (models.GetLastTradesResponse, error)
+	GetLastTradesByInstrument(
+		ctx context.Context,
+		params *models.GetLastTradesByInstrumentParams,
+	) (models.GetLastTradesResponse, error)
+	GetLastTradesByInstrumentAndTime(
+		ctx context.Context,
+		params *models.GetLastTradesByInstrumentAndTimeParams,
+	) (models.GetLastTradesResponse, error)
+	GetOrderBook(ctx context.Context, params *models.GetOrderBookParams) (models.GetOrderBookResponse, error)
+	GetTradeVolumes(
+		ctx context.Context,
+		params *models.GetTradeVolumesParams,
+	) (models.GetTradeVolumesResponse, error)
+	GetTradingviewChartData(
+		ctx context.Context,
+		params *models.GetTradingviewChartDataParams,
+	) (models.GetTradingviewChartDataResponse, error)
+	Ticker(ctx context.Context, params *models.TickerParams) (models.TickerResponse, error)
+
+	// Supporting
+	GetTime(ctx context.Context) (int64, error)
+	Test(ctx context.Context) (models.TestResponse, error)
+
+	// Trading
+	Buy(ctx context.Context, params *models.BuyParams) (models.BuyResponse, error)
+	Sell(ctx context.Context, params *models.SellParams) (models.SellResponse, error)
+	Edit(ctx context.Context, params *models.EditParams) (models.EditResponse, error)
+	EditByLabel(ctx context.Context, params *models.EditParams) (models.EditResponse, error)
+	Cancel(ctx context.Context, params *models.CancelParams) (models.Order, error)
+	CancelAll(ctx context.Context) (uint, error)
+	CancelAllByCurrency(ctx context.Context, params *models.CancelAllByCurrencyParams) (uint, error)
+	CancelAllByInstrument(ctx context.Context, params *models.CancelAllByInstrumentParams) (uint, error)
+	CancelAllByLabel(ctx context.Context, params *models.CancelAllByInstrumentParams) (uint, error)
+	ClosePosition(ctx context.Context, params *models.ClosePositionParams) (models.ClosePositionResponse, error)
+	GetMargins(ctx context.Context, params *models.GetMarginsParams) (models.GetMarginsResponse, error)
+	GetOpenOrdersByCurrency(
+		ctx context.Context,
+		params *models.GetOpenOrdersByCurrencyParams,
+	) ([]models.Order, error)
+	GetOpenOrdersByInstrument(
+		ctx context.Context,
+		params *models.GetOpenOrdersByInstrumentParams,
+	) ([]models.Order, error)
+	GetOrderHistoryByCurrency(
+		ctx context.Context,
+		params *models.GetOrderHistoryByCurrencyParams,
+	) ([]models.Order, error)
+	GetOrderHistoryByInstrument(
+		ctx context.Context,
+		params *models.GetOrderHistoryByInstrumentParams,
+	) ([]models.Order, error)
+	GetOrderMarginByIDs(
+		ctx context.Context,
+		params *models.GetOrderMarginByIDsParams,
+	) (models.GetOrderMarginByIDsResponse, error)
+	GetOrderState(ctx context.Context, params *models.GetOrderStateParams) (models.Order, error)
+	GetUserTradesByCurrency(
+		ctx context.Context,
+		params *models.GetUserTradesByCurrencyParams,
+	) (models.GetUserTradesResponse, error)
+	GetUserTradesByCurrencyAndTime(
+		ctx context.Context,
+		params *models.GetUserTradesByCurrencyAndTimeParams,
+	) (models.GetUserTradesResponse, error)
+	GetUserTradesByInstrument(
+		ctx context.Context,
+		params *models.GetUserTradesByInstrumentParams,
+	) (models.GetUserTradesResponse, error)
+	GetUserTradesByInstrumentAndTime(
+		ctx context.Context,
+		params *models.GetUserTradesByInstrumentAndTimeParams,
+	) (models.GetUserTradesResponse, error)
+	GetUserTradesByOrder(
+		ctx context.Context,
+		params *models.GetUserTradesByOrderParams,
+	) (models.GetUserTradesResponse, error)
+	GetSettlementHistoryByInstrument(
+		ctx context.Context,
+		params *models.GetSettlementHistoryByInstrumentParams,
+	) (models.GetSettlementHistoryResponse, error)
+	GetSettlementHistoryByCurrency(
+		ctx context.Context,
+		params *models.GetSettlementHistoryByCurrencyParams,
+	) (models.GetSettlementHistoryResponse, error)
+
+	// Wallet
+	CancelTransferByID(ctx context.Context, params *models.CancelTransferByIDParams) (models.Transfer, error)
+	CancelWithdrawal(ctx context.Context, params *models.CancelWithdrawalParams) (models.Withdrawal, error)
+	CreateDepositAddress(
+		ctx context.Context,
+		params *models.CreateDepositAddressParams,
+	) (models.DepositAddress, error)
+	GetCurrentDepositAddress(
+		ctx context.Context,
+		params *models.GetCurrentDepositAddressParams,
+	) (models.DepositAddress, error)
+	GetDeposits(ctx context.Context, params *models.GetDepositsParams) (models.GetDepositsResponse, error)
+	GetTransfers(ctx context.Context, params *models.GetTransfersParams) (models.GetTransfersResponse, error)
+	GetWithdrawals(ctx context.Context, params *models.GetWithdrawalsParams) ([]models.Withdrawal, error)
+	SubmitTransferBetweenSubaccounts(
+		ctx context.Context,
+		params *models.SubmitTransferBetweenSubaccountsParams,
+	) (models.Transfer, error)
+	SubmitTransferToSubaccount(
+		ctx context.Context,
+		params *models.SubmitTransferToSubaccountParams,
+	) (models.Transfer, error)
+	SubmitTransferToUser(
+		ctx context.Context,
+		params *models.SubmitTransferToUserParams,
+	) (models.Transfer, error)
+	Withdraw(ctx context.Context, params *models.WithdrawParams) (models.Withdrawal, error)
+}
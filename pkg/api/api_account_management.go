@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+func (m *Methods) GetAnnouncements(
+	ctx context.Context,
+) (result []models.Announcement, err error) {
+	err = m.caller.Call(ctx, "public/get_announcements", nil, &result)
+	return
+}
+
+func (m *Methods) ChangeSubaccountName(
+	ctx context.Context,
+	params *models.ChangeSubaccountNameParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/change_subaccount_name", params, &result)
+	return
+}
+
+func (m *Methods) CreateSubaccount(ctx context.Context) (result models.Subaccount, err error) {
+	err = m.caller.Call(ctx, "private/create_subaccount", nil, &result)
+	return
+}
+
+func (m *Methods) DisableTfaForSubaccount(
+	ctx context.Context,
+	params *models.DisableTfaForSubaccountParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/disable_tfa_for_subaccount", params, &result)
+	return
+}
+
+func (m *Methods) GetAccountSummary(
+	ctx context.Context,
+	params *models.GetAccountSummaryParams,
+) (result models.AccountSummary, err error) {
+	err = m.caller.Call(ctx, "private/get_account_summary", params, &result)
+	return
+}
+
+func (m *Methods) GetEmailLanguage(ctx context.Context) (result string, err error) {
+	err = m.caller.Call(ctx, "private/get_email_language", nil, &result)
+	return
+}
+
+func (m *Methods) GetNewAnnouncements(
+	ctx context.Context,
+) (result []models.Announcement, err error) {
+	err = m.caller.Call(ctx, "private/get_new_announcements", nil, &result)
+	return
+}
+
+func (m *Methods) GetPosition(
+	ctx context.Context,
+	params *models.GetPositionParams,
+) (result models.Position, err error) {
+	err = m.caller.Call(ctx, "private/get_position", params, &result)
+	return
+}
+
+func (m *Methods) GetPositions(
+	ctx context.Context,
+	params *models.GetPositionsParams,
+) (result []models.Position, err error) {
+	err = m.caller.Call(ctx, "private/get_positions", params, &result)
+	return
+}
+
+func (m *Methods) GetSubaccounts(
+	ctx context.Context,
+	params *models.GetSubaccountsParams,
+) (result []models.Subaccount, err error) {
+	err = m.caller.Call(ctx, "private/get_subaccounts", params, &result)
+	return
+}
+
+func (m *Methods) SetAnnouncementAsRead(
+	ctx context.Context,
+	params *models.SetAnnouncementAsReadParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/set_announcement_as_read", params, &result)
+	return
+}
+
+func (m *Methods) SetEmailForSubaccount(
+	ctx context.Context,
+	params *models.SetEmailForSubaccountParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/set_email_for_subaccount", params, &result)
+	return
+}
+
+func (m *Methods) SetEmailLanguage(
+	ctx context.Context,
+	params *models.SetEmailLanguageParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/set_email_language", params, &result)
+	return
+}
+
+func (m *Methods) SetPasswordForSubaccount(
+	ctx context.Context,
+	params *models.SetPasswordForSubaccountParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/set_password_for_subaccount", params, &result)
+	return
+}
+
+func (m *Methods) ToggleNotificationsFromSubaccount(
+	ctx context.Context,
+	params *models.ToggleNotificationsFromSubaccountParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/toggle_notifications_from_subaccount", params, &result)
+	return
+}
+
+func (m *Methods) ToggleSubaccountLogin(
+	ctx context.Context,
+	params *models.ToggleSubaccountLoginParams,
+) (result string, err error) {
+	err = m.caller.Call(ctx, "private/toggle_subaccount_login", params, &result)
+	return
+}
@@ -1,4 +1,4 @@
-package websocket
+package api
 
 import (
 	"context"
@@ -6,151 +6,159 @@ import (
 	"github.com/KyberNetwork/deribit-api/pkg/models"
 )
 
-func (c *Client) GetBookSummaryByCurrency(
+func (m *Methods) GetBookSummaryByCurrency(
 	ctx context.Context,
 	params *models.GetBookSummaryByCurrencyParams,
 ) (result []models.BookSummary, err error) {
-	err = c.Call(ctx, "public/get_book_summary_by_currency", params, &result)
+	err = m.caller.Call(ctx, "public/get_book_summary_by_currency", params, &result)
 	return
 }
 
-func (c *Client) GetBookSummaryByInstrument(
+func (m *Methods) GetBookSummaryByInstrument(
 	ctx context.Context,
 	params *models.GetBookSummaryByInstrumentParams,
 ) (result []models.BookSummary, err error) {
-	err = c.Call(ctx, "public/get_book_summary_by_instrument", params, &result)
+	err = m.caller.Call(ctx, "public/get_book_summary_by_instrument", params, &result)
 	return
 }
 
-func (c *Client) GetContractSize(
+func (m *Methods) GetContractSize(
 	ctx context.Context,
 	params *models.GetContractSizeParams,
 ) (result models.GetContractSizeResponse, err error) {
-	err = c.Call(ctx, "public/get_contract_size", params, &result)
+	err = m.caller.Call(ctx, "public/get_contract_size", params, &result)
 	return
 }
 
-func (c *Client) GetCurrencies(ctx context.Context) (result []models.Currency, err error) {
-	err = c.Call(ctx, "public/get_currencies", nil, &result)
+func (m *Methods) GetCurrencies(ctx context.Context) (result []models.Currency, err error) {
+	err = m.caller.Call(ctx, "public/get_currencies", nil, &result)
 	return
 }
 
-func (c *Client) GetFundingChartData(
+func (m *Methods) GetFundingChartData(
 	ctx context.Context,
 	params *models.GetFundingChartDataParams,
 ) (result models.GetFundingChartDataResponse, err error) {
-	err = c.Call(ctx, "public/get_funding_chart_data", params, &result)
+	err = m.caller.Call(ctx, "public/get_funding_chart_data", params, &result)
 	return
 }
 
-func (c *Client) GetHistoricalVolatility(
+func (m *Methods) GetFundingRateHistory(
+	ctx context.Context,
+	params *models.GetFundingRateHistoryParams,
+) (result models.GetFundingRateHistoryResponse, err error) {
+	err = m.caller.Call(ctx, "public/get_funding_rate_history", params, &result)
+	return
+}
+
+func (m *Methods) GetHistoricalVolatility(
 	ctx context.Context,
 	params *models.GetHistoricalVolatilityParams,
 ) (result models.GetHistoricalVolatilityResponse, err error) {
-	err = c.Call(ctx, "public/get_historical_volatility", params, &result)
+	err = m.caller.Call(ctx, "public/get_historical_volatility", params, &result)
 	return
 }
 
-func (c *Client) GetIndex(
+func (m *Methods) GetIndex(
 	ctx context.Context,
 	params *models.GetIndexParams,
 ) (result models.GetIndexResponse, err error) {
-	err = c.Call(ctx, "public/get_index", params, &result)
+	err = m.caller.Call(ctx, "public/get_index", params, &result)
 	return
 }
 
-func (c *Client) GetInstrument(
+func (m *Methods) GetInstrument(
 	ctx context.Context,
 	params *models.GetInstrumentParams,
 ) (result models.Instrument, err error) {
-	err = c.Call(ctx, "public/get_instrument", params, &result)
+	err = m.caller.Call(ctx, "public/get_instrument", params, &result)
 	return
 }
 
-func (c *Client) GetInstruments(
+func (m *Methods) GetInstruments(
 	ctx context.Context,
 	params *models.GetInstrumentsParams,
 ) (result []models.Instrument, err error) {
-	err = c.Call(ctx, "public/get_instruments", params, &result)
+	err = m.caller.Call(ctx, "public/get_instruments", params, &result)
 	return
 }
 
-func (c *Client) GetLastSettlementsByCurrency(
+func (m *Methods) GetLastSettlementsByCurrency(
 	ctx context.Context,
 	params *models.GetLastSettlementsByCurrencyParams,
 ) (result models.GetLastSettlementsResponse, err error) {
-	err = c.Call(ctx, "public/get_last_settlements_by_currency", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_settlements_by_currency", params, &result)
 	return
 }
 
-func (c *Client) GetLastSettlementsByInstrument(
+func (m *Methods) GetLastSettlementsByInstrument(
 	ctx context.Context,
 	params *models.GetLastSettlementsByInstrumentParams,
 ) (result models.GetLastSettlementsResponse, err error) {
-	err = c.Call(ctx, "public/get_last_settlements_by_instrument", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_settlements_by_instrument", params, &result)
 	return
 }
 
-func (c *Client) GetLastTradesByCurrency(
+func (m *Methods) GetLastTradesByCurrency(
 	ctx context.Context,
 	params *models.GetLastTradesByCurrencyParams,
 ) (result models.GetLastTradesResponse, err error) {
-	err = c.Call(ctx, "public/get_last_trades_by_currency", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_trades_by_currency", params, &result)
 	return
 }
 
-func (c *Client) GetLastTradesByCurrencyAndTime(
+func (m *Methods) GetLastTradesByCurrencyAndTime(
 	ctx context.Context,
 	params *models.GetLastTradesByCurrencyAndTimeParams,
 ) (result models.GetLastTradesResponse, err error) {
-	err = c.Call(ctx, "public/get_last_trades_by_currency_and_time", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_trades_by_currency_and_time", params, &result)
 	return
 }
 
-func (c *Client) GetLastTradesByInstrument(
+func (m *Methods) GetLastTradesByInstrument(
 	ctx context.Context,
 	params *models.GetLastTradesByInstrumentParams,
 ) (result models.GetLastTradesResponse, err error) {
-	err = c.Call(ctx, "public/get_last_trades_by_instrument", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_trades_by_instrument", params, &result)
 	return
 }
 
-func (c *Client) GetLastTradesByInstrumentAndTime(
+func (m *Methods) GetLastTradesByInstrumentAndTime(
 	ctx context.Context,
 	params *models.GetLastTradesByInstrumentAndTimeParams,
 ) (result models.GetLastTradesResponse, err error) {
-	err = c.Call(ctx, "public/get_last_trades_by_instrument_and_time", params, &result)
+	err = m.caller.Call(ctx, "public/get_last_trades_by_instrument_and_time", params, &result)
 	return
 }
 
-func (c *Client) GetOrderBook(
+func (m *Methods) GetOrderBook(
 	ctx context.Context,
 	params *models.GetOrderBookParams,
 ) (result models.GetOrderBookResponse, err error) {
-	err = c.Call(ctx, "public/get_order_book", params, &result)
+	err = m.caller.Call(ctx, "public/get_order_book", params, &result)
 	return
 }
 
-func (c *Client) GetTradeVolumes(
+func (m *Methods) GetTradeVolumes(
 	ctx context.Context,
 	params *models.GetTradeVolumesParams,
 ) (result models.GetTradeVolumesResponse, err error) {
-	err = c.Call(ctx, "public/get_trade_volumes", params, &result)
+	err = m.caller.Call(ctx, "public/get_trade_volumes", params, &result)
 	return
 }
 
-func (c *Client) GetTradingviewChartData(
+func (m *Methods) GetTradingviewChartData(
 	ctx context.Context,
 	params *models.GetTradingviewChartDataParams,
 ) (result models.GetTradingviewChartDataResponse, err error) {
-	err = c.Call(ctx, "public/get_tradingview_chart_data", params, &result)
+	err = m.caller.Call(ctx, "public/get_tradingview_chart_data", params, &result)
 	return
 }
 
-func (c *Client) Ticker(
+func (m *Methods) Ticker(
 	ctx context.Context,
 	params *models.TickerParams,
 ) (result models.TickerResponse, err error) {
-	err = c.Call(ctx, "public/ticker", params, &result)
+	err = m.caller.Call(ctx, "public/ticker", params, &result)
 	return
 }
@@ -0,0 +1,17 @@
+package api
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+func (m *Methods) GetTime(ctx context.Context) (result int64, err error) {
+	err = m.caller.Call(ctx, "public/get_time", nil, &result)
+	return
+}
+
+func (m *Methods) Test(ctx context.Context) (result models.TestResponse, err error) {
+	err = m.caller.Call(ctx, "public/test", nil, &result)
+	return
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+func (m *Methods) Buy(ctx context.Context, params *models.BuyParams) (result models.BuyResponse, err error) {
+	err = m.caller.Call(ctx, "private/buy", params, &result)
+	return
+}
+
+func (m *Methods) Sell(ctx context.Context, params *models.SellParams) (result models.SellResponse, err error) {
+	err = m.caller.Call(ctx, "private/sell", params, &result)
+	return
+}
+
+func (m *Methods) Edit(ctx context.Context, params *models.EditParams) (result models.EditResponse, err error) {
+	err = m.caller.Call(ctx, "private/edit", params, &result)
+	return
+}
+
+func (m *Methods) EditByLabel(ctx context.Context, params *models.EditParams) (result models.EditResponse, err error) {
+	err = m.caller.Call(ctx, "private/edit", params, &result)
+	return
+}
+
+func (m *Methods) Cancel(ctx context.Context, params *models.CancelParams) (result models.Order, err error) {
+	err = m.caller.Call(ctx, "private/cancel", params, &result)
+	return
+}
+
+func (m *Methods) CancelAll(ctx context.Context) (result uint, err error) {
+	err = m.caller.Call(ctx, "private/cancel_all", nil, &result)
+	return
+}
+
+func (m *Methods) CancelAllByCurrency(
+	ctx context.Context,
+	params *models.CancelAllByCurrencyParams,
+) (result uint, err error) {
+	err = m.caller.Call(ctx, "private/cancel_all_by_currency", params, &result)
+	return
+}
+
+func (m *Methods) CancelAllByInstrument(
+	ctx context.Context,
+	params *models.CancelAllByInstrumentParams,
+) (result uint, err error) {
+	err = m.caller.Call(ctx, "private/cancel_all_by_instrument", params, &result)
+	return
+}
+
+func (m *Methods) CancelAllByLabel(
+	ctx context.Context,
+	params *models.CancelAllByInstrumentParams,
+) (result uint, err error) {
+	err = m.caller.Call(ctx, "/private/cancel_by_label", params, &result)
+	return
+}
+
+func (m *Methods) ClosePosition(
+	ctx context.Context,
+	params *models.ClosePositionParams,
+) (result models.ClosePositionResponse, err error) {
+	err = m.caller.Call(ctx, "private/close_position", params, &result)
+	return
+}
+
+func (m *Methods) GetMargins(
+	ctx context.Context,
+	params *models.GetMarginsParams,
+) (result models.GetMarginsResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_margins", params, &result)
+	return
+}
+
+func (m *Methods) GetOpenOrdersByCurrency(
+	ctx context.Context,
+	params *models.GetOpenOrdersByCurrencyParams,
+) (result []models.Order, err error) {
+	err = m.caller.Call(ctx, "private/get_open_orders_by_currency", params, &result)
+	return
+}
+
+func (m *Methods) GetOpenOrdersByInstrument(
+	ctx context.Context,
+	params *models.GetOpenOrdersByInstrumentParams,
+) (result []models.Order, err error) {
+	err = m.caller.Call(ctx, "private/get_open_orders_by_instrument", params, &result)
+	return
+}
+
+func (m *Methods) GetOrderHistoryByCurrency(
+	ctx context.Context,
+	params *models.GetOrderHistoryByCurrencyParams,
+) (result []models.Order, err error) {
+	err = m.caller.Call(ctx, "private/get_order_history_by_currency", params, &result)
+	return
+}
+
+func (m *Methods) GetOrderHistoryByInstrument(
+	ctx context.Context,
+	params *models.GetOrderHistoryByInstrumentParams,
+) (result []models.Order, err error) {
+	err = m.caller.Call(ctx, "private/get_order_history_by_instrument", params, &result)
+	return
+}
+
+func (m *Methods) GetOrderMarginByIDs(
+	ctx context.Context,
+	params *models.GetOrderMarginByIDsParams,
+) (result models.GetOrderMarginByIDsResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_order_margin_by_ids", params, &result)
+	return
+}
+
+func (m *Methods) GetOrderState(
+	ctx context.Context,
+	params *models.GetOrderStateParams,
+) (result models.Order, err error) {
+	err = m.caller.Call(ctx, "private/get_order_state", params, &result)
+	return
+}
+
+func (m *Methods) GetUserTradesByCurrency(
+	ctx context.Context,
+	params *models.GetUserTradesByCurrencyParams,
+) (result models.GetUserTradesResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_user_trades_by_currency", params, &result)
+	return
+}
+
+func (m *Methods) GetUserTradesByCurrencyAndTime(
+	ctx context.Context,
+	params *models.GetUserTradesByCurrencyAndTimeParams,
+) (result models.GetUserTradesResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_user_trades_by_currency_and_time", params, &result)
+	return
+}
+
+func (m *Methods) GetUserTradesByInstrument(
+	ctx context.Context,
+	params *models.GetUserTradesByInstrumentParams,
+) (result models.GetUserTradesResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_user_trades_by_instrument", params, &result)
+	return
+}
+
+func (m *Methods) GetUserTradesByInstrumentAndTime(
+	ctx context.Context,
+	params *models.GetUserTradesByInstrumentAndTimeParams,
+) (result models.GetUserTradesResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_user_trades_by_instrument_and_time", params, &result)
+	return
+}
+
+func (m *Methods) GetUserTradesByOrder(
+	ctx context.Context,
+	params *models.GetUserTradesByOrderParams,
+) (result models.GetUserTradesResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_user_trades_by_order", params, &result)
+	return
+}
+
+func (m *Methods) GetSettlementHistoryByInstrument(
+	ctx context.Context,
+	params *models.GetSettlementHistoryByInstrumentParams,
+) (result models.GetSettlementHistoryResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_settlement_history_by_instrument", params, &result)
+	return
+}
+
+func (m *Methods) GetSettlementHistoryByCurrency(
+	ctx context.Context,
+	params *models.GetSettlementHistoryByCurrencyParams,
+) (result models.GetSettlementHistoryResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_settlement_history_by_currency", params, &result)
+	return
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+func (m *Methods) CancelTransferByID(
+	ctx context.Context,
+	params *models.CancelTransferByIDParams,
+) (result models.Transfer, err error) {
+	err = m.caller.Call(ctx, "private/cancel_transfer_by_id", params, &result)
+	return
+}
+
+func (m *Methods) CancelWithdrawal(
+	ctx context.Context,
+	params *models.CancelWithdrawalParams,
+) (result models.Withdrawal, err error) {
+	err = m.caller.Call(ctx, "private/cancel_withdrawal", params, &result)
+	return
+}
+
+func (m *Methods) CreateDepositAddress(
+	ctx context.Context,
+	params *models.CreateDepositAddressParams,
+) (result models.DepositAddress, err error) {
+	err = m.caller.Call(ctx, "private/create_deposit_address", params, &result)
+	return
+}
+
+func (m *Methods) GetCurrentDepositAddress(
+	ctx context.Context,
+	params *models.GetCurrentDepositAddressParams,
+) (result models.DepositAddress, err error) {
+	err = m.caller.Call(ctx, "private/get_current_deposit_address", params, &result)
+	return
+}
+
+func (m *Methods) GetDeposits(
+	ctx context.Context,
+	params *models.GetDepositsParams,
+) (result models.GetDepositsResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_deposits", params, &result)
+	return
+}
+
+func (m *Methods) GetTransfers(
+	ctx context.Context,
+	params *models.GetTransfersParams,
+) (result models.GetTransfersResponse, err error) {
+	err = m.caller.Call(ctx, "private/get_transfers", params, &result)
+	return
+}
+
+func (m *Methods) GetWithdrawals(
+	ctx context.Context,
+	params *models.GetWithdrawalsParams,
+) (result []models.Withdrawal, err error) {
+	err = m.caller.Call(ctx, "private/get_withdrawals", params, &result)
+	return
+}
+
+func (m *Methods) SubmitTransferBetweenSubaccounts(
+	ctx context.Context,
+	params *models.SubmitTransferBetweenSubaccountsParams,
+) (result models.Transfer, err error) {
+	err = m.caller.Call(ctx, "private/submit_transfer_between_subaccounts", params, &result)
+	return
+}
+
+func (m *Methods) SubmitTransferToSubaccount(
+	ctx context.Context,
+	params *models.SubmitTransferToSubaccountParams,
+) (result models.Transfer, err error) {
+	err = m.caller.Call(ctx, "private/submit_transfer_to_subaccount", params, &result)
+	return
+}
+
+func (m *Methods) SubmitTransferToUser(
+	ctx context.Context,
+	params *models.SubmitTransferToUserParams,
+) (result models.Transfer, err error) {
+	err = m.caller.Call(ctx, "private/submit_transfer_to_user", params, &result)
+	return
+}
+
+func (m *Methods) Withdraw(
+	ctx context.Context,
+	params *models.WithdrawParams,
+) (result models.Withdrawal, err error) {
+	err = m.caller.Call(ctx, "private/withdraw", params, &result)
+	return
+}
@@ -0,0 +1,21 @@
+package api
+
+import "context"
+
+// Caller issues a Deribit JSONRPC call over a transport, e.g. websocket.Client or rest.Client.
+type Caller interface {
+	Call(ctx context.Context, method string, params interface{}, result interface{}) error
+}
+
+// Methods implements Client over a Caller. The transports embed it, so the methods are
+// written once whatever carries the calls.
+type Methods struct {
+	caller Caller
+}
+
+var _ Client = (*Methods)(nil)
+
+// NewMethods creates a new Methods instance calling through caller.
+func NewMethods(caller Caller) Methods {
+	return Methods{caller: caller}
+}
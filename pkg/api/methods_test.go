@@ -0,0 +1,39 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingCaller struct {
+	method string
+	params interface{}
+}
+
+func (c *recordingCaller) Call(_ context.Context, method string, params interface{}, result interface{}) error {
+	c.method, c.params = method, params
+	if ts, ok := result.(*int64); ok {
+		*ts = 1660000000000
+	}
+	return nil
+}
+
+func TestMethods(t *testing.T) {
+	caller := &recordingCaller{}
+	m := NewMethods(caller)
+
+	now, err := m.GetTime(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1660000000000), now)
+	assert.Equal(t, "public/get_time", caller.method)
+
+	params := &models.GetPositionParams{InstrumentName: "BTC-PERPETUAL"}
+	_, err = m.GetPosition(context.Background(), params)
+	require.NoError(t, err)
+	assert.Equal(t, "private/get_position", caller.method)
+	assert.Same(t, params, caller.params)
+}
@@ -0,0 +1,231 @@
+package clocksync
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval   = time.Minute
+	defaultWindowSize = 16
+	defaultTimeout    = 5 * time.Second
+	// bestSamples is the number of lowest-RTT samples used to estimate the offset.
+	bestSamples = 4
+)
+
+// TimeGetter returns Deribit server time in milliseconds, e.g. websocket.Client.
+type TimeGetter interface {
+	GetTime(ctx context.Context) (int64, error)
+}
+
+type Config struct {
+	// Interval between two samples. Defaults to one minute.
+	Interval time.Duration
+	// WindowSize is the number of recent samples kept for estimation.
+	WindowSize int
+	// Timeout of a single GetTime call.
+	Timeout time.Duration
+}
+
+type sample struct {
+	local  time.Time     // local midpoint of the request
+	offset time.Duration // server - local at local
+	rtt    time.Duration
+}
+
+// Clock estimates the offset and drift of Deribit server clock relative to the
+// local clock by sampling public/get_time.
+type Clock struct {
+	log        *zap.SugaredLogger
+	getter     TimeGetter
+	interval   time.Duration
+	windowSize int
+	timeout    time.Duration
+	now        func() time.Time
+
+	mu      sync.RWMutex
+	samples []sample
+	synced  bool
+	ref     time.Time
+	offset  time.Duration
+	drift   float64 // seconds of offset change per second of local time
+}
+
+// New creates a new Clock instance.
+func New(getter TimeGetter, cfg Config) *Clock {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultInterval
+	}
+	if cfg.WindowSize <= 0 {
+		cfg.WindowSize = defaultWindowSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	return &Clock{
+		log:        zap.S(),
+		getter:     getter,
+		interval:   cfg.Interval,
+		windowSize: cfg.WindowSize,
+		timeout:    cfg.Timeout,
+		now:        time.Now,
+	}
+}
+
+// Start takes a first sample and keeps sampling every interval until ctx is done.
+func (c *Clock) Start(ctx context.Context) error {
+	if err := c.Sync(ctx); err != nil {
+		return err
+	}
+
+	go func() {
+		t := time.NewTicker(c.interval)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if err := c.Sync(ctx); err != nil {
+					c.log.Warnw("Fail to sync server clock", "error", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Sync takes one sample of the server clock and updates the estimation.
+// As in NTP, the server timestamp is assumed to be taken halfway through the round trip.
+func (c *Clock) Sync(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	t0 := c.now()
+	serverMs, err := c.getter.GetTime(ctx)
+	t3 := c.now()
+	if err != nil {
+		return err
+	}
+
+	rtt := t3.Sub(t0)
+	local := t0.Add(rtt / 2)
+	s := sample{
+		local:  local,
+		offset: time.UnixMilli(serverMs).Sub(local),
+		rtt:    rtt,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples = append(c.samples, s)
+	if len(c.samples) > c.windowSize {
+		c.samples = c.samples[len(c.samples)-c.windowSize:]
+	}
+	c.estimate()
+	c.synced = true
+
+	c.log.Debugw("Synced server clock", "rtt", rtt, "offset", c.offset, "drift", c.drift)
+	return nil
+}
+
+// estimate computes the offset from the lowest-RTT samples, which suffer the least
+// from asymmetric network delay, and the drift as the least squares slope of all samples.
+func (c *Clock) estimate() {
+	best := make([]sample, len(c.samples))
+	copy(best, c.samples)
+	sort.Slice(best, func(i, j int) bool { return best[i].rtt < best[j].rtt })
+	if len(best) > bestSamples {
+		best = best[:bestSamples]
+	}
+
+	c.drift = slope(c.samples)
+	c.ref = c.samples[len(c.samples)-1].local
+
+	var sum float64
+	for _, s := range best {
+		// Project every sample offset to the reference time using the drift.
+		sum += float64(s.offset) + c.drift*float64(c.ref.Sub(s.local))
+	}
+	c.offset = time.Duration(sum / float64(len(best)))
+}
+
+func slope(samples []sample) float64 {
+	n := float64(len(samples))
+	if n < 2 {
+		return 0
+	}
+
+	origin := samples[0].local
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := float64(s.local.Sub(origin))
+		y := float64(s.offset)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
+
+// IsSynced reports whether at least one sample was taken.
+func (c *Clock) IsSynced() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.synced
+}
+
+// OffsetAt returns the estimated server - local offset at local time t.
+func (c *Clock) OffsetAt(t time.Time) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.synced {
+		return 0
+	}
+	return c.offset + time.Duration(c.drift*float64(t.Sub(c.ref)))
+}
+
+// Offset returns the current estimated server - local offset.
+func (c *Clock) Offset() time.Duration {
+	return c.OffsetAt(c.now())
+}
+
+// Drift returns the estimated drift of the offset in seconds per second.
+func (c *Clock) Drift() float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.drift
+}
+
+// ToServerTime converts a local time to the estimated server time.
+func (c *Clock) ToServerTime(t time.Time) time.Time {
+	return t.Add(c.OffsetAt(t))
+}
+
+// ServerNow returns the estimated current server time.
+func (c *Clock) ServerNow() time.Time {
+	return c.ToServerTime(c.now())
+}
+
+// Latency returns the time between an exchange timestamp in milliseconds and
+// a local receive time, both on the server clock.
+func (c *Clock) Latency(exchangeTimestampMs int64, receivedAt time.Time) time.Duration {
+	return c.ToServerTime(receivedAt).Sub(time.UnixMilli(exchangeTimestampMs))
+}
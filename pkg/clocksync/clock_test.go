@@ -0,0 +1,120 @@
+package clocksync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer simulates a server clock running `offset` ahead of the local clock
+// and drifting by `drift` seconds per second. Each call advances the local clock.
+type fakeServer struct {
+	local  time.Time
+	start  time.Time
+	offset time.Duration
+	drift  float64
+	rtts   []time.Duration
+	calls  int
+	err    error
+}
+
+func (s *fakeServer) now() time.Time {
+	return s.local
+}
+
+func (s *fakeServer) GetTime(_ context.Context) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+
+	rtt := s.rtts[s.calls%len(s.rtts)]
+	s.calls++
+
+	mid := s.local.Add(rtt / 2)
+	server := mid.Add(s.offset + time.Duration(s.drift*float64(mid.Sub(s.start))))
+	s.local = s.local.Add(rtt)
+	return server.UnixMilli(), nil
+}
+
+func newTestClock(s *fakeServer) *Clock {
+	c := New(s, Config{WindowSize: 8})
+	c.now = s.now
+	return c
+}
+
+func TestSyncOffset(t *testing.T) {
+	start := time.UnixMilli(1_660_000_000_000)
+	s := &fakeServer{
+		local:  start,
+		start:  start,
+		offset: 250 * time.Millisecond,
+		rtts:   []time.Duration{20 * time.Millisecond},
+	}
+	c := newTestClock(s)
+
+	assert.False(t, c.IsSynced())
+	assert.Equal(t, time.Duration(0), c.Offset())
+
+	require.NoError(t, c.Sync(context.Background()))
+	assert.True(t, c.IsSynced())
+	assert.InDelta(t, float64(250*time.Millisecond), float64(c.Offset()), float64(time.Millisecond))
+	assert.InDelta(t,
+		float64(s.local.Add(250*time.Millisecond).UnixNano()),
+		float64(c.ServerNow().UnixNano()),
+		float64(time.Millisecond),
+	)
+}
+
+func TestSyncDrift(t *testing.T) {
+	start := time.UnixMilli(1_660_000_000_000)
+	s := &fakeServer{
+		local:  start,
+		start:  start,
+		offset: 100 * time.Millisecond,
+		drift:  1e-3,
+		rtts:   []time.Duration{10 * time.Millisecond, 300 * time.Millisecond},
+	}
+	c := newTestClock(s)
+
+	for i := 0; i < 8; i++ {
+		require.NoError(t, c.Sync(context.Background()))
+		s.local = s.local.Add(time.Minute)
+	}
+
+	assert.InDelta(t, 1e-3, c.Drift(), 1e-4)
+
+	// One hour later the offset has grown by ~3.6s.
+	later := s.local.Add(time.Hour)
+	expected := 100*time.Millisecond + time.Duration(1e-3*float64(later.Sub(start)))
+	assert.InDelta(t, float64(expected), float64(c.OffsetAt(later)), float64(20*time.Millisecond))
+}
+
+func TestLatency(t *testing.T) {
+	start := time.UnixMilli(1_660_000_000_000)
+	s := &fakeServer{
+		local:  start,
+		start:  start,
+		offset: -time.Second,
+		rtts:   []time.Duration{2 * time.Millisecond},
+	}
+	c := newTestClock(s)
+	require.NoError(t, c.Sync(context.Background()))
+
+	// Local clock is 1s ahead, so a packet stamped 5ms before "server now" took ~5ms.
+	receivedAt := s.local
+	exchangeTs := receivedAt.Add(-time.Second - 5*time.Millisecond).UnixMilli()
+	assert.InDelta(t, float64(5*time.Millisecond), float64(c.Latency(exchangeTs, receivedAt)), float64(time.Millisecond))
+}
+
+func TestSyncError(t *testing.T) {
+	s := &fakeServer{local: time.Now(), err: errors.New("not connected")}
+	c := newTestClock(s)
+
+	require.Error(t, c.Sync(context.Background()))
+	assert.False(t, c.IsSynced())
+	require.Error(t, c.Start(context.Background()))
+}
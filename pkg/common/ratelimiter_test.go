@@ -0,0 +1,26 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	l := NewRateLimiter(100, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+	// Burst covers the first two calls, the next two wait ~10ms each.
+	require.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l = NewRateLimiter(0.001, 0)
+	require.ErrorIs(t, l.Wait(ctx), context.Canceled)
+}
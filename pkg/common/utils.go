@@ -5,6 +5,8 @@ import (
 	"reflect"
 )
 
+// ReplaceNaNValueOfStruct replaces the NaN values of the top-level float64 fields of a struct
+// with zero, see ReplaceNaN for nested structs and nullable fields.
 func ReplaceNaNValueOfStruct(v interface{}, typeOfV reflect.Type) {
 	LogP := reflect.ValueOf(v)
 	if LogP.CanConvert(typeOfV) {
@@ -25,3 +27,59 @@ func ReplaceNaNValueOfStruct(v interface{}, typeOfV reflect.Type) {
 		}
 	}
 }
+
+// NaNToZero returns zero for NaN, the null value of the SBE optional floats.
+func NaNToZero(f float64) float64 {
+	if math.IsNaN(f) {
+		return 0
+	}
+	return f
+}
+
+// Nullable returns nil for NaN, or a pointer to a copy of f.
+func Nullable(f float64) *float64 {
+	if math.IsNaN(f) {
+		return nil
+	}
+	return &f
+}
+
+// ReplaceNaN replaces the NaN values reachable from v, which must be a pointer: the nullable
+// values, pointers to a float, are set to nil and the other floats to zero. It follows the
+// exported fields of nested structs, pointers, slices and arrays, v must not hold pointer cycles.
+func ReplaceNaN(v interface{}) {
+	replaceNaN(reflect.ValueOf(v))
+}
+
+func isFloat(kind reflect.Kind) bool {
+	return kind == reflect.Float64 || kind == reflect.Float32
+}
+
+func replaceNaN(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Float64, reflect.Float32:
+		if v.CanSet() && math.IsNaN(v.Float()) {
+			v.SetFloat(0)
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		elem := v.Elem()
+		if isFloat(elem.Kind()) && v.CanSet() {
+			if math.IsNaN(elem.Float()) {
+				v.Set(reflect.Zero(v.Type()))
+			}
+			return
+		}
+		replaceNaN(elem)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			replaceNaN(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			replaceNaN(v.Index(i))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package common
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullable(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, Nullable(math.NaN()))
+	f := 1.5
+	p := Nullable(f)
+	require.NotNil(t, p)
+	assert.Equal(t, 1.5, *p)
+	assert.NotSame(t, &f, p)
+
+	assert.Zero(t, NaNToZero(math.NaN()))
+	assert.Equal(t, -2.5, NaNToZero(-2.5))
+}
+
+type nanLevel struct {
+	Price  float64
+	Amount *float64
+}
+
+type nanBook struct {
+	Mid      float64
+	Spread   float32
+	Last     *float64
+	Best     *nanLevel
+	Levels   []nanLevel
+	Pointers []*float64
+	Fixed    [2]float64
+	Nested   struct{ IV *float64 }
+	Name     string
+	hidden   float64
+}
+
+func TestReplaceNaN(t *testing.T) {
+	t.Parallel()
+
+	nan := math.NaN
+	ptr := func(f float64) *float64 { return &f }
+	book := nanBook{
+		Mid:      nan(),
+		Spread:   float32(nan()),
+		Last:     ptr(nan()),
+		Best:     &nanLevel{Price: nan(), Amount: ptr(nan())},
+		Levels:   []nanLevel{{Price: 1, Amount: ptr(2)}, {Price: nan(), Amount: ptr(nan())}},
+		Pointers: []*float64{ptr(nan()), ptr(3), nil},
+		Fixed:    [2]float64{nan(), 4},
+		Name:     "book",
+		hidden:   nan(),
+	}
+	book.Nested.IV = ptr(nan())
+
+	ReplaceNaN(&book)
+
+	assert.Zero(t, book.Mid)
+	assert.Zero(t, book.Spread)
+	assert.Nil(t, book.Last)
+	assert.Equal(t, &nanLevel{}, book.Best)
+	assert.Equal(t, []nanLevel{{Price: 1, Amount: ptr(2)}, {}}, book.Levels)
+	assert.Equal(t, []*float64{nil, ptr(3), nil}, book.Pointers)
+	assert.Equal(t, [2]float64{0, 4}, book.Fixed)
+	assert.Nil(t, book.Nested.IV)
+	assert.Equal(t, "book", book.Name)
+	// Unexported fields are left as they are.
+	assert.True(t, math.IsNaN(book.hidden))
+
+	// A pointer to a float is not nullable, it is zeroed.
+	f := nan()
+	ReplaceNaN(&f)
+	assert.Zero(t, f)
+
+	// Values which are not pointers can not be changed.
+	assert.NotPanics(t, func() { ReplaceNaN(nanLevel{Price: nan()}) })
+	assert.NotPanics(t, func() { ReplaceNaN(nil) })
+}
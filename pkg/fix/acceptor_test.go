@@ -0,0 +1,812 @@
+package fix
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testAcceptorCompID = "DERIBITSERVER"
+	// testDropCopySuffix is appended to the comp ID of the trading session for the drop copy session.
+	testDropCopySuffix = "_DC"
+)
+
+// testSessionCount gives every acceptor a distinct session, as quickfix registers sessions globally.
+// nolint:gochecknoglobals
+var testSessionCount int32
+
+// testMDEntry is a scripted market data entry.
+type testMDEntry struct {
+	action    enum.MDUpdateAction // ignored in snapshots
+	entryType enum.MDEntryType
+	price     float64
+	size      float64
+}
+
+// testMarketData is the scripted market data of an instrument: a snapshot followed by incremental updates.
+type testMarketData struct {
+	markPrice float64
+	snapshot  []testMDEntry
+	updates   [][]testMDEntry
+}
+
+type testOrder struct {
+	orderID  string
+	clOrdID  string
+	symbol   string
+	side     enum.Side
+	price    float64
+	amount   float64
+	filled   float64
+	notional float64
+	label    string
+	status   enum.OrdStatus
+	quoteKey string // quote set and entry ID of the orders of quote entries
+}
+
+type testFill struct {
+	execID    string
+	price     float64
+	amount    float64
+	liquidity int
+}
+
+// testTrade is one side of a fill, reported on drop copy sessions.
+type testTrade struct {
+	seq       int
+	execID    string
+	order     testOrder
+	price     float64
+	amount    float64
+	aggressor bool
+	time      time.Time
+}
+
+// testAcceptor is a local stand-in for the Deribit FIX server. It checks the logon signature,
+// answers market data requests with scripted messages, matches limit orders against a simple book,
+// reports the trades on a drop copy session, validates mass quotes resting in the book and rejects
+// requests on demand.
+type testAcceptor struct {
+	t         *testing.T
+	apiKey    string
+	secretKey string
+	compID    string // TargetCompID of the client session
+	port      int
+	acceptor  *quickfix.Acceptor
+
+	mu         sync.Mutex
+	heartbeats int
+	marketData map[string]testMarketData
+	rejects    map[enum.MsgType]string
+	orders     map[string]*testOrder
+	book       []*testOrder // resting orders in time priority
+	nextID     int
+	trades     []testTrade
+	published  int                           // number of trades reported to the subscriptions
+	dropCopies map[quickfix.SessionID]string // TradeRequestID of the subscription of every session
+	quotes     map[string]QuoteEntry         // quote entries by quote set and entry ID
+	// quoteEntries is the number of entries of every MassQuote received.
+	quoteEntries []int
+}
+
+func newTestAcceptor(t *testing.T) *testAcceptor {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	a := &testAcceptor{
+		t:          t,
+		apiKey:     apiKey,
+		secretKey:  secretKey,
+		compID:     fmt.Sprintf("FIX_TEST_%d", atomic.AddInt32(&testSessionCount, 1)),
+		port:       port,
+		marketData: make(map[string]testMarketData),
+		rejects:    make(map[enum.MsgType]string),
+		orders:     make(map[string]*testOrder),
+		dropCopies: make(map[quickfix.SessionID]string),
+		quotes:     make(map[string]QuoteEntry),
+	}
+
+	settingStr := fmt.Sprintf(
+		"[DEFAULT]\nSocketAcceptHost=127.0.0.1\nSocketAcceptPort=%d\nSenderCompID=%s\nTargetCompID=%s\n"+
+			"ResetOnLogon=Y\n\n[SESSION]\nBeginString=FIX.4.4\n\n[SESSION]\nBeginString=FIX.4.4\nTargetCompID=%s\n",
+		port, testAcceptorCompID, a.compID, a.compID+testDropCopySuffix,
+	)
+	settings, err := quickfix.ParseSettings(bytes.NewBufferString(settingStr))
+	require.NoError(t, err)
+
+	a.acceptor, err = quickfix.NewAcceptor(a, quickfix.NewMemoryStoreFactory(), settings, quickfix.NewNullLogFactory())
+	require.NoError(t, err)
+	require.NoError(t, a.acceptor.Start())
+
+	t.Cleanup(func() {
+		a.acceptor.Stop()
+		for _, compID := range []string{a.compID, a.compID + testDropCopySuffix} {
+			_ = quickfix.UnregisterSession(quickfix.SessionID{
+				BeginString: fixVersion, SenderCompID: testAcceptorCompID, TargetCompID: compID,
+			})
+			_ = quickfix.UnregisterSession(quickfix.SessionID{
+				BeginString: fixVersion, SenderCompID: compID, TargetCompID: testAcceptorCompID,
+			})
+		}
+	})
+
+	return a
+}
+
+// clientSettings returns the settings of a client session connecting to the acceptor.
+func (a *testAcceptor) clientSettings(heartBtInt int) *quickfix.Settings {
+	return a.sessionSettings(a.compID, heartBtInt)
+}
+
+// dropCopySettings returns the settings of a drop copy session connecting to the acceptor.
+func (a *testAcceptor) dropCopySettings(heartBtInt int) *quickfix.Settings {
+	return a.sessionSettings(a.compID+testDropCopySuffix, heartBtInt)
+}
+
+func (a *testAcceptor) sessionSettings(compID string, heartBtInt int) *quickfix.Settings {
+	settingStr := fmt.Sprintf(
+		"[DEFAULT]\nSocketConnectHost=127.0.0.1\nSocketConnectPort=%d\nHeartBtInt=%d\nSenderCompID=%s\n"+
+			"TargetCompID=%s\nReconnectInterval=1\n\n[SESSION]\nBeginString=FIX.4.4\n",
+		a.port, heartBtInt, compID, testAcceptorCompID,
+	)
+	settings, err := quickfix.ParseSettings(bytes.NewBufferString(settingStr))
+	require.NoError(a.t, err)
+	return settings
+}
+
+func (a *testAcceptor) sessionID() quickfix.SessionID {
+	return quickfix.SessionID{BeginString: fixVersion, SenderCompID: testAcceptorCompID, TargetCompID: a.compID}
+}
+
+// setMarketData scripts the messages sent for a subscription to instrument.
+func (a *testAcceptor) setMarketData(instrument string, data testMarketData) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.marketData[instrument] = data
+}
+
+// rejectNext rejects the next request of msgType with reason.
+func (a *testAcceptor) rejectNext(msgType enum.MsgType, reason string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.rejects[msgType] = reason
+}
+
+func (a *testAcceptor) takeReject(msgType enum.MsgType) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	reason, ok := a.rejects[msgType]
+	delete(a.rejects, msgType)
+	return reason, ok
+}
+
+func (a *testAcceptor) heartbeatCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.heartbeats
+}
+
+// OnCreate implemented as part of Application interface.
+func (a *testAcceptor) OnCreate(_ quickfix.SessionID) {}
+
+// OnLogon implemented as part of Application interface.
+func (a *testAcceptor) OnLogon(_ quickfix.SessionID) {}
+
+// OnLogout implemented as part of Application interface.
+func (a *testAcceptor) OnLogout(sessionID quickfix.SessionID) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.dropCopies, sessionID)
+}
+
+// ToAdmin implemented as part of Application interface.
+func (a *testAcceptor) ToAdmin(_ *quickfix.Message, _ quickfix.SessionID) {}
+
+// ToApp implemented as part of Application interface.
+func (a *testAcceptor) ToApp(_ *quickfix.Message, _ quickfix.SessionID) error {
+	return nil
+}
+
+// FromAdmin implemented as part of Application interface.
+func (a *testAcceptor) FromAdmin(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	switch {
+	case msg.IsMsgTypeOf(string(enum.MsgType_HEARTBEAT)):
+		a.mu.Lock()
+		a.heartbeats++
+		a.mu.Unlock()
+	case msg.IsMsgTypeOf(string(enum.MsgType_LOGON)):
+		return a.checkLogon(msg)
+	}
+	return nil
+}
+
+// checkLogon verifies Password = base64(sha256(RawData ++ secret key)).
+func (a *testAcceptor) checkLogon(msg *quickfix.Message) quickfix.MessageRejectError {
+	if reason, ok := a.takeReject(enum.MsgType_LOGON); ok {
+		return quickfix.RejectLogon{Text: reason}
+	}
+
+	username, _ := msg.Body.GetString(tag.Username)
+	rawData, _ := msg.Body.GetString(tag.RawData)
+	password, _ := msg.Body.GetString(tag.Password)
+	hash := sha256.Sum256([]byte(rawData + a.secretKey))
+	if username != a.apiKey || password != base64.StdEncoding.EncodeToString(hash[:]) {
+		return quickfix.RejectLogon{Text: "invalid_credentials"}
+	}
+	return nil
+}
+
+// FromApp implemented as part of Application interface.
+func (a *testAcceptor) FromApp(msg *quickfix.Message, sessionID quickfix.SessionID) quickfix.MessageRejectError {
+	var responses []*quickfix.Message
+	switch {
+	case msg.IsMsgTypeOf(string(enum.MsgType_MARKET_DATA_REQUEST)):
+		responses = a.handleMarketDataRequest(msg)
+	case msg.IsMsgTypeOf(string(enum.MsgType_ORDER_SINGLE)):
+		responses = a.handleNewOrder(msg)
+	case msg.IsMsgTypeOf(string(enum.MsgType_ORDER_CANCEL_REQUEST)):
+		responses = a.handleCancel(msg)
+	case msg.IsMsgTypeOf(string(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST)):
+		responses = a.handleTradeCaptureReportRequest(msg, sessionID)
+	case msg.IsMsgTypeOf(string(enum.MsgType_MASS_QUOTE)):
+		responses = a.handleMassQuote(msg)
+	case msg.IsMsgTypeOf(string(enum.MsgType_QUOTE_CANCEL)):
+		responses = a.handleQuoteCancel(msg)
+	default:
+		a.t.Logf("Unexpected message %s", msg)
+	}
+
+	for _, response := range responses {
+		require.NoError(a.t, quickfix.SendToTarget(response, sessionID))
+	}
+	a.publishTrades()
+	return nil
+}
+
+func newAcceptorMessage(msgType enum.MsgType) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(msgType))
+	return msg
+}
+
+func (a *testAcceptor) handleMarketDataRequest(msg *quickfix.Message) []*quickfix.Message {
+	reqID, _ := msg.Body.GetString(tag.MDReqID)
+	if reason, ok := a.takeReject(enum.MsgType_MARKET_DATA_REQUEST); ok {
+		reject := newAcceptorMessage(enum.MsgType_MARKET_DATA_REQUEST_REJECT)
+		reject.Body.SetString(tag.MDReqID, reqID)
+		reject.Body.SetString(tag.Text, reason)
+		return []*quickfix.Message{reject}
+	}
+
+	symbols := quickfix.NewRepeatingGroup(tag.NoRelatedSym, quickfix.GroupTemplate{quickfix.GroupElement(tag.Symbol)})
+	require.NoError(a.t, msg.Body.GetGroup(symbols))
+	requestType, _ := msg.Body.GetString(tag.SubscriptionRequestType)
+
+	var responses []*quickfix.Message
+	for i := 0; i < symbols.Len(); i++ {
+		symbol, _ := symbols.Get(i).GetString(tag.Symbol)
+		a.mu.Lock()
+		data := a.marketData[symbol]
+		a.mu.Unlock()
+
+		// unsubscriptions are acknowledged by an empty snapshot
+		if enum.SubscriptionRequestType(requestType) != enum.SubscriptionRequestType_SNAPSHOT_PLUS_UPDATES {
+			data = testMarketData{}
+		}
+
+		responses = append(responses, newMarketDataMessage(
+			enum.MsgType_MARKET_DATA_SNAPSHOT_FULL_REFRESH, reqID, symbol, data.markPrice, data.snapshot,
+		))
+		for _, update := range data.updates {
+			responses = append(responses, newMarketDataMessage(
+				enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH, reqID, symbol, data.markPrice, update,
+			))
+		}
+	}
+	return responses
+}
+
+func newMarketDataMessage(
+	msgType enum.MsgType, reqID, symbol string, markPrice float64, entries []testMDEntry,
+) *quickfix.Message {
+	msg := newAcceptorMessage(msgType)
+	msg.Body.SetString(tag.MDReqID, reqID)
+	msg.Body.SetString(tag.Symbol, symbol)
+	msg.Body.SetString(tagMarkPrice, floatToStr(markPrice))
+
+	group := newSnapshotNoMDEntriesRepeatingGroup()
+	if msgType == enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH {
+		group = newNoMDEntriesRepeatingGroup()
+	}
+	for _, entry := range entries {
+		g := group.Add()
+		if msgType == enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH {
+			g.Set(field.NewMDUpdateAction(entry.action))
+		}
+		g.Set(field.NewMDEntryType(entry.entryType))
+		g.SetString(tag.MDEntryPx, floatToStr(entry.price))
+		g.SetString(tag.MDEntrySize, floatToStr(entry.size))
+		g.SetString(tag.MDEntryDate, time.Now().UTC().Format("20060102-15:04:05.000"))
+	}
+	msg.Body.SetGroup(group)
+	return msg
+}
+
+// handleNewOrder matches a limit order against the resting orders of the other side
+// and rests the remaining amount.
+func (a *testAcceptor) handleNewOrder(msg *quickfix.Message) []*quickfix.Message {
+	clOrdID, _ := msg.Body.GetString(tag.ClOrdID)
+	symbol, _ := msg.Body.GetString(tag.Symbol)
+	side, _ := msg.Body.GetString(tag.Side)
+	amount, _ := msg.Body.GetString(tag.OrderQty)
+	price, _ := msg.Body.GetString(tag.Price)
+	label, _ := msg.Body.GetString(tagDeribitLabel)
+
+	if reason, ok := a.takeReject(enum.MsgType_ORDER_SINGLE); ok {
+		reject := newAcceptorMessage(enum.MsgType_EXECUTION_REPORT)
+		reject.Body.SetString(tag.OrigClOrdID, clOrdID)
+		reject.Body.Set(field.NewOrdStatus(enum.OrdStatus_REJECTED))
+		reject.Body.SetString(tag.Text, reason)
+		return []*quickfix.Message{reject}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.nextID++
+	order := &testOrder{
+		orderID: strconv.Itoa(a.nextID),
+		clOrdID: clOrdID,
+		symbol:  symbol,
+		side:    enum.Side(side),
+		label:   label,
+		status:  enum.OrdStatus_NEW,
+	}
+	order.amount, _ = strconv.ParseFloat(amount, 64)
+	order.price, _ = strconv.ParseFloat(price, 64)
+	a.orders[order.orderID] = order
+
+	var responses []*quickfix.Message
+	var takerFills []testFill
+	for _, maker := range a.matchingOrders(order) {
+		fillAmount := minFloat(order.amount-order.filled, maker.amount-maker.filled)
+		a.nextID++
+		execID := strconv.Itoa(a.nextID)
+		maker.fill(fillAmount, maker.price)
+		order.fill(fillAmount, maker.price)
+		a.addTrade(execID, maker, fillAmount, maker.price, false)
+		a.addTrade(execID, order, fillAmount, maker.price, true)
+		takerFills = append(takerFills, testFill{execID: execID, price: maker.price, amount: fillAmount, liquidity: 2})
+		responses = append(responses, newExecutionReport(maker, maker.clOrdID, enum.ExecType_TRADE, []testFill{
+			{execID: execID, price: maker.price, amount: fillAmount, liquidity: 1},
+		}))
+	}
+	a.removeInactiveOrders()
+	if order.status != enum.OrdStatus_FILLED {
+		a.book = append(a.book, order)
+	}
+
+	execType := enum.ExecType_NEW
+	if len(takerFills) > 0 {
+		execType = enum.ExecType_TRADE
+	}
+	report := newExecutionReport(order, clOrdID, execType, takerFills)
+	return append([]*quickfix.Message{report}, responses...)
+}
+
+// matchingOrders returns the resting orders crossing order by price then time priority.
+func (a *testAcceptor) matchingOrders(order *testOrder) []*testOrder {
+	var matches []*testOrder
+	for _, resting := range a.book {
+		if resting.symbol != order.symbol || resting.side == order.side {
+			continue
+		}
+		if order.side == enum.Side_BUY && resting.price <= order.price ||
+			order.side == enum.Side_SELL && resting.price >= order.price {
+			matches = append(matches, resting)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if order.side == enum.Side_BUY {
+			return matches[i].price < matches[j].price
+		}
+		return matches[i].price > matches[j].price
+	})
+
+	var out []*testOrder
+	remaining := order.amount
+	for _, match := range matches {
+		if remaining <= 0 {
+			break
+		}
+		out = append(out, match)
+		remaining -= match.amount - match.filled
+	}
+	return out
+}
+
+func (a *testAcceptor) removeInactiveOrders() {
+	book := a.book[:0]
+	for _, order := range a.book {
+		if order.status == enum.OrdStatus_NEW || order.status == enum.OrdStatus_PARTIALLY_FILLED {
+			book = append(book, order)
+		}
+	}
+	a.book = book
+}
+
+func (a *testAcceptor) handleCancel(msg *quickfix.Message) []*quickfix.Message {
+	clOrdID, _ := msg.Body.GetString(tag.ClOrdID)
+	orderID, _ := msg.Body.GetString(tag.OrigClOrdID)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	reason, rejected := a.rejects[enum.MsgType_ORDER_CANCEL_REQUEST]
+	delete(a.rejects, enum.MsgType_ORDER_CANCEL_REQUEST)
+	order := a.orders[orderID]
+	if !rejected && (order == nil || order.status == enum.OrdStatus_FILLED || order.status == enum.OrdStatus_CANCELED) {
+		rejected, reason = true, "order_not_found"
+	}
+	if rejected {
+		reject := newAcceptorMessage(enum.MsgType_ORDER_CANCEL_REJECT)
+		reject.Body.SetString(tag.ClOrdID, clOrdID)
+		reject.Body.SetString(tag.OrigClOrdID, orderID)
+		reject.Body.Set(field.NewCxlRejReason(enum.CxlRejReason_UNKNOWN_ORDER))
+		reject.Body.SetString(tag.Text, reason)
+		return []*quickfix.Message{reject}
+	}
+
+	order.status = enum.OrdStatus_CANCELED
+	a.removeInactiveOrders()
+	return []*quickfix.Message{newExecutionReport(order, orderID, enum.ExecType_CANCELED, nil)}
+}
+
+func (o *testOrder) fill(amount, price float64) {
+	o.filled += amount
+	o.notional += amount * price
+	o.status = enum.OrdStatus_PARTIALLY_FILLED
+	if o.filled >= o.amount {
+		o.status = enum.OrdStatus_FILLED
+	}
+}
+
+func newExecutionReport(
+	order *testOrder, origClOrdID string, execType enum.ExecType, fills []testFill,
+) *quickfix.Message {
+	var avgPx float64
+	if order.filled > 0 {
+		avgPx = order.notional / order.filled
+	}
+
+	msg := newAcceptorMessage(enum.MsgType_EXECUTION_REPORT)
+	msg.Body.SetString(tag.ClOrdID, order.orderID)
+	msg.Body.SetString(tag.OrigClOrdID, origClOrdID)
+	msg.Body.SetString(tag.OrderID, order.orderID)
+	msg.Body.Set(field.NewExecType(execType))
+	msg.Body.Set(field.NewOrdStatus(order.status))
+	msg.Body.Set(field.NewSide(order.side))
+	msg.Body.Set(field.NewTransactTime(time.Now()))
+	msg.Body.SetString(tag.Commission, "0")
+	msg.Body.SetString(tag.LeavesQty, floatToStr(order.amount-order.filled))
+	msg.Body.SetString(tag.CumQty, floatToStr(order.filled))
+	msg.Body.SetString(tag.OrderQty, floatToStr(order.amount))
+	msg.Body.Set(field.NewOrdType(enum.OrdType_LIMIT))
+	msg.Body.SetString(tag.Price, floatToStr(order.price))
+	msg.Body.SetString(tag.Text, "success")
+	msg.Body.SetString(tag.Symbol, order.symbol)
+	msg.Body.SetString(tag.AvgPx, floatToStr(avgPx))
+	msg.Body.SetString(tag.MaxShow, floatToStr(order.amount))
+	msg.Body.SetString(tagDeribitLabel, order.label)
+
+	if len(fills) > 0 {
+		group := newNoFillsRepeatingGroup()
+		for _, fill := range fills {
+			g := group.Add()
+			g.SetString(tag.FillExecID, fill.execID)
+			g.SetString(tag.FillPx, floatToStr(fill.price))
+			g.SetString(tag.FillQty, floatToStr(fill.amount))
+			g.SetInt(tag.FillLiquidityInd, fill.liquidity)
+		}
+		msg.Body.SetGroup(group)
+	}
+	return msg
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// addTrade records the fill of order, a.mu must be held.
+func (a *testAcceptor) addTrade(execID string, order *testOrder, amount, price float64, aggressor bool) {
+	a.trades = append(a.trades, testTrade{
+		seq:       len(a.trades) + 1,
+		execID:    execID,
+		order:     *order,
+		price:     price,
+		amount:    amount,
+		aggressor: aggressor,
+		time:      time.Now(),
+	})
+}
+
+// tradeCount returns the number of trades done.
+func (a *testAcceptor) tradeCount() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.trades)
+}
+
+// handleTradeCaptureReportRequest acknowledges the request and replays the matching trades,
+// subscribing the session to the new trades if requested.
+func (a *testAcceptor) handleTradeCaptureReportRequest(
+	msg *quickfix.Message, sessionID quickfix.SessionID,
+) []*quickfix.Message {
+	reqID, _ := msg.Body.GetString(tag.TradeRequestID)
+	requestType, _ := msg.Body.GetString(tag.SubscriptionRequestType)
+	symbol, _ := msg.Body.GetString(tag.Symbol)
+
+	ack := newAcceptorMessage(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST_ACK)
+	ack.Body.SetString(tag.TradeRequestID, reqID)
+	if reason, ok := a.takeReject(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST); ok {
+		ack.Body.Set(field.NewTradeRequestStatus(enum.TradeRequestStatus_REJECTED))
+		ack.Body.SetString(tag.Text, reason)
+		return []*quickfix.Message{ack}
+	}
+	ack.Body.Set(field.NewTradeRequestStatus(enum.TradeRequestStatus_ACCEPTED))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	switch enum.SubscriptionRequestType(requestType) {
+	case enum.SubscriptionRequestType_DISABLE_PREVIOUS_SNAPSHOT_PLUS_UPDATE_REQUEST:
+		delete(a.dropCopies, sessionID)
+		ack.Body.SetInt(tag.TotNumTradeReports, 0)
+		return []*quickfix.Message{ack}
+	case enum.SubscriptionRequestType_SNAPSHOT_PLUS_UPDATES:
+		a.dropCopies[sessionID] = reqID
+	}
+
+	var startTime, endTime time.Time
+	dates := newNoDatesRepeatingGroup()
+	if msg.Body.Has(tag.NoDates) {
+		require.NoError(a.t, msg.Body.GetGroup(dates))
+	}
+	if dates.Len() > 0 {
+		startTime, _ = dates.Get(0).GetTime(tag.TransactTime)
+	}
+	if dates.Len() > 1 {
+		endTime, _ = dates.Get(1).GetTime(tag.TransactTime)
+	}
+
+	var reports []*quickfix.Message
+	for _, trade := range a.trades {
+		if symbol != "" && trade.order.symbol != symbol ||
+			trade.time.Before(startTime) || !endTime.IsZero() && trade.time.After(endTime) {
+			continue
+		}
+		reports = append(reports, newTradeCaptureReport(reqID, trade))
+	}
+	ack.Body.SetInt(tag.TotNumTradeReports, len(reports))
+	if len(reports) > 0 {
+		reports[len(reports)-1].Body.SetBool(tag.LastRptRequested, true)
+	}
+	return append([]*quickfix.Message{ack}, reports...)
+}
+
+// publishTrades reports the new trades to the subscribed drop copy sessions.
+func (a *testAcceptor) publishTrades() {
+	a.mu.Lock()
+	trades := a.trades[a.published:]
+	a.published = len(a.trades)
+	dropCopies := make(map[quickfix.SessionID]string, len(a.dropCopies))
+	for sessionID, reqID := range a.dropCopies {
+		dropCopies[sessionID] = reqID
+	}
+	a.mu.Unlock()
+
+	for sessionID, reqID := range dropCopies {
+		for _, trade := range trades {
+			require.NoError(a.t, quickfix.SendToTarget(newTradeCaptureReport(reqID, trade), sessionID))
+		}
+	}
+}
+
+func newTradeCaptureReport(reqID string, trade testTrade) *quickfix.Message {
+	msg := newAcceptorMessage(enum.MsgType_TRADE_CAPTURE_REPORT)
+	msg.Body.SetInt(tag.TradeReportID, trade.seq)
+	msg.Body.SetString(tag.TradeRequestID, reqID)
+	msg.Body.SetString(tag.ExecID, trade.execID)
+	msg.Body.SetString(tag.Symbol, trade.order.symbol)
+	msg.Body.SetString(tag.LastQty, floatToStr(trade.amount))
+	msg.Body.SetString(tag.LastPx, floatToStr(trade.price))
+	msg.Body.Set(field.NewTransactTime(trade.time))
+
+	sides := newTradeCaptureNoSidesRepeatingGroup()
+	side := sides.Add()
+	side.Set(field.NewSide(trade.order.side))
+	side.SetString(tag.OrderID, trade.order.orderID)
+	side.SetString(tag.ClOrdID, trade.order.clOrdID)
+	side.SetString(tag.Commission, "0")
+	side.SetBool(tag.AggressorIndicator, trade.aggressor)
+	side.SetString(tagDeribitLabel, trade.order.label)
+	msg.Body.SetGroup(sides)
+	return msg
+}
+
+// quoteState returns the quote entries and the number of entries of every MassQuote received.
+func (a *testAcceptor) quoteState() (map[string]QuoteEntry, []int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	quotes := make(map[string]QuoteEntry, len(a.quotes))
+	for key, entry := range a.quotes {
+		quotes[key] = entry
+	}
+	return quotes, append([]int(nil), a.quoteEntries...)
+}
+
+// handleMassQuote accepts the entries with positive prices and a bid below the offer.
+func (a *testAcceptor) handleMassQuote(msg *quickfix.Message) []*quickfix.Message {
+	quoteID, _ := msg.Body.GetString(tag.QuoteID)
+	ack := newAcceptorMessage(enum.MsgType_MASS_QUOTE_ACKNOWLEDGEMENT)
+	ack.Body.SetString(tag.QuoteID, quoteID)
+	if reason, ok := a.takeReject(enum.MsgType_MASS_QUOTE); ok {
+		ack.Body.Set(field.NewQuoteStatus(enum.QuoteStatus_REJECTED))
+		ack.Body.SetString(tag.Text, reason)
+		return []*quickfix.Message{ack}
+	}
+	ack.Body.Set(field.NewQuoteStatus(enum.QuoteStatus_ACCEPTED))
+
+	sets := newMassQuoteNoQuoteSetsRepeatingGroup()
+	require.NoError(a.t, msg.Body.GetGroup(sets))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	ackSets := newMassQuoteAckNoQuoteSetsRepeatingGroup()
+	var count int
+	for i := 0; i < sets.Len(); i++ {
+		setID, _ := sets.Get(i).GetString(tag.QuoteSetID)
+		entries := newMassQuoteNoQuoteEntriesRepeatingGroup()
+		require.NoError(a.t, sets.Get(i).GetGroup(entries))
+
+		ackSet := ackSets.Add()
+		ackSet.SetString(tag.QuoteSetID, setID)
+		ackEntries := newMassQuoteAckNoQuoteEntriesRepeatingGroup()
+		for j := 0; j < entries.Len(); j++ {
+			count++
+			entry := readTestQuoteEntry(entries.Get(j))
+			ackEntry := ackEntries.Add()
+			ackEntry.SetString(tag.QuoteEntryID, entry.ID)
+			ackEntry.SetString(tag.Symbol, entry.Instrument)
+
+			var reason enum.QuoteEntryRejectReason
+			switch {
+			case entry.BidSize > 0 && entry.BidPrice <= 0 || entry.OfferSize > 0 && entry.OfferPrice <= 0:
+				reason = enum.QuoteEntryRejectReason_INVALID_PRICE
+			case entry.BidSize > 0 && entry.OfferSize > 0 && entry.BidPrice >= entry.OfferPrice:
+				reason = enum.QuoteEntryRejectReason_INVALID_BID_ASK_SPREAD
+			}
+			if reason != "" {
+				ackEntry.Set(field.NewQuoteEntryStatus(enum.QuoteEntryStatus_REJECTED))
+				ackEntry.Set(field.NewQuoteEntryRejectReason(reason))
+				a.cancelQuote(quoteKey(setID, entry.ID))
+				continue
+			}
+			ackEntry.Set(field.NewQuoteEntryStatus(enum.QuoteEntryStatus_ACCEPTED))
+			a.restQuote(quoteKey(setID, entry.ID), entry)
+		}
+		ackSet.SetInt(tag.TotNoQuoteEntries, entries.Len())
+		ackSet.SetGroup(ackEntries)
+	}
+	a.quoteEntries = append(a.quoteEntries, count)
+	ack.Body.SetGroup(ackSets)
+	return []*quickfix.Message{ack}
+}
+
+// restQuote replaces the quote entry of key and its resting orders, a.mu must be held.
+func (a *testAcceptor) restQuote(key string, entry QuoteEntry) {
+	a.cancelQuote(key)
+	a.quotes[key] = entry
+
+	sides := []struct {
+		side        enum.Side
+		price, size float64
+	}{
+		{enum.Side_BUY, entry.BidPrice, entry.BidSize},
+		{enum.Side_SELL, entry.OfferPrice, entry.OfferSize},
+	}
+	for _, side := range sides {
+		if side.size <= 0 {
+			continue
+		}
+		a.nextID++
+		order := &testOrder{
+			orderID:  strconv.Itoa(a.nextID),
+			symbol:   entry.Instrument,
+			side:     side.side,
+			price:    side.price,
+			amount:   side.size,
+			status:   enum.OrdStatus_NEW,
+			quoteKey: key,
+		}
+		a.orders[order.orderID] = order
+		a.book = append(a.book, order)
+	}
+}
+
+// cancelQuote removes the quote entry of key and its resting orders, a.mu must be held.
+func (a *testAcceptor) cancelQuote(key string) {
+	delete(a.quotes, key)
+	for _, order := range a.book {
+		if order.quoteKey == key {
+			order.status = enum.OrdStatus_CANCELED
+		}
+	}
+	a.removeInactiveOrders()
+}
+
+func readTestQuoteEntry(g *quickfix.Group) QuoteEntry {
+	entry := QuoteEntry{}
+	entry.ID, _ = g.GetString(tag.QuoteEntryID)
+	entry.Instrument, _ = g.GetString(tag.Symbol)
+	entry.BidPrice, _ = getGroupFloat(g, tag.BidPx)
+	entry.BidSize, _ = getGroupFloat(g, tag.BidSize)
+	entry.OfferPrice, _ = getGroupFloat(g, tag.OfferPx)
+	entry.OfferSize, _ = getGroupFloat(g, tag.OfferSize)
+	return entry
+}
+
+func (a *testAcceptor) handleQuoteCancel(msg *quickfix.Message) []*quickfix.Message {
+	quoteID, _ := msg.Body.GetString(tag.QuoteID)
+	cancelType, _ := msg.Body.GetString(tag.QuoteCancelType)
+
+	instruments := make(map[string]bool)
+	if msg.Body.Has(tag.NoQuoteEntries) {
+		entries := newQuoteCancelNoQuoteEntriesRepeatingGroup()
+		require.NoError(a.t, msg.Body.GetGroup(entries))
+		for i := 0; i < entries.Len(); i++ {
+			instrument, _ := entries.Get(i).GetString(tag.Symbol)
+			instruments[instrument] = true
+		}
+	}
+
+	a.mu.Lock()
+	for key, entry := range a.quotes {
+		if enum.QuoteCancelType(cancelType) == enum.QuoteCancelType_CANCEL_ALL_QUOTES || instruments[entry.Instrument] {
+			a.cancelQuote(key)
+		}
+	}
+	a.mu.Unlock()
+
+	ack := newAcceptorMessage(enum.MsgType_MASS_QUOTE_ACKNOWLEDGEMENT)
+	ack.Body.SetString(tag.QuoteID, quoteID)
+	ack.Body.Set(field.NewQuoteStatus(enum.QuoteStatus_CANCELED_ALL))
+	if enum.QuoteCancelType(cancelType) != enum.QuoteCancelType_CANCEL_ALL_QUOTES {
+		ack.Body.Set(field.NewQuoteStatus(enum.QuoteStatus_CANCEL_FOR_SYMBOL))
+	}
+	return []*quickfix.Message{ack}
+}
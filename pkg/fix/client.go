@@ -5,17 +5,20 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/router"
 	"github.com/chuckpreslar/emission"
 	"github.com/google/uuid"
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/quickfix/config"
 	"github.com/quickfixgo/tag"
 	"go.uber.org/zap"
 )
@@ -26,6 +29,20 @@ const (
 	subscriptionChannelParts = 2
 	subscriptionTypeBook     = "book"
 	subscriptionTypeTrades   = "trades"
+	subscriptionTypeTicker   = "ticker"
+
+	// tickerMarketDepth is the MarketDepth of ticker subscriptions, which only carry session statistics.
+	tickerMarketDepth = 1
+
+	// seenFillsSize is the number of recent fill IDs remembered to avoid emitting a trade twice.
+	seenFillsSize = 10000
+
+	defaultLogonTimeout = 30 * time.Second
+
+	defaultReconnectBackoff    = time.Second
+	defaultMaxReconnectBackoff = time.Minute
+	// expirePendingInterval is how often calls whose context is done are removed from pending.
+	expirePendingInterval = 10 * time.Second
 )
 
 type Initiator interface {
@@ -35,20 +52,69 @@ type Initiator interface {
 
 type Sender func(m quickfix.Messagable) (err error)
 
+// Clock converts local times to Deribit server time, e.g. clocksync.Clock.
+type Clock interface {
+	ToServerTime(t time.Time) time.Time
+}
+
 type Config struct {
 	APIKey    string
 	SecretKey string
 	Settings  *quickfix.Settings
 	Dialer    Dialer
 	Sender    Sender
+	Clock     Clock
+
+	// StoreFactory stores sent messages and sequence numbers. Defaults to a file store
+	// in FileStorePath if it is set, or to an in-memory store otherwise.
+	StoreFactory  quickfix.MessageStoreFactory
+	FileStorePath string
+	// LogFactory logs raw FIX messages and session events, e.g. NewAuditLogFactory.
+	// Defaults to no logging.
+	LogFactory quickfix.LogFactory
+	// PersistSeqNums keeps sequence numbers across logons and restarts instead of
+	// resetting them on every logon, so that missed messages are resent by the server.
+	PersistSeqNums bool
+
+	// CancelOnDisconnect cancels all orders of the session when the connection is lost.
+	CancelOnDisconnect bool
+	// AppID and AppSecret identify a registered Deribit application, if set.
+	AppID     string
+	AppSecret string
+	// LogonTimeout bounds the wait for the server to accept the logon. Defaults to 30 seconds.
+	LogonTimeout time.Duration
+
+	// OrderBookDepth is the MarketDepth of order book subscriptions, 0 for the full book.
+	OrderBookDepth int
+	// TradesDepth is the MarketDepth of trades subscriptions. Defaults to 1.
+	TradesDepth int
+
+	// AutoReconnect logs on again after the session is logged out and restores the subscriptions.
+	AutoReconnect bool
+	// ReconnectBackoff is the wait before the first reconnect attempt, doubled after each failure
+	// up to MaxReconnectBackoff. Defaults to one second and one minute.
+	ReconnectBackoff    time.Duration
+	MaxReconnectBackoff time.Duration
 }
 
 // Client implements the quickfix.Application interface.
 type Client struct {
 	log *zap.SugaredLogger
 
-	apiKey    string
-	secretKey string
+	apiKey             string
+	secretKey          string
+	appID              string
+	appSecret          string
+	persistSeqNums     bool
+	cancelOnDisconnect bool
+	logonTimeout       time.Duration
+
+	autoReconnect       bool
+	reconnectBackoff    time.Duration
+	maxReconnectBackoff time.Duration
+
+	orderBookDepth int
+	tradesDepth    int
 
 	settings *quickfix.Settings
 
@@ -59,14 +125,36 @@ type Client struct {
 
 	mu          sync.Mutex
 	isConnected bool
+	// logon receives the result of the logon while Start waits for it.
+	logon chan error
+	// disconnected is signaled to the supervisor when a logged on session is logged out.
+	disconnected   chan struct{}
+	ctx            context.Context
+	cancel         context.CancelFunc
+	supervisorDone chan struct{}
 
 	sending sync.Mutex
 	pending map[string]*call
 
 	subscriptions    []string
 	subscriptionsMap map[string]bool
-	emitter          *emission.Emitter
-	sender           Sender
+	// tickers holds the last statistics received for every instrument of ticker subscriptions.
+	tickers map[string]*models.TickerNotification
+	emitter *emission.Emitter
+	router  *router.Router
+	sender  Sender
+	clock   Clock
+
+	seenFills *idSet
+	// lastTradeSeqs holds the highest TradeSeq of the trades emitted by instrument, as trades are
+	// numbered by instrument.
+	lastTradeSeqs map[string]uint64
+
+	// quoting serializes mass quotes, quotes holds the last accepted entry of every quote set and entry ID.
+	quoting sync.Mutex
+	quotes  map[string]QuoteEntry
+	// filledQuotes holds the instruments filled since the last MassQuote started.
+	filledQuotes map[string]bool
 }
 
 type Dialer func(
@@ -82,10 +170,12 @@ func (c *Client) OnCreate(_ quickfix.SessionID) {}
 // OnLogon implemented as part of Application interface.
 func (c *Client) OnLogon(_ quickfix.SessionID) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	c.isConnected = true
 	c.log.Debugw("Logon successfully!")
+	c.notifyLogon(nil)
+	c.mu.Unlock()
+
+	c.Emit(EventConnected)
 }
 
 // OnLogout implemented as part of Application interface.
@@ -97,23 +187,73 @@ func (c *Client) OnLogout(_ quickfix.SessionID) {
 	}()
 
 	c.mu.Lock()
+	wasConnected := c.isConnected
 	c.isConnected = false
+	pending := c.pending
+	c.pending = make(map[string]*call)
+	// the quotes may be cancelled on disconnect, they are all sent by the next MassQuote
+	c.quotes = make(map[string]QuoteEntry)
 	c.mu.Unlock()
 
 	c.log.Debugw("Logged out!")
-	for _, call := range c.pending {
+	closed := make(map[*call]bool)
+	for _, call := range pending {
+		// a call can be registered under several IDs
+		if closed[call] {
+			continue
+		}
+		closed[call] = true
 		call.done <- ErrClosed
 		close(call.done)
 	}
+
+	if !wasConnected {
+		return
+	}
+	c.Emit(EventDisconnected)
+	select {
+	case c.disconnected <- struct{}{}:
+	default:
+	}
 }
 
 // FromAdmin implemented as part of Application interface.
-func (c *Client) FromAdmin(_ *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+func (c *Client) FromAdmin(msg *quickfix.Message, _ quickfix.SessionID) quickfix.MessageRejectError {
+	if msg.IsMsgTypeOf(string(enum.MsgType_LOGOUT)) && !c.IsConnected() {
+		c.rejectLogon(msg)
+	}
 	return nil
 }
 
+// rejectLogon fails a pending logon with the reason given in the Logout sent by the server.
+func (c *Client) rejectLogon(logout *quickfix.Message) {
+	reason, _ := logout.Body.GetString(tag.Text)
+	c.log.Warnw("Logon is rejected", "reason", reason)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notifyLogon(&LogonError{Reason: reason})
+}
+
+// notifyLogon must be called with c.mu held.
+func (c *Client) notifyLogon(err error) {
+	if c.logon == nil {
+		return
+	}
+	select {
+	case c.logon <- err:
+	default:
+	}
+}
+
 // ToAdmin implemented as part of Application interface.
 func (c *Client) ToAdmin(msg *quickfix.Message, _ quickfix.SessionID) {
+	// Only Logon carries the credentials, other session messages such as
+	// SequenceReset must be sent unchanged.
+	if !msg.IsMsgTypeOf(string(enum.MsgType_LOGON)) {
+		return
+	}
+
 	timestamp := time.Now().UnixMilli()
 	nonce, err := generateRandomBytes(nonceLen)
 	if err != nil {
@@ -126,18 +266,35 @@ func (c *Client) ToAdmin(msg *quickfix.Message, _ quickfix.SessionID) {
 	}
 
 	rawData := strconv.FormatInt(timestamp, 10) + "." + base64.StdEncoding.EncodeToString(nonce)
-	hash := sha256.Sum256([]byte(rawData + c.secretKey))
-	password := base64.StdEncoding.EncodeToString(hash[:])
 
 	msg.Body.Set(field.NewRawData(rawData))
 	msg.Body.Set(field.NewUsername(c.apiKey))
-	msg.Body.Set(field.NewPassword(password))
-	msg.Body.Set(field.NewResetSeqNumFlag(true))
-	msg.Body.SetBool(tagCancelOnDisconnect, false)
+	msg.Body.Set(field.NewPassword(signLogon(rawData, c.secretKey)))
+	if !c.persistSeqNums {
+		msg.Body.Set(field.NewResetSeqNumFlag(true))
+	}
+	msg.Body.SetBool(tagCancelOnDisconnect, c.cancelOnDisconnect)
+	if c.appID != "" {
+		msg.Body.SetString(tagDeribitAppID, c.appID)
+		msg.Body.SetString(tagDeribitAppSig, signLogon(rawData, c.appSecret))
+	}
+}
+
+// signLogon returns base64(sha256(RawData ++ secret)).
+func signLogon(rawData, secret string) string {
+	hash := sha256.Sum256([]byte(rawData + secret))
+	return base64.StdEncoding.EncodeToString(hash[:])
 }
 
 // ToApp implemented as a part of Application interface.
 func (c *Client) ToApp(msg *quickfix.Message, _ quickfix.SessionID) error {
+	// Messages requested by a ResendRequest are replaced by a gap fill: their calls
+	// already failed when the session logged out, and resending orders could execute them twice.
+	if possDup, err := msg.Header.GetBool(tag.PossDupFlag); err == nil && possDup {
+		c.log.Infow("Skip resending message", "msg", msg)
+		return quickfix.ErrDoNotSend
+	}
+
 	c.log.Debugw("Sending message to server", "msg", msg)
 	return nil
 }
@@ -159,6 +316,15 @@ func (c *Client) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.M
 		return nil
 	}
 
+	// ExecutionReports sent in response to an OrderMassStatusRequest carry its MassStatusReqID.
+	if reqIDTag == tag.OrigClOrdID && msg.Body.Has(tag.MassStatusReqID) {
+		reqIDTag = tag.MassStatusReqID
+	}
+	// TradeCaptureReports of trades done outside of a subscription are not requested.
+	if reqIDTag == tag.TradeRequestID && !msg.Body.Has(reqIDTag) {
+		return nil
+	}
+
 	id, err := msg.Body.GetString(reqIDTag)
 	if err != nil {
 		c.log.Errorw("Fail to get request ID", "tag", reqIDTag, "error", err)
@@ -167,25 +333,38 @@ func (c *Client) FromApp(msg *quickfix.Message, _ quickfix.SessionID) quickfix.M
 
 	c.mu.Lock()
 	call := c.pending[id]
-	delete(c.pending, id)
-	c.mu.Unlock()
+	if call == nil {
+		c.mu.Unlock()
+		return nil
+	}
 
-	if call != nil {
-		c.log.Debugw(
-			"Matching response message",
-			"id_tag", reqIDTag,
-			"id", id,
-			"request", call.request,
-			"response", msg,
-		)
-		response, err2 := copyMessage(msg)
-		if err2 != nil {
-			c.log.Fatalw("Fail to copy response message", "error", err2)
-		}
-		call.response = response
-		call.done <- nil
-		close(call.done)
+	c.log.Debugw(
+		"Matching response message",
+		"id_tag", reqIDTag,
+		"id", id,
+		"request", call.request,
+		"response", msg,
+	)
+	response, err2 := copyMessage(msg)
+	if err2 != nil {
+		c.log.Fatalw("Fail to copy response message", "error", err2)
+	}
+	call.response = response
+	call.responses = append(call.responses, response)
+
+	// wait for the remaining responses of a multi-response call
+	if call.isLast != nil && !call.isLast(msg) {
+		c.mu.Unlock()
+		return nil
+	}
+
+	for _, callID := range call.ids {
+		delete(c.pending, callID)
 	}
+	c.mu.Unlock()
+
+	call.done <- nil
+	close(call.done)
 
 	return nil
 }
@@ -215,30 +394,94 @@ func New(
 		logger.Errorw("Fail to read SenderCompID from settings", "error", err)
 		return nil, err
 	}
+
+	if cfg.AppID != "" && cfg.AppSecret == "" {
+		return nil, errors.New("empty app secret")
+	}
+	logonTimeout := cfg.LogonTimeout
+	if logonTimeout <= 0 {
+		logonTimeout = defaultLogonTimeout
+	}
+	reconnectBackoff := cfg.ReconnectBackoff
+	if reconnectBackoff <= 0 {
+		reconnectBackoff = defaultReconnectBackoff
+	}
+	if cfg.OrderBookDepth < 0 {
+		return nil, errors.New("negative order book depth")
+	}
+	tradesDepth := cfg.TradesDepth
+	if tradesDepth <= 0 {
+		tradesDepth = 1
+	}
+	maxReconnectBackoff := cfg.MaxReconnectBackoff
+	if maxReconnectBackoff < reconnectBackoff {
+		maxReconnectBackoff = defaultMaxReconnectBackoff
+	}
+
 	sender := cfg.Sender
 	if sender == nil {
 		sender = quickfix.Send
 	}
 
+	if cfg.PersistSeqNums {
+		if err = disableSeqNumsReset(cfg.Settings); err != nil {
+			logger.Errorw("Fail to persist sequence numbers", "error", err)
+			return nil, err
+		}
+	}
+
 	// Create a new Client object.
 	client := &Client{
-		log:              logger,
-		apiKey:           cfg.APIKey,
-		secretKey:        cfg.SecretKey,
-		settings:         cfg.Settings,
-		targetCompID:     targetCompID,
-		senderCompID:     senderCompID,
-		mu:               sync.Mutex{},
-		isConnected:      false,
-		sending:          sync.Mutex{},
-		pending:          make(map[string]*call),
-		subscriptionsMap: make(map[string]bool),
-		emitter:          emission.NewEmitter(),
-		sender:           sender,
+		log:                 logger,
+		apiKey:              cfg.APIKey,
+		secretKey:           cfg.SecretKey,
+		appID:               cfg.AppID,
+		appSecret:           cfg.AppSecret,
+		persistSeqNums:      cfg.PersistSeqNums,
+		cancelOnDisconnect:  cfg.CancelOnDisconnect,
+		logonTimeout:        logonTimeout,
+		autoReconnect:       cfg.AutoReconnect,
+		reconnectBackoff:    reconnectBackoff,
+		maxReconnectBackoff: maxReconnectBackoff,
+		orderBookDepth:      cfg.OrderBookDepth,
+		tradesDepth:         tradesDepth,
+		disconnected:        make(chan struct{}, 1),
+		supervisorDone:      make(chan struct{}),
+		settings:            cfg.Settings,
+		targetCompID:        targetCompID,
+		senderCompID:        senderCompID,
+		mu:                  sync.Mutex{},
+		isConnected:         false,
+		sending:             sync.Mutex{},
+		pending:             make(map[string]*call),
+		subscriptionsMap:    make(map[string]bool),
+		tickers:             make(map[string]*models.TickerNotification),
+		emitter:             emission.NewEmitter(),
+		router:              router.New(),
+		sender:              sender,
+		clock:               cfg.Clock,
+		seenFills:           newIDSet(seenFillsSize),
+		lastTradeSeqs:       make(map[string]uint64),
+		quotes:              make(map[string]QuoteEntry),
+		filledQuotes:        make(map[string]bool),
 	}
 
 	// Init session and logon to deribit FIX API server.
-	logFactory := quickfix.NewNullLogFactory()
+	logFactory := cfg.LogFactory
+	if logFactory == nil {
+		logFactory = quickfix.NewNullLogFactory()
+	}
+	logFactory = logonLogFactory{LogFactory: logFactory, c: client}
+
+	storeFactory := cfg.StoreFactory
+	if storeFactory == nil {
+		if cfg.FileStorePath != "" {
+			globalSettings.Set(config.FileStorePath, cfg.FileStorePath)
+			storeFactory = quickfix.NewFileStoreFactory(cfg.Settings)
+		} else {
+			storeFactory = quickfix.NewMemoryStoreFactory()
+		}
+	}
 
 	dialer := cfg.Dialer
 	if dialer == nil {
@@ -254,7 +497,7 @@ func New(
 
 	client.initiator, err = dialer(
 		client,
-		quickfix.NewMemoryStoreFactory(),
+		storeFactory,
 		cfg.Settings,
 		logFactory,
 	)
@@ -263,32 +506,84 @@ func New(
 		return nil, err
 	}
 
-	err = client.Start()
+	err = client.Start(ctx)
 	if err != nil {
 		client.log.Errorw("Fail to start fix connection", "error", err)
+		client.unregisterSessions()
 		return nil, err
 	}
 
+	client.ctx, client.cancel = context.WithCancel(context.Background())
+	go client.supervise()
+
 	return client, nil
 }
 
-func (c *Client) Start() error {
+// disableSeqNumsReset turns off the reset of sequence numbers on logon, logout and disconnect.
+func disableSeqNumsReset(settings *quickfix.Settings) error {
+	resetSettings := []string{config.ResetOnLogon, config.ResetOnLogout, config.ResetOnDisconnect}
+	for _, setting := range resetSettings {
+		settings.GlobalSettings().Set(setting, "N")
+	}
+
+	// session settings override the global ones
+	for sessionID, sessionSettings := range settings.SessionSettings() {
+		for _, setting := range resetSettings {
+			if reset, err := sessionSettings.BoolSetting(setting); err == nil && reset {
+				return fmt.Errorf("%w: %s is enabled for session %s", ErrSeqNumsReset, setting, sessionID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Start connects to the server and waits until the logon is accepted, rejected,
+// the logon timeout expires or ctx is done. The initiator is stopped on failure.
+func (c *Client) Start(ctx context.Context) error {
+	logon := make(chan error, 1)
 	c.mu.Lock()
 	c.subscriptionsMap = make(map[string]bool)
+	c.logon = logon
 	c.mu.Unlock()
 
+	defer func() {
+		c.mu.Lock()
+		c.logon = nil
+		c.mu.Unlock()
+	}()
+
 	if err := c.initiator.Start(); err != nil {
 		c.log.Errorw("Fail to initialize initiator", "error", err)
 		return err
 	}
 
 	// Wait for the session to be authorized by the server.
-	for !c.IsConnected() {
-		time.Sleep(10 * time.Millisecond)
+	timer := time.NewTimer(c.logonTimeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case err = <-logon:
+	case <-timer.C:
+		err = ErrLogonTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil {
+		c.log.Errorw("Fail to logon", "error", err)
+		c.initiator.Stop()
+		return err
 	}
 
-	if len(c.subscriptions) > 0 {
-		err := c.Subscribe(context.Background(), c.subscriptions)
+	// Subscribe adds the channels back to c.subscriptions.
+	c.mu.Lock()
+	subscriptions := c.subscriptions
+	c.subscriptions = nil
+	c.mu.Unlock()
+
+	if len(subscriptions) > 0 {
+		err := c.Subscribe(ctx, subscriptions)
 		if err != nil {
 			c.log.Warnw("Fail to resubscribe to channels", "error", err)
 		}
@@ -304,9 +599,99 @@ func (c *Client) IsConnected() bool {
 	return c.isConnected
 }
 
+// Latency returns the time between an exchange timestamp, e.g. MDEntryDate or TransactTime,
+// and the local time it was received, using the server clock offset if a Clock is set.
+func (c *Client) Latency(exchangeTime time.Time, receivedAt time.Time) time.Duration {
+	if c.clock != nil {
+		receivedAt = c.clock.ToServerTime(receivedAt)
+	}
+	return receivedAt.Sub(exchangeTime)
+}
+
 // Close closes underlying connection.
 func (c *Client) Close() {
+	c.cancel()
+	<-c.supervisorDone
 	c.initiator.Stop()
+	c.unregisterSessions()
+}
+
+// unregisterSessions releases the session IDs registered by quickfix so that a new client can use them.
+func (c *Client) unregisterSessions() {
+	for sessionID := range c.settings.SessionSettings() {
+		_ = quickfix.UnregisterSession(sessionID)
+	}
+}
+
+// supervise expires orphaned pending calls and, with auto reconnect, logs on again after a logout.
+func (c *Client) supervise() {
+	defer close(c.supervisorDone)
+
+	t := time.NewTicker(expirePendingInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-t.C:
+			c.expirePendingCalls()
+		case <-c.disconnected:
+			if c.autoReconnect {
+				c.reconnect()
+			}
+		}
+	}
+}
+
+// reconnect restarts the initiator with an exponential backoff until the logon succeeds.
+func (c *Client) reconnect() {
+	// quickfix would redial on its own, but without restoring the subscriptions.
+	c.initiator.Stop()
+
+	backoff := c.reconnectBackoff
+	for {
+		c.log.Infow("Reconnecting", "backoff", backoff)
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		err := c.Start(c.ctx)
+		if err == nil {
+			// the session may be logged out again while resubscribing
+			select {
+			case <-c.disconnected:
+			default:
+			}
+			if c.IsConnected() {
+				c.log.Infow("Reconnect successfully")
+				return
+			}
+			c.initiator.Stop()
+		} else {
+			c.log.Warnw("Fail to reconnect", "error", err)
+		}
+
+		backoff *= 2
+		if backoff > c.maxReconnectBackoff {
+			backoff = c.maxReconnectBackoff
+		}
+	}
+}
+
+// expirePendingCalls removes the calls whose context is done, as their response will never be awaited.
+func (c *Client) expirePendingCalls() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, call := range c.pending {
+		if call.ctx.Err() != nil {
+			c.log.Debugw("Expire pending call", "id", id)
+			delete(c.pending, id)
+		}
+	}
 }
 
 // nolint:funlen,gocognit,cyclop
@@ -334,6 +719,8 @@ func (c *Client) handleSubscriptions(msgType string, msg *quickfix.Message) {
 		}
 
 		var tradesEvent models.TradesNotification
+		var tickerEntries []tickerEntry
+		var tickerTime time.Time
 		orderBookEvent := models.OrderBookRawNotification{
 			InstrumentName: symbol,
 		}
@@ -345,6 +732,19 @@ func (c *Client) handleSubscriptions(msgType string, msg *quickfix.Message) {
 				continue
 			}
 
+			if isTickerEntryType(entryType) {
+				value, err := getMDEntryValue(entry)
+				if err != nil {
+					logger.Warnw("Fail to get ticker entry value", "type", entryType, "error", err)
+					continue
+				}
+				tickerEntries = append(tickerEntries, tickerEntry{entryType: entryType, value: value})
+				if serverTime, err := getMDEntryDate(entry); err == nil {
+					tickerTime = serverTime
+				}
+				continue
+			}
+
 			if entryType != enum.MDEntryType_BID &&
 				entryType != enum.MDEntryType_OFFER &&
 				entryType != enum.MDEntryType_TRADE {
@@ -442,11 +842,120 @@ func (c *Client) handleSubscriptions(msgType string, msg *quickfix.Message) {
 		if len(tradesEvent) > 0 {
 			c.Emit(newTradeNotificationChannel(symbol), &tradesEvent)
 		}
+
+		if len(tickerEntries) > 0 {
+			isSnapshot := msgType == string(enum.MsgType_MARKET_DATA_SNAPSHOT_FULL_REFRESH)
+			ticker := c.updateTicker(symbol, isSnapshot, tickerEntries, markPrice, tickerTime)
+			c.Emit(newTickerNotificationChannel(symbol), ticker)
+		}
+	case enum.MsgType_EXECUTION_REPORT:
+		c.handleExecutionReport(msg)
+	case enum.MsgType_TRADE_CAPTURE_REPORT:
+		c.handleTradeCaptureReport(msg)
 	default:
 		return
 	}
 }
 
+// handleExecutionReport emits order updates and new fills of every ExecutionReport,
+// including fills, expiries, cancels and rejections done by the server, on the same channels
+// and with the same types as the websocket client.
+func (c *Client) handleExecutionReport(msg *quickfix.Message) {
+	logger := c.log.With("msg", msg)
+
+	// responses to OrderMassStatusRequest only repeat the current state of orders
+	if msg.Body.Has(tag.MassStatusReqID) {
+		return
+	}
+
+	// a rejection is an error of the request it answers, it is emitted as an order update here
+	if status, err := getOrderStatus(msg); err == nil && status == enum.OrdStatus_REJECTED {
+		order, err := decodeRejectedOrder(msg)
+		if err != nil {
+			logger.Warnw("Fail to decode rejected order", "error", err)
+			return
+		}
+		reason, _ := getText(msg)
+		logger.Infow("Order is rejected", "order_id", order.OrderID, "reason", reason)
+		c.Emit(newUserOrdersNotificationChannel(order.InstrumentName), &order)
+		return
+	}
+
+	order, err := decodeExecutionReport(msg)
+	if err != nil {
+		logger.Debugw("Skip ExecutionReport", "error", err)
+		return
+	}
+
+	trades, err := decodeFills(msg, order)
+	if err != nil {
+		logger.Warnw("Fail to decode fills", "error", err)
+	}
+	if len(trades) > 0 {
+		c.invalidateQuotes(order.InstrumentName)
+	}
+
+	c.Emit(newUserOrdersNotificationChannel(order.InstrumentName), &order)
+	// Reports of the same order repeat its previous fills.
+	c.emitUserTrades(trades)
+}
+
+// handleTradeCaptureReport emits the trade of a TradeCaptureReport pushed by the server on a
+// drop copy session. Reports replayed in response to a pending request are returned by the request.
+func (c *Client) handleTradeCaptureReport(msg *quickfix.Message) {
+	reqID, _ := msg.Body.GetString(tag.TradeRequestID)
+	c.mu.Lock()
+	_, replayed := c.pending[reqID]
+	c.mu.Unlock()
+	if replayed {
+		return
+	}
+
+	trade, err := decodeTradeCaptureReport(msg)
+	if err != nil {
+		c.log.Warnw("Fail to decode TradeCaptureReport", "msg", msg, "error", err)
+		return
+	}
+	c.emitUserTrades(models.UserTradesNotification{trade})
+}
+
+// emitUserTrades emits the trades not emitted yet on the user trades channel of their instrument.
+func (c *Client) emitUserTrades(trades models.UserTradesNotification) {
+	byInstrument := make(map[string]models.UserTradesNotification)
+	var instruments []string
+	for _, trade := range c.addSeenTrades(trades) {
+		if _, ok := byInstrument[trade.InstrumentName]; !ok {
+			instruments = append(instruments, trade.InstrumentName)
+		}
+		byInstrument[trade.InstrumentName] = append(byInstrument[trade.InstrumentName], trade)
+	}
+
+	for _, instrument := range instruments {
+		newTrades := byInstrument[instrument]
+		c.Emit(newUserTradesNotificationChannel(instrument), &newTrades)
+	}
+}
+
+// addSeenTrades marks trades as seen and returns the ones not seen before.
+// Fills are identified by order and trade ID.
+func (c *Client) addSeenTrades(trades []models.UserTrade) []models.UserTrade {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var newTrades []models.UserTrade
+	for _, trade := range trades {
+		// both sides of a self-trade have the same trade ID
+		if !c.seenFills.Add(trade.OrderID + "/" + trade.TradeID) {
+			continue
+		}
+		if trade.TradeSeq > c.lastTradeSeqs[trade.InstrumentName] {
+			c.lastTradeSeqs[trade.InstrumentName] = trade.TradeSeq
+		}
+		newTrades = append(newTrades, trade)
+	}
+	return newTrades
+}
+
 func (c *Client) addCommonHeaders(msg *quickfix.Message) {
 	msg.Header.Set(field.NewBeginString(fixVersion))
 	msg.Header.Set(field.NewTargetCompID(c.targetCompID))
@@ -454,9 +963,19 @@ func (c *Client) addCommonHeaders(msg *quickfix.Message) {
 	msg.Header.Set(field.NewSendingTime(time.Now().UTC()))
 }
 
+// send sends msg and, if wait is true, registers a pending call matched by id and any aliases.
 func (c *Client) send(
-	_ context.Context, id string, msg *quickfix.Message, wait bool,
+	ctx context.Context, id string, msg *quickfix.Message, wait bool, aliases ...string,
 ) (Waiter, error) {
+	var cc *call
+	if wait {
+		cc = &call{ids: append([]string{id}, aliases...)}
+	}
+	return c.sendCall(ctx, msg, cc)
+}
+
+// sendCall sends msg and registers cc, if not nil, as pending until its response is received.
+func (c *Client) sendCall(ctx context.Context, msg *quickfix.Message, cc *call) (Waiter, error) {
 	c.sending.Lock()
 	defer c.sending.Unlock()
 
@@ -467,17 +986,24 @@ func (c *Client) send(
 	}
 
 	c.addCommonHeaders(msg)
-	var cc *call
-	if wait {
-		cc = &call{request: msg, done: make(chan error, 1)}
-		c.pending[id] = cc
+	if cc != nil {
+		cc.ctx = ctx
+		cc.request = msg
+		cc.done = make(chan error, 1)
+		for _, callID := range cc.ids {
+			c.pending[callID] = cc
+		}
 	}
 	c.mu.Unlock()
 
 	if err := c.sender(msg); err != nil {
-		c.mu.Lock()
-		delete(c.pending, id)
-		c.mu.Unlock()
+		if cc != nil {
+			c.mu.Lock()
+			for _, callID := range cc.ids {
+				delete(c.pending, callID)
+			}
+			c.mu.Unlock()
+		}
 		return Waiter{}, err
 	}
 
@@ -488,7 +1014,14 @@ func (c *Client) send(
 func (c *Client) Call(
 	ctx context.Context, id string, msg *quickfix.Message,
 ) (*quickfix.Message, error) {
-	call, err := c.send(ctx, id, msg, true)
+	return c.callWithAliases(ctx, id, msg)
+}
+
+// callWithAliases initiates a FIX call whose response may be matched by id or any of the aliases.
+func (c *Client) callWithAliases(
+	ctx context.Context, id string, msg *quickfix.Message, aliases ...string,
+) (*quickfix.Message, error) {
+	call, err := c.send(ctx, id, msg, true, aliases...)
 	if err != nil {
 		return nil, err
 	}
@@ -496,10 +1029,30 @@ func (c *Client) Call(
 	return call.Wait(ctx)
 }
 
+// callMulti initiates a FIX call answered by several messages, the last of which satisfies isLast.
+func (c *Client) callMulti(
+	ctx context.Context, id string, msg *quickfix.Message, isLast func(*quickfix.Message) bool,
+) ([]*quickfix.Message, error) {
+	cc := &call{ids: []string{id}, isLast: isLast}
+	w, err := c.sendCall(ctx, msg, cc)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return cc.responses, nil
+}
+
 type call struct {
-	request  *quickfix.Message
-	response *quickfix.Message
-	done     chan error
+	ctx       context.Context
+	ids       []string
+	request   *quickfix.Message
+	response  *quickfix.Message
+	responses []*quickfix.Message
+	isLast    func(*quickfix.Message) bool
+	done      chan error
 }
 
 // Waiter proxies an ongoing FIX call.
@@ -576,7 +1129,7 @@ func (c *Client) SubscribeOrderBooks(ctx context.Context, instruments []string)
 		return nil
 	}
 
-	marketDepth := 0
+	marketDepth := c.orderBookDepth
 	mdUpdateType := enum.MDUpdateType_INCREMENTAL_REFRESH
 	msg, err := c.MarketDataRequest(
 		ctx,
@@ -648,7 +1201,7 @@ func (c *Client) SubscribeTrades(ctx context.Context, instruments []string) erro
 		return nil
 	}
 
-	marketDepth := 1
+	marketDepth := c.tradesDepth
 	mdUpdateType := enum.MDUpdateType_INCREMENTAL_REFRESH
 	msg, err := c.MarketDataRequest(
 		ctx,
@@ -712,8 +1265,107 @@ func (c *Client) UnsubscribeTrades(ctx context.Context, instruments []string) er
 	return nil
 }
 
+// updateTicker merges the entries of a market data message into the last ticker of the instrument
+// and returns a copy of it. A snapshot replaces all previous statistics.
+func (c *Client) updateTicker(
+	instrument string, isSnapshot bool, entries []tickerEntry, markPrice float64, serverTime time.Time,
+) *models.TickerNotification {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ticker := c.tickers[instrument]
+	if ticker == nil || isSnapshot {
+		ticker = &models.TickerNotification{InstrumentName: instrument}
+		c.tickers[instrument] = ticker
+	}
+	for _, entry := range entries {
+		entry.apply(ticker)
+	}
+	ticker.MarkPrice = markPrice
+	if !serverTime.IsZero() {
+		ticker.Timestamp = uint64(serverTime.UnixMilli())
+	}
+
+	out := *ticker
+	return &out
+}
+
+// SubscribeTickers subscribes to index, settlement, open interest and session statistics of instruments,
+// which are emitted as models.TickerNotification on ticker.<instrument>.
+func (c *Client) SubscribeTickers(ctx context.Context, instruments []string) error {
+	if len(instruments) == 0 {
+		c.log.Debugw("No instruments to subscribe")
+		return nil
+	}
+
+	marketDepth := tickerMarketDepth
+	mdUpdateType := enum.MDUpdateType_INCREMENTAL_REFRESH
+	msg, err := c.MarketDataRequest(
+		ctx,
+		enum.SubscriptionRequestType_SNAPSHOT_PLUS_UPDATES,
+		&marketDepth,
+		&mdUpdateType,
+		tickerEntryTypes,
+		instruments,
+	)
+	if err != nil {
+		c.log.Errorw("Fail to subscribe tickers", "error", err)
+		return err
+	}
+
+	if msg.IsMsgTypeOf(string(enum.MsgType_MARKET_DATA_REQUEST_REJECT)) {
+		reason, err := getText(msg)
+		if err != nil {
+			c.log.Warnw("No value for Text field", "error", err)
+		} else {
+			err = errors.New(reason)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) UnsubscribeTickers(ctx context.Context, instruments []string) error {
+	if len(instruments) == 0 {
+		c.log.Debugw("No instruments to unsubscribe")
+		return nil
+	}
+
+	msg, err := c.MarketDataRequest(
+		ctx,
+		enum.SubscriptionRequestType_DISABLE_PREVIOUS_SNAPSHOT_PLUS_UPDATE_REQUEST,
+		nil,
+		nil,
+		tickerEntryTypes,
+		instruments,
+	)
+	if err != nil {
+		c.log.Errorw("Fail to unsubscribe tickers", "error", err)
+		return err
+	}
+
+	if msg.IsMsgTypeOf(string(enum.MsgType_MARKET_DATA_REQUEST_REJECT)) {
+		reason, err := getText(msg)
+		if err != nil {
+			c.log.Warnw("No value for Text field", "error", err)
+		} else {
+			err = errors.New(reason)
+		}
+		return err
+	}
+
+	c.mu.Lock()
+	for _, instrument := range instruments {
+		delete(c.tickers, instrument)
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
 // Subscribe listens for notifications.
-// Currently, only support for orderbook notifications.
+// Currently, only support for book.<instrument>, trades.<instrument> and ticker.<instrument> channels.
 // nolint: cyclop
 func (c *Client) Subscribe(ctx context.Context, channels []string) error {
 	c.mu.Lock()
@@ -725,8 +1377,9 @@ func (c *Client) Subscribe(ctx context.Context, channels []string) error {
 				continue // Ignore channels don't have format <SubType>.<Instrument>
 			}
 
-			if parts[0] != subscriptionTypeBook && parts[0] != subscriptionTypeTrades {
-				continue // Support only book.<Instrument> and trades.<Instrument>
+			if parts[0] != subscriptionTypeBook && parts[0] != subscriptionTypeTrades &&
+				parts[0] != subscriptionTypeTicker {
+				continue // Support only book.<Instrument>, trades.<Instrument> and ticker.<Instrument>
 			}
 
 			c.subscriptionsMap[channel] = true
@@ -754,6 +1407,12 @@ func (c *Client) Subscribe(ctx context.Context, channels []string) error {
 				c.log.Errorw("Fail to subscribe trades notifications", "error", err)
 				return err
 			}
+		case subscriptionTypeTicker:
+			err := c.SubscribeTickers(ctx, instruments)
+			if err != nil {
+				c.log.Errorw("Fail to subscribe ticker notifications", "error", err)
+				return err
+			}
 		}
 	}
 
@@ -790,6 +1449,12 @@ func (c *Client) Unsubscribe(ctx context.Context, channels []string) error {
 				c.log.Errorw("Fail to unsubscribe trades notifications", "error", err)
 				return err
 			}
+		case subscriptionTypeTicker:
+			err := c.UnsubscribeTickers(ctx, instruments)
+			if err != nil {
+				c.log.Errorw("Fail to unsubscribe ticker notifications", "error", err)
+				return err
+			}
 		}
 	}
 
@@ -875,3 +1540,322 @@ func (c *Client) CreateOrder(
 
 	return order, nil
 }
+
+// CancelOrder cancels an order by its Deribit order ID.
+// The ExecutionReport response is matched on OrigClOrdID and the OrderCancelReject on ClOrdID,
+// which is returned as ErrOrderCancelRejected.
+func (c *Client) CancelOrder(ctx context.Context, orderID string) (order models.Order, err error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return order, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REQUEST))
+
+	msg.Body.Set(field.NewClOrdID(id.String()))
+	msg.Body.Set(field.NewOrigClOrdID(orderID))
+
+	resp, err := c.callWithAliases(ctx, id.String(), msg, orderID)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to cancel order",
+			"request", msg,
+			"error", err,
+		)
+		return order, err
+	}
+
+	return c.decodeOrderResponse(msg, resp)
+}
+
+// ReplaceOrder amends the amount and price of an order by its Deribit order ID.
+// A rejection is returned as ErrOrderCancelRejected.
+// nolint:funlen
+func (c *Client) ReplaceOrder(
+	ctx context.Context,
+	orderID string,
+	instrument string,
+	side enum.Side,
+	amount float64,
+	price float64,
+	orderType enum.OrdType,
+) (order models.Order, err error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return order, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST))
+
+	msg.Body.Set(field.NewClOrdID(id.String()))
+	msg.Body.Set(field.NewOrigClOrdID(orderID))
+	msg.Body.Set(field.NewSymbol(instrument))
+	msg.Body.Set(field.NewSide(side))
+	msg.Body.SetString(tag.OrderQty, floatToStr(amount))
+	msg.Body.SetString(tag.Price, floatToStr(price))
+	msg.Body.Set(field.NewOrdType(orderType))
+
+	resp, err := c.callWithAliases(ctx, id.String(), msg, orderID)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to replace order",
+			"request", msg,
+			"error", err,
+		)
+		return order, err
+	}
+
+	order, err = c.decodeOrderResponse(msg, resp)
+	if err != nil {
+		return order, err
+	}
+
+	order.Replaced = true
+	return order, nil
+}
+
+// MassCancel cancels all orders (CANCEL_ALL_ORDERS), the orders of an instrument
+// (CANCEL_ORDERS_FOR_A_SECURITY) or the orders of a security type and currency
+// (CANCEL_ORDERS_FOR_A_SECURITYTYPE). It returns the number of cancelled orders.
+func (c *Client) MassCancel(
+	ctx context.Context,
+	requestType enum.MassCancelRequestType,
+	instrument string,
+	securityType enum.SecurityType,
+	currency string,
+) (uint, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return 0, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_MASS_CANCEL_REQUEST))
+
+	msg.Body.Set(field.NewClOrdID(id.String()))
+	msg.Body.Set(field.NewMassCancelRequestType(requestType))
+	if instrument != "" {
+		msg.Body.Set(field.NewSymbol(instrument))
+	}
+	if securityType != "" {
+		msg.Body.Set(field.NewSecurityType(securityType))
+	}
+	if currency != "" {
+		msg.Body.Set(field.NewCurrency(currency))
+	}
+
+	resp, err := c.Call(ctx, id.String(), msg)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to mass cancel orders",
+			"request", msg,
+			"error", err,
+		)
+		return 0, err
+	}
+
+	count, err := decodeOrderMassCancelReport(resp)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to decode OrderMassCancelReport message",
+			"request", msg,
+			"response", resp,
+			"error", err,
+		)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// decodeOrderResponse decodes the response of an order request,
+// which is either an ExecutionReport or an OrderCancelReject.
+func (c *Client) decodeOrderResponse(req, resp *quickfix.Message) (order models.Order, err error) {
+	if resp.IsMsgTypeOf(string(enum.MsgType_ORDER_CANCEL_REJECT)) {
+		return order, decodeOrderCancelReject(resp)
+	}
+
+	order, err = decodeExecutionReport(resp)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to decode ExecutionReport message",
+			"request", req,
+			"response", resp,
+			"error", err,
+		)
+		return order, err
+	}
+
+	return order, nil
+}
+
+// OrderStatus requests the current state of an order by its Deribit order ID.
+func (c *Client) OrderStatus(ctx context.Context, orderID string) (order models.Order, err error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return order, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_STATUS_REQUEST))
+
+	msg.Body.Set(field.NewClOrdID(id.String()))
+	msg.Body.Set(field.NewOrigClOrdID(orderID))
+
+	resp, err := c.callWithAliases(ctx, id.String(), msg, orderID)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to request order status",
+			"request", msg,
+			"error", err,
+		)
+		return order, err
+	}
+
+	return c.decodeOrderResponse(msg, resp)
+}
+
+// OrderMassStatus requests the state of all open orders, or of the open orders
+// of an instrument if it is not empty.
+func (c *Client) OrderMassStatus(ctx context.Context, instrument string) ([]models.Order, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_ORDER_MASS_STATUS_REQUEST))
+
+	msg.Body.Set(field.NewMassStatusReqID(id.String()))
+	if instrument != "" {
+		msg.Body.Set(field.NewMassStatusReqType(enum.MassStatusReqType_STATUS_FOR_ORDERS_FOR_A_SECURITY))
+		msg.Body.Set(field.NewSymbol(instrument))
+	} else {
+		msg.Body.Set(field.NewMassStatusReqType(enum.MassStatusReqType_STATUS_FOR_ALL_ORDERS))
+	}
+
+	resps, err := c.callMulti(ctx, id.String(), msg, isLastReportRequested)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to request order mass status",
+			"request", msg,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	orders := make([]models.Order, 0, len(resps))
+	for _, resp := range resps {
+		// the only report has no order when there are no open orders
+		if !resp.Body.Has(tag.OrderID) {
+			continue
+		}
+
+		order, err := c.decodeOrderResponse(msg, resp)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// GetPositions requests the positions of a currency, optionally filtered by kind.
+func (c *Client) GetPositions(ctx context.Context, params *models.GetPositionsParams) ([]models.Position, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_REQUEST_FOR_POSITIONS))
+
+	msg.Body.Set(field.NewPosReqID(id.String()))
+	msg.Body.Set(field.NewPosReqType(enum.PosReqType_POSITIONS))
+	msg.Body.Set(field.NewSubscriptionRequestType(enum.SubscriptionRequestType_SNAPSHOT))
+	msg.Body.Set(field.NewCurrency(params.Currency))
+	if params.Kind != "" {
+		msg.Body.Set(field.NewSecurityType(encodeSecurityType(params.Kind)))
+	}
+
+	resp, err := c.Call(ctx, id.String(), msg)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to request positions",
+			"request", msg,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	positions, err := decodePositionReport(resp)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to decode PositionReport message",
+			"request", msg,
+			"response", resp,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	return positions, nil
+}
+
+// GetInstruments requests the active instruments of a currency, optionally filtered by kind.
+// Together with multicast instrument IDs, it lets Client act as a multicast.InstrumentsGetter.
+func (c *Client) GetInstruments(
+	ctx context.Context,
+	params *models.GetInstrumentsParams,
+) ([]models.Instrument, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_SECURITY_LIST_REQUEST))
+
+	msg.Body.Set(field.NewSecurityReqID(id.String()))
+	msg.Body.Set(field.NewSecurityListRequestType(enum.SecurityListRequestType_SYMBOL))
+	msg.Body.SetBool(tagDisplayMulticastInstrumentID, true)
+	if params.Currency != "" {
+		msg.Body.Set(field.NewCurrency(params.Currency))
+	}
+	if params.Kind != "" {
+		msg.Body.Set(field.NewSecurityType(encodeSecurityType(params.Kind)))
+	}
+
+	resp, err := c.Call(ctx, id.String(), msg)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to request security list",
+			"request", msg,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	instruments, err := decodeSecurityList(resp)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to decode SecurityList message",
+			"request", msg,
+			"response", resp,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	return instruments, nil
+}
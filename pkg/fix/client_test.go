@@ -3,15 +3,22 @@ package fix
 import (
 	"bytes"
 	"context"
+	"errors"
 	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast"
+	"github.com/KyberNetwork/deribit-api/pkg/router"
+	"github.com/chuckpreslar/emission"
 	"github.com/quickfixgo/enum"
 	"github.com/quickfixgo/field"
 	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -21,6 +28,8 @@ const (
 	responseTime = 100 * time.Microsecond
 )
 
+var _ multicast.InstrumentsGetter = (*Client)(nil)
+
 // nolint:gochecknoglobals
 var (
 	mockInitiator Initiator
@@ -205,6 +214,127 @@ func (ts *FixTestSuite) TestHandleSubscriptions() {
 	}
 }
 
+func (ts *FixTestSuite) TestHandleTicker() {
+	require := ts.Require()
+
+	newMarketDataMessage := func(msgType enum.MsgType, entries map[enum.MDEntryType]string) *quickfix.Message {
+		msg := newTestMessage(msgType)
+		msg.Body.SetString(tag.Symbol, "BTC-PERPETUAL")
+		msg.Body.SetString(tagMarkPrice, "21000.5")
+		group := newSnapshotNoMDEntriesRepeatingGroup()
+		if msgType == enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH {
+			group = newNoMDEntriesRepeatingGroup()
+		}
+		for _, entryType := range tickerEntryTypes {
+			value, ok := entries[entryType]
+			if !ok {
+				continue
+			}
+			entry := group.Add()
+			if msgType == enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH {
+				entry.SetString(tag.MDUpdateAction, string(enum.MDUpdateAction_CHANGE))
+			}
+			entry.SetString(tag.MDEntryType, string(entryType))
+			if entryType == enum.MDEntryType_TRADE_VOLUME || entryType == enum.MDEntryType_OPEN_INTEREST {
+				entry.SetString(tag.MDEntrySize, value)
+			} else {
+				entry.SetString(tag.MDEntryPx, value)
+			}
+			entry.SetString(tag.MDEntryDate, "20220815-10:39:21.568")
+		}
+		msg.Body.SetGroup(group)
+
+		out, err := copyMessage(msg)
+		require.NoError(err)
+		return out
+	}
+
+	tickers := make(chan *models.TickerNotification, 2)
+	listener := func(ticker *models.TickerNotification) {
+		tickers <- ticker
+	}
+	ts.c.On("ticker.BTC-PERPETUAL", listener)
+	defer ts.c.Off("ticker.BTC-PERPETUAL", listener)
+
+	snapshot := newMarketDataMessage(enum.MsgType_MARKET_DATA_SNAPSHOT_FULL_REFRESH, map[enum.MDEntryType]string{
+		enum.MDEntryType_INDEX_VALUE:                "21001.25",
+		enum.MDEntryType_SETTLEMENT_PRICE:           "20950",
+		enum.MDEntryType_TRADING_SESSION_HIGH_PRICE: "21500",
+		enum.MDEntryType_TRADING_SESSION_LOW_PRICE:  "20500",
+		enum.MDEntryType_TRADE_VOLUME:               "1234.5",
+		enum.MDEntryType_OPEN_INTEREST:              "987654321",
+	})
+	ts.c.handleSubscriptions(string(enum.MsgType_MARKET_DATA_SNAPSHOT_FULL_REFRESH), snapshot)
+	require.Len(tickers, 1)
+	require.Equal(&models.TickerNotification{
+		Timestamp:       1660559961568,
+		Stats:           models.Stats{Volume: 1234.5, Low: 20500, High: 21500},
+		SettlementPrice: 20950,
+		OpenInterest:    987654321,
+		MarkPrice:       21000.5,
+		InstrumentName:  "BTC-PERPETUAL",
+		IndexPrice:      21001.25,
+	}, <-tickers)
+
+	// updates are merged into the last ticker
+	update := newMarketDataMessage(enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH, map[enum.MDEntryType]string{
+		enum.MDEntryType_INDEX_VALUE: "21010",
+	})
+	ts.c.handleSubscriptions(string(enum.MsgType_MARKET_DATA_INCREMENTAL_REFRESH), update)
+	require.Len(tickers, 1)
+	ticker := <-tickers
+	require.Equal(21010.0, ticker.IndexPrice)
+	require.Equal(20950.0, ticker.SettlementPrice)
+	require.Equal(987654321.0, ticker.OpenInterest)
+}
+
+func (ts *FixTestSuite) TestPatternListener() {
+	require := ts.Require()
+
+	tickers := make(chan *models.TickerNotification, 2)
+	listener := func(ticker *models.TickerNotification) {
+		tickers <- ticker
+	}
+	ts.c.On("ticker.*-PERPETUAL", listener)
+
+	perpetual := &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"}
+	ts.c.Emit(newTickerNotificationChannel("BTC-PERPETUAL"), perpetual)
+	ts.c.Emit(newTickerNotificationChannel("BTC-30DEC22"), &models.TickerNotification{})
+	require.Len(tickers, 1)
+	require.Equal(perpetual, <-tickers)
+
+	ts.c.Off("ticker.*-PERPETUAL", listener)
+	ts.c.Emit(newTickerNotificationChannel("ETH-PERPETUAL"), perpetual)
+	require.Len(tickers, 0)
+}
+
+func (ts *FixTestSuite) TestMarketDepth() {
+	require := ts.Require()
+
+	errSent := errors.New("sent")
+	var marketDepth int
+	c := &Client{
+		log:            ts.c.log,
+		isConnected:    true,
+		pending:        make(map[string]*call),
+		orderBookDepth: 10,
+		tradesDepth:    1,
+		sender: func(m quickfix.Messagable) error {
+			var err error
+			marketDepth, err = m.ToMessage().Body.GetInt(tag.MarketDepth)
+			require.NoError(err)
+			return errSent
+		},
+	}
+
+	require.ErrorIs(c.SubscribeOrderBooks(context.Background(), []string{"BTC-PERPETUAL"}), errSent)
+	require.Equal(10, marketDepth)
+	require.ErrorIs(c.SubscribeTrades(context.Background(), []string{"BTC-PERPETUAL"}), errSent)
+	require.Equal(1, marketDepth)
+	require.ErrorIs(c.SubscribeTickers(context.Background(), []string{"BTC-PERPETUAL"}), errSent)
+	require.Equal(tickerMarketDepth, marketDepth)
+}
+
 func (ts *FixTestSuite) TestSend() {
 	assert := ts.Assert()
 	wait := true
@@ -651,6 +781,364 @@ func (ts *FixTestSuite) TestCreateOrder() {
 	require.Equal(res, models.Order{})
 }
 
+// nolint:lll,funlen
+func (ts *FixTestSuite) TestCancelOrder() {
+	require := ts.Require()
+	sendResp := make(chan bool)
+	orderID := "14020845373"
+
+	go func() {
+		// the ExecutionReport is matched on OrigClOrdID, the OrderCancelReject on ClOrdID
+		msgStrings := []string{
+			"8=FIX.4.4\u00019=316\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=9158\u000152=20220818-06:37:42.584\u0001527=14020845373\u000137=14020845373\u000111=14020845373\u000141=14020845373\u0001150=4\u000139=4\u000154=1\u000160=20220818-06:37:42.583\u000112=0\u0001151=0.1000\u000114=0\u000138=0.1000\u000140=2\u000144=0.077\u0001103=0\u000158=success\u0001207=DERIBITSERVER\u000155=BTC-19AUG22-21000-C\u0001854=1\u0001231=1.0000\u00016=0\u0001210=0.1000\u0001100010=test\u000110=040\u0001",
+			"8=FIX.4.4\u00019=80\u000135=9\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=9159\u000141=14020845373\u000139=8\u000158=not_open_order\u000110=082\u0001",
+		}
+		for i, msgStr := range msgStrings {
+			<-sendResp
+			time.Sleep(responseTime)
+			respMsg := getMsgFromString(msgStr)
+
+			if i == 1 {
+				mutex.Lock()
+				respMsg.Body.Set(field.NewClOrdID(requestID))
+				mutex.Unlock()
+			}
+
+			err := mockDeribitResponse(respMsg)
+			require.NoError(err)
+		}
+	}()
+
+	// success case
+	sendResp <- true
+	res, err := ts.c.CancelOrder(context.Background(), orderID)
+	require.NoError(err)
+	require.Equal("cancelled", res.OrderState)
+	require.Equal(orderID, res.OrderID)
+	require.Equal("BTC-19AUG22-21000-C", res.InstrumentName)
+	require.Empty(ts.c.pending)
+
+	// reject case
+	sendResp <- true
+	res, err = ts.c.CancelOrder(context.Background(), orderID)
+	require.ErrorIs(err, ErrOrderCancelRejected)
+	require.EqualError(err, "order cancel rejected: not_open_order")
+	require.Equal(models.Order{}, res)
+	require.Empty(ts.c.pending)
+}
+
+// nolint:lll
+func (ts *FixTestSuite) TestReplaceOrder() {
+	require := ts.Require()
+	orderID := "14230452591"
+
+	go func() {
+		time.Sleep(responseTime)
+		msgStr := "8=FIX.4.4\u00019=306\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=7534\u000152=20220912-11:12:19.623\u0001527=14230452591\u000137=14230452591\u000111=14230452591\u000141=14230452591\u0001150=5\u000139=0\u000154=1\u000160=20220912-11:12:19.623\u000112=0\u0001151=20.0\u000114=0\u000138=20.0\u000140=2\u000144=0.08\u0001103=0\u000158=success\u0001207=DERIBITSERVER\u000155=BTC-30JUN23-14000-P\u0001854=1\u0001231=1.0\u00016=0\u0001210=20.0\u0001100010=test\u000110=047\u0001"
+		err := mockDeribitResponse(getMsgFromString(msgStr))
+		require.NoError(err)
+	}()
+
+	res, err := ts.c.ReplaceOrder(
+		context.Background(),
+		orderID,
+		"BTC-30JUN23-14000-P",
+		enum.Side_BUY,
+		20,
+		0.08,
+		enum.OrdType_LIMIT,
+	)
+	require.NoError(err)
+	require.Equal("open", res.OrderState)
+	require.Equal(20.0, res.Amount)
+	require.Equal(0.08, res.Price)
+	require.True(res.Replaced)
+	require.Empty(ts.c.pending)
+}
+
+// nolint:lll
+func (ts *FixTestSuite) TestMassCancel() {
+	require := ts.Require()
+	sendResp := make(chan bool)
+
+	go func() {
+		msgStrings := []string{
+			"8=FIX.4.4\u00019=57\u000135=r\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=2\u0001530=1\u0001531=1\u0001533=3\u000110=194\u0001",
+			"8=FIX.4.4\u00019=75\u000135=r\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=3\u0001530=1\u0001531=0\u000158=instrument_not_found\u000110=229\u0001",
+		}
+		for _, msgStr := range msgStrings {
+			<-sendResp
+			time.Sleep(responseTime)
+			respMsg := getMsgFromString(msgStr)
+
+			mutex.Lock()
+			respMsg.Body.Set(field.NewClOrdID(requestID))
+			mutex.Unlock()
+
+			err := mockDeribitResponse(respMsg)
+			require.NoError(err)
+		}
+	}()
+
+	// success case
+	sendResp <- true
+	count, err := ts.c.MassCancel(
+		context.Background(),
+		enum.MassCancelRequestType_CANCEL_ORDERS_FOR_A_SECURITY,
+		"BTC-PERPETUAL",
+		"",
+		"",
+	)
+	require.NoError(err)
+	require.Equal(uint(3), count)
+
+	// reject case
+	sendResp <- true
+	count, err = ts.c.MassCancel(
+		context.Background(),
+		enum.MassCancelRequestType_CANCEL_ORDERS_FOR_A_SECURITY,
+		"BTC-XXX",
+		"",
+		"",
+	)
+	require.ErrorIs(err, ErrMassCancelRejected)
+	require.Zero(count)
+}
+
+// nolint:lll
+func (ts *FixTestSuite) TestHandleExecutionReport() {
+	require := ts.Require()
+	instrument := "BTC-19AUG22-21000-C"
+	// unsolicited ExecutionReport of a filled order
+	msg := getMsgFromString("8=FIX.4.4\u00019=494\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=9158\u000152=20220818-06:37:42.584\u0001527=14020845373\u000137=14020845373\u000111=14020845373\u000141=6b5c1fe2-e6ad-4ccf-93d7-8b6ccd51cdea\u0001150=I\u000139=2\u000154=1\u000160=20220818-06:37:42.583\u000112=0.00003000\u0001151=0.0000\u000114=0.1000\u000138=0.1000\u000140=2\u000144=0.077\u0001103=0\u000158=success\u0001207=DERIBITSERVER\u000155=BTC-19AUG22-21000-C\u0001854=1\u0001231=1.0000\u00016=0.077000\u0001210=0.1000\u0001100010=BTC-19AUG22-21000-C_buy_0.077_0.1_JNGhwLYqkJzoYSk\u000132=0.1000\u000131=0.0770\u00011362=1\u00011363=BTC-19AUG22-21000-C#102\u00011364=0.0770\u00011365=0.1000\u00011443=2\u000110=012\u0001")
+
+	orderCh := make(chan *models.Order, 2)
+	tradesCh := make(chan *models.UserTradesNotification, 2)
+	orderListener := func(order *models.Order) { orderCh <- order }
+	tradesListener := func(trades *models.UserTradesNotification) { tradesCh <- trades }
+	// TestCreateOrder may have seen the same fill
+	ts.c.seenFills = newIDSet(seenFillsSize)
+	ts.c.On("user.orders."+instrument+".raw", orderListener)
+	ts.c.On("user.trades."+instrument+".raw", tradesListener)
+	defer ts.c.Off("user.orders."+instrument+".raw", orderListener)
+	defer ts.c.Off("user.trades."+instrument+".raw", tradesListener)
+
+	require.NoError(mockDeribitResponse(msg))
+	require.Len(orderCh, 1)
+	order := <-orderCh
+	require.Equal("filled", order.OrderState)
+	require.Equal("14020845373", order.OrderID)
+
+	require.Len(tradesCh, 1)
+	require.Equal(&models.UserTradesNotification{
+		{
+			TradeID:        "BTC-19AUG22-21000-C#102",
+			TradeSeq:       102,
+			Timestamp:      1660804662583,
+			State:          "filled",
+			Price:          0.077,
+			OrderType:      "limit",
+			OrderID:        "14020845373",
+			Liquidity:      "T",
+			Label:          "BTC-19AUG22-21000-C_buy_0.077_0.1_JNGhwLYqkJzoYSk",
+			InstrumentName: instrument,
+			Direction:      "buy",
+			Amount:         0.1,
+		},
+	}, <-tradesCh)
+
+	// the same fill is not emitted again
+	require.NoError(mockDeribitResponse(msg))
+	require.Len(orderCh, 1)
+	require.Len(tradesCh, 0)
+	<-orderCh
+
+	// unsolicited rejection of an order
+	msg = getMsgFromString("8=FIX.4.4\u00019=216\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=9159\u000152=20220818-06:37:43.584\u000111=14020845374\u000141=14020845374\u0001150=8\u000139=8\u000154=2\u000160=20220818-06:37:43.583\u000138=0.1000\u000140=2\u000144=0.08\u000158=not_enough_funds\u000155=BTC-19AUG22-21000-C\u0001100010=test\u000110=235\u0001")
+	require.NoError(mockDeribitResponse(msg))
+	require.Len(orderCh, 1)
+	require.Equal(&models.Order{
+		OrderState:          "rejected",
+		API:                 true,
+		Amount:              0.1,
+		InstrumentName:      instrument,
+		Price:               0.08,
+		LastUpdateTimestamp: 1660804663583,
+		OrderID:             "14020845374",
+		Label:               "test",
+		CreationTimestamp:   1660804663583,
+		Direction:           "sell",
+		OrderType:           "limit",
+	}, <-orderCh)
+	require.Len(tradesCh, 0)
+}
+
+// nolint:lll
+const openOrderExecutionReport = "8=FIX.4.4\u00019=341\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=9158\u000152=20220818-06:37:42.584\u0001527=14020845373\u000137=14020845373\u000111=14020845373\u000141=6b5c1fe2-e6ad-4ccf-93d7-8b6ccd51cdea\u0001150=I\u000139=0\u000154=1\u000160=20220818-06:37:42.583\u000112=0\u0001151=0.1000\u000114=0\u000138=0.1000\u000140=2\u000144=0.077\u0001103=0\u000158=success\u0001207=DERIBITSERVER\u000155=BTC-19AUG22-21000-C\u0001854=1\u0001231=1.0000\u00016=0\u0001210=0.1000\u0001100010=test\u000110=200\u0001"
+
+func newTestMessage(msgType enum.MsgType) *quickfix.Message {
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewBeginString(fixVersion))
+	msg.Header.Set(field.NewMsgType(msgType))
+	return msg
+}
+
+func (ts *FixTestSuite) TestOrderStatus() {
+	require := ts.Require()
+	orderID := "14020845373"
+
+	go func() {
+		time.Sleep(responseTime)
+		respMsg := getMsgFromString(openOrderExecutionReport)
+		respMsg.Body.Set(field.NewOrigClOrdID(orderID))
+		require.NoError(mockDeribitResponse(respMsg))
+	}()
+
+	order, err := ts.c.OrderStatus(context.Background(), orderID)
+	require.NoError(err)
+	require.Equal(orderID, order.OrderID)
+	require.Equal("open", order.OrderState)
+	require.Empty(ts.c.pending)
+}
+
+func (ts *FixTestSuite) TestOrderMassStatus() {
+	require := ts.Require()
+	sendResp := make(chan bool)
+
+	go func() {
+		// two open orders
+		<-sendResp
+		time.Sleep(responseTime)
+		for i, orderID := range []string{"14020845301", "14020845302"} {
+			respMsg := getMsgFromString(strings.ReplaceAll(openOrderExecutionReport, "14020845373", orderID))
+			mutex.Lock()
+			respMsg.Body.Set(field.NewMassStatusReqID(requestID))
+			mutex.Unlock()
+			respMsg.Body.Set(field.NewLastRptRequested(i == 1))
+			require.NoError(mockDeribitResponse(respMsg))
+		}
+
+		// no open orders
+		<-sendResp
+		time.Sleep(responseTime)
+		respMsg := newTestMessage(enum.MsgType_EXECUTION_REPORT)
+		mutex.Lock()
+		respMsg.Body.Set(field.NewMassStatusReqID(requestID))
+		mutex.Unlock()
+		respMsg.Body.Set(field.NewLastRptRequested(true))
+		require.NoError(mockDeribitResponse(respMsg))
+	}()
+
+	sendResp <- true
+	orders, err := ts.c.OrderMassStatus(context.Background(), "")
+	require.NoError(err)
+	require.Len(orders, 2)
+	require.Equal("14020845301", orders[0].OrderID)
+	require.Equal("14020845302", orders[1].OrderID)
+
+	sendResp <- true
+	orders, err = ts.c.OrderMassStatus(context.Background(), "BTC-PERPETUAL")
+	require.NoError(err)
+	require.Empty(orders)
+	require.Empty(ts.c.pending)
+}
+
+func (ts *FixTestSuite) TestGetPositions() {
+	require := ts.Require()
+
+	go func() {
+		time.Sleep(responseTime)
+		respMsg := newTestMessage(enum.MsgType_POSITION_REPORT)
+		mutex.Lock()
+		respMsg.Body.Set(field.NewPosReqID(requestID))
+		mutex.Unlock()
+		respMsg.Body.Set(field.NewPosReqResult(enum.PosReqResult_VALID_REQUEST))
+
+		groups := newNoPositionsRepeatingGroup()
+		g := groups.Add()
+		g.Set(field.NewPosType(enum.PosType_TRANSACTION_QUANTITY))
+		g.SetString(tag.LongQty, "0")
+		g.SetString(tag.ShortQty, "100")
+		g.Set(field.NewSymbol("BTC-PERPETUAL"))
+		g.SetString(tag.SettlPrice, "21000.5")
+		g.SetString(tagMarkPrice, "21100")
+		respMsg.Body.SetGroup(groups)
+		require.NoError(mockDeribitResponse(respMsg))
+	}()
+
+	positions, err := ts.c.GetPositions(context.Background(), &models.GetPositionsParams{Currency: "BTC", Kind: "future"})
+	require.NoError(err)
+	require.Equal([]models.Position{
+		{
+			AveragePrice:   21000.5,
+			Direction:      "sell",
+			InstrumentName: "BTC-PERPETUAL",
+			MarkPrice:      21100,
+			Size:           -100,
+		},
+	}, positions)
+}
+
+func (ts *FixTestSuite) TestGetInstruments() {
+	require := ts.Require()
+
+	go func() {
+		time.Sleep(responseTime)
+		respMsg := newTestMessage(enum.MsgType_SECURITY_LIST)
+		mutex.Lock()
+		respMsg.Body.Set(field.NewSecurityReqID(requestID))
+		mutex.Unlock()
+
+		groups := newSecurityListNoRelatedSymRepeatingGroup()
+		g := groups.Add()
+		g.Set(field.NewSymbol("BTC-26AUG22-32000-P"))
+		g.SetString(tag.SecurityType, string(enum.SecurityType_OPTION))
+		g.SetString(tag.PutOrCall, string(enum.PutOrCall_PUT))
+		g.SetString(tag.StrikePrice, "32000")
+		g.SetString(tag.StrikeCurrency, "USD")
+		g.SetString(tag.Currency, "BTC")
+		g.SetString(tag.MinPriceIncrement, "0.0005")
+		g.SetString(tag.MinTradeVol, "0.1")
+		g.SetString(tag.ContractMultiplier, "1")
+		g.SetString(tag.IssueDate, "20220812-08:00:00.000")
+		g.SetString(tag.MaturityDate, "20220826")
+		g.SetString(tag.MaturityTime, "08:00:00")
+		altIDs := quickfix.NewRepeatingGroup(
+			tag.NoSecurityAltID,
+			quickfix.GroupTemplate{
+				quickfix.GroupElement(tag.SecurityAltID),
+				quickfix.GroupElement(tag.SecurityAltIDSource),
+			},
+		)
+		altID := altIDs.Add()
+		altID.SetString(tag.SecurityAltID, "210838")
+		altID.SetString(tag.SecurityAltIDSource, securityAltIDSourceMulticast)
+		g.SetGroup(altIDs)
+		respMsg.Body.SetGroup(groups)
+		require.NoError(mockDeribitResponse(respMsg))
+	}()
+
+	instruments, err := ts.c.GetInstruments(context.Background(), &models.GetInstrumentsParams{Currency: "BTC"})
+	require.NoError(err)
+	require.Equal([]models.Instrument{
+		{
+			TickSize:            0.0005,
+			QuoteCurrency:       "USD",
+			MinTradeAmount:      0.1,
+			Kind:                "option",
+			IsActive:            true,
+			InstrumentID:        210838,
+			InstrumentName:      "BTC-26AUG22-32000-P",
+			ExpirationTimestamp: 1661500800000,
+			CreationTimestamp:   1660291200000,
+			ContractSize:        1,
+			BaseCurrency:        "BTC",
+			OptionType:          "put",
+			Strike:              32000,
+		},
+	}, instruments)
+}
+
 func getMsgFromString(str string) *quickfix.Message {
 	msg := quickfix.NewMessage()
 	bufferData := bytes.NewBufferString(str)
@@ -661,6 +1149,291 @@ func getMsgFromString(str string) *quickfix.Message {
 	return msg
 }
 
+type offsetClock time.Duration
+
+func (c offsetClock) ToServerTime(t time.Time) time.Time {
+	return t.Add(time.Duration(c))
+}
+
+func (ts *FixTestSuite) TestLatency() {
+	require := ts.Require()
+
+	exchangeTime := time.UnixMilli(1660000000000)
+	receivedAt := exchangeTime.Add(100 * time.Millisecond)
+	c := &Client{}
+	require.Equal(100*time.Millisecond, c.Latency(exchangeTime, receivedAt))
+
+	// local clock is 30ms ahead of the server clock
+	c.clock = offsetClock(-30 * time.Millisecond)
+	require.Equal(70*time.Millisecond, c.Latency(exchangeTime, receivedAt))
+}
+
+func (ts *FixTestSuite) TestToAdmin() {
+	require := ts.Require()
+
+	logon := newTestMessage(enum.MsgType_LOGON)
+	ts.c.ToAdmin(logon, quickfix.SessionID{})
+	username, err := logon.Body.GetString(tag.Username)
+	require.NoError(err)
+	require.Equal(apiKey, username)
+	require.True(logon.Body.Has(tag.Password))
+	reset, err := logon.Body.GetBool(tag.ResetSeqNumFlag)
+	require.NoError(err)
+	require.True(reset)
+
+	// other session messages are sent unchanged
+	sequenceReset := newTestMessage(enum.MsgType_SEQUENCE_RESET)
+	ts.c.ToAdmin(sequenceReset, quickfix.SessionID{})
+	require.False(sequenceReset.Body.Has(tag.Password))
+	require.False(sequenceReset.Body.Has(tag.ResetSeqNumFlag))
+
+	// sequence numbers are kept on logon
+	c := &Client{apiKey: apiKey, secretKey: secretKey, persistSeqNums: true}
+	logon = newTestMessage(enum.MsgType_LOGON)
+	c.ToAdmin(logon, quickfix.SessionID{})
+	require.True(logon.Body.Has(tag.Password))
+	require.False(logon.Body.Has(tag.ResetSeqNumFlag))
+
+	// logon options
+	c = &Client{apiKey: apiKey, secretKey: secretKey, appID: "app_id", appSecret: "app_secret", cancelOnDisconnect: true}
+	logon = newTestMessage(enum.MsgType_LOGON)
+	c.ToAdmin(logon, quickfix.SessionID{})
+	cancelOnDisconnect, err := logon.Body.GetBool(tagCancelOnDisconnect)
+	require.NoError(err)
+	require.True(cancelOnDisconnect)
+	appID, err := logon.Body.GetString(tagDeribitAppID)
+	require.NoError(err)
+	require.Equal("app_id", appID)
+	rawData, err := logon.Body.GetString(tag.RawData)
+	require.NoError(err)
+	appSig, err := logon.Body.GetString(tagDeribitAppSig)
+	require.NoError(err)
+	require.Equal(signLogon(rawData, "app_secret"), appSig)
+}
+
+// nolint:funlen
+func (ts *FixTestSuite) TestStart() {
+	require := ts.Require()
+
+	logout := newTestMessage(enum.MsgType_LOGOUT)
+	logout.Body.SetString(tag.Text, "invalid_credentials")
+	logoutRaw := []byte(logout.String())
+	nullLog, err := quickfix.NewNullLogFactory().Create()
+	require.NoError(err)
+
+	newClient := func(onStart func(c *Client)) (*Client, *pendingInitiator) {
+		c := &Client{
+			log: ts.c.log, logonTimeout: 50 * time.Millisecond, emitter: emission.NewEmitter(), router: router.New(),
+		}
+		i := &pendingInitiator{onStart: func() { onStart(c) }}
+		c.initiator = i
+		return c, i
+	}
+
+	tests := []struct {
+		name    string
+		onStart func(c *Client)
+		check   func(err error)
+	}{
+		{
+			"accepted",
+			func(c *Client) { go c.OnLogon(quickfix.SessionID{}) },
+			func(err error) { require.NoError(err) },
+		},
+		{
+			"rejected in session",
+			func(c *Client) { go c.FromAdmin(logout, quickfix.SessionID{}) },
+			func(err error) {
+				var logonErr *LogonError
+				require.ErrorAs(err, &logonErr)
+				require.Equal("invalid_credentials", logonErr.Reason)
+			},
+		},
+		{
+			"rejected before logon",
+			func(c *Client) {
+				log := logonLog{Log: nullLog, c: c}
+				go log.OnIncoming(logoutRaw)
+			},
+			func(err error) {
+				var logonErr *LogonError
+				require.ErrorAs(err, &logonErr)
+				require.Equal("invalid_credentials", logonErr.Reason)
+			},
+		},
+		{
+			"timeout",
+			func(c *Client) {},
+			func(err error) { require.ErrorIs(err, ErrLogonTimeout) },
+		},
+	}
+
+	for _, test := range tests {
+		c, i := newClient(test.onStart)
+		err := c.Start(context.Background())
+		test.check(err)
+		require.Equal(err != nil, i.stopped, test.name)
+	}
+
+	// canceled context
+	c, i := newClient(func(c *Client) {})
+	c.logonTimeout = time.Minute
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(c.Start(ctx), context.Canceled)
+	require.True(i.stopped)
+}
+
+func (ts *FixTestSuite) TestToApp() {
+	require := ts.Require()
+
+	msg := newTestMessage(enum.MsgType_ORDER_SINGLE)
+	require.NoError(ts.c.ToApp(msg, quickfix.SessionID{}))
+
+	// resent messages are replaced by a gap fill
+	msg.Header.SetBool(tag.PossDupFlag, true)
+	require.ErrorIs(ts.c.ToApp(msg, quickfix.SessionID{}), quickfix.ErrDoNotSend)
+}
+
+func (ts *FixTestSuite) TestPersistSeqNums() {
+	require := ts.Require()
+
+	settingStr := "[DEFAULT]\nSocketConnectHost=test.deribit.com\nSocketConnectPort=9881\nHeartBtInt=30\n" +
+		"SenderCompID=FIX_TEST\nTargetCompID=DERIBITSERVER\nResetOnLogon=Y\n\n[SESSION]\nBeginString=FIX.4.4\n"
+	appSettings, err := quickfix.ParseSettings(bytes.NewBufferString(settingStr))
+	require.NoError(err)
+
+	storePath := ts.T().TempDir()
+	c, err := New(context.Background(), Config{
+		APIKey:         apiKey,
+		SecretKey:      secretKey,
+		Settings:       appSettings,
+		Dialer:         createMockInitiator,
+		Sender:         mockSender,
+		FileStorePath:  storePath,
+		PersistSeqNums: true,
+	})
+	require.NoError(err)
+	defer c.Close()
+
+	for sessionID, sessionSettings := range appSettings.SessionSettings() {
+		reset, err := sessionSettings.BoolSetting("ResetOnLogon")
+		require.NoError(err)
+		require.False(reset)
+
+		// sequence numbers are stored in files and restored by a new store
+		store, err := c.initiator.(*MockInitiator).storeFactory.Create(sessionID)
+		require.NoError(err)
+		require.NoError(store.SetNextSenderMsgSeqNum(42))
+		require.NoError(store.Close())
+
+		store, err = c.initiator.(*MockInitiator).storeFactory.Create(sessionID)
+		require.NoError(err)
+		require.Equal(42, store.NextSenderMsgSeqNum())
+		require.NoError(store.Close())
+	}
+
+	// a session cannot override the persistence
+	settingStr = "[DEFAULT]\nSocketConnectHost=test.deribit.com\nSocketConnectPort=9881\nHeartBtInt=30\n" +
+		"SenderCompID=FIX_TEST\nTargetCompID=DERIBITSERVER\n\n[SESSION]\nBeginString=FIX.4.4\nResetOnLogout=Y\n"
+	appSettings, err = quickfix.ParseSettings(bytes.NewBufferString(settingStr))
+	require.NoError(err)
+	_, err = New(context.Background(), Config{
+		APIKey:         apiKey,
+		SecretKey:      secretKey,
+		Settings:       appSettings,
+		Dialer:         createMockInitiator,
+		Sender:         mockSender,
+		PersistSeqNums: true,
+	})
+	require.ErrorIs(err, ErrSeqNumsReset)
+}
+
+// nolint:funlen
+func (ts *FixTestSuite) TestReconnect() {
+	require := ts.Require()
+
+	settingStr := "[DEFAULT]\nSocketConnectHost=test.deribit.com\nSocketConnectPort=9881\nHeartBtInt=30\n" +
+		"SenderCompID=FIX_TEST\nTargetCompID=DERIBITSERVER\n\n[SESSION]\nBeginString=FIX.4.4\n"
+	appSettings, err := quickfix.ParseSettings(bytes.NewBufferString(settingStr))
+	require.NoError(err)
+
+	// the server answers every market data request with an empty snapshot
+	var c *Client
+	var marketDataRequests int32
+	sender := func(m quickfix.Messagable) error {
+		msg := m.ToMessage()
+		if !msg.IsMsgTypeOf(string(enum.MsgType_MARKET_DATA_REQUEST)) {
+			return nil
+		}
+		atomic.AddInt32(&marketDataRequests, 1)
+		reqID, err := msg.Body.GetString(tag.MDReqID)
+		if err != nil {
+			return err
+		}
+		resp := newTestMessage(enum.MsgType_MARKET_DATA_SNAPSHOT_FULL_REFRESH)
+		resp.Body.SetString(tag.MDReqID, reqID)
+		go c.FromApp(resp, quickfix.SessionID{})
+		return nil
+	}
+
+	c, err = New(context.Background(), Config{
+		APIKey:           apiKey,
+		SecretKey:        secretKey,
+		Settings:         appSettings,
+		Dialer:           createMockInitiator,
+		Sender:           sender,
+		AutoReconnect:    true,
+		ReconnectBackoff: time.Millisecond,
+	})
+	require.NoError(err)
+	defer c.Close()
+
+	connected := make(chan struct{}, 1)
+	disconnected := make(chan struct{}, 1)
+	c.On(EventConnected, func() { connected <- struct{}{} })
+	c.On(EventDisconnected, func() { disconnected <- struct{}{} })
+
+	channels := []string{"book.BTC-PERPETUAL", "trades.BTC-PERPETUAL"}
+	require.NoError(c.Subscribe(context.Background(), channels))
+	require.EqualValues(2, atomic.LoadInt32(&marketDataRequests))
+
+	// the server logs the session out
+	for sessionID := range appSettings.SessionSettings() {
+		c.OnLogout(sessionID)
+	}
+	<-disconnected
+	<-connected
+
+	require.Eventually(func() bool {
+		return atomic.LoadInt32(&marketDataRequests) == 4
+	}, time.Second, time.Millisecond)
+	require.True(c.IsConnected())
+	c.mu.Lock()
+	require.ElementsMatch(channels, c.subscriptions)
+	c.mu.Unlock()
+}
+
+func (ts *FixTestSuite) TestExpirePendingCalls() {
+	require := ts.Require()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	active := &call{ctx: context.Background(), ids: []string{"active"}}
+	orphaned := &call{ctx: ctx, ids: []string{"orphaned", "alias"}}
+	c := &Client{log: ts.c.log, pending: map[string]*call{
+		"active":   active,
+		"orphaned": orphaned,
+		"alias":    orphaned,
+	}}
+
+	c.expirePendingCalls()
+	require.Len(c.pending, 3)
+
+	cancel()
+	c.expirePendingCalls()
+	require.Equal(map[string]*call{"active": active}, c.pending)
+}
+
 func (ts *FixTestSuite) TestXClose() {
 	require := ts.Require()
 
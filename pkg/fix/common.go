@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/KyberNetwork/deribit-api/pkg/models"
 	"github.com/quickfixgo/enum"
@@ -18,10 +20,26 @@ const (
 )
 
 var (
-	ErrClosed              = errors.New("connection is closed")
-	ErrInvalidRequestIDTag = errors.New("request id tag not found")
+	ErrClosed               = errors.New("connection is closed")
+	ErrMassCancelRejected   = errors.New("mass cancel request rejected")
+	ErrPositionsRejected    = errors.New("request for positions rejected")
+	ErrSeqNumsReset         = errors.New("sequence numbers reset is enabled")
+	ErrLogonTimeout         = errors.New("timed out waiting for logon")
+	ErrTradeCaptureRejected = errors.New("trade capture report request rejected")
+	ErrMassQuoteRejected    = errors.New("mass quote rejected")
+	ErrOrderCancelRejected  = errors.New("order cancel rejected")
+	ErrInvalidRequestIDTag  = errors.New("request id tag not found")
 )
 
+// LogonError is returned when the server rejects the logon, with the Text of its Logout.
+type LogonError struct {
+	Reason string
+}
+
+func (e *LogonError) Error() string {
+	return "logon rejected: " + e.Reason
+}
+
 func generateRandomBytes(n int) ([]byte, error) {
 	b := make([]byte, n)
 	_, err := rand.Read(b)
@@ -36,6 +54,173 @@ func newTradeNotificationChannel(instrument string) string {
 	return "trades." + instrument
 }
 
+func newTickerNotificationChannel(instrument string) string {
+	return "ticker." + instrument
+}
+
+// nolint:gochecknoglobals
+var tickerEntryTypes = []enum.MDEntryType{
+	enum.MDEntryType_INDEX_VALUE,
+	enum.MDEntryType_SETTLEMENT_PRICE,
+	enum.MDEntryType_TRADING_SESSION_HIGH_PRICE,
+	enum.MDEntryType_TRADING_SESSION_LOW_PRICE,
+	enum.MDEntryType_TRADE_VOLUME,
+	enum.MDEntryType_OPEN_INTEREST,
+}
+
+func isTickerEntryType(entryType enum.MDEntryType) bool {
+	for _, t := range tickerEntryTypes {
+		if t == entryType {
+			return true
+		}
+	}
+	return false
+}
+
+// tickerEntry is a statistics entry of a market data message.
+type tickerEntry struct {
+	entryType enum.MDEntryType
+	value     float64
+}
+
+func (e tickerEntry) apply(ticker *models.TickerNotification) {
+	switch e.entryType {
+	case enum.MDEntryType_INDEX_VALUE:
+		ticker.IndexPrice = e.value
+	case enum.MDEntryType_SETTLEMENT_PRICE:
+		ticker.SettlementPrice = e.value
+	case enum.MDEntryType_TRADING_SESSION_HIGH_PRICE:
+		ticker.Stats.High = e.value
+	case enum.MDEntryType_TRADING_SESSION_LOW_PRICE:
+		ticker.Stats.Low = e.value
+	case enum.MDEntryType_TRADE_VOLUME:
+		ticker.Stats.Volume = e.value
+	case enum.MDEntryType_OPEN_INTEREST:
+		ticker.OpenInterest = e.value
+	}
+}
+
+// newUserOrdersNotificationChannel returns the websocket channel name of order updates,
+// which are sent by the FIX server without aggregation.
+func newUserOrdersNotificationChannel(instrument string) string {
+	return "user.orders." + instrument + ".raw"
+}
+
+func newUserTradesNotificationChannel(instrument string) string {
+	return "user.trades." + instrument + ".raw"
+}
+
+// decodeFills decodes the NoFills group of an ExecutionReport into user trades of the order.
+// The group has neither the time nor the fee of a fill: a fill is stamped with the time of
+// the report and its Fee is left unset, the Commission of the report is cumulative for the
+// order. The fee of every trade is reported by the TradeCaptureReports of a drop copy session.
+func decodeFills(msg *quickfix.Message, order models.Order) (trades models.UserTradesNotification, err error) {
+	if !hasFills(msg) {
+		return nil, nil
+	}
+
+	fills, err := getFills(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < fills.Len(); i++ {
+		fill := fills.Get(i)
+		tradeID, err := getFillExecID(fill)
+		if err != nil {
+			return nil, err
+		}
+
+		price, err := getFillPx(fill)
+		if err != nil {
+			return nil, err
+		}
+
+		amount, err := getFillQty(fill)
+		if err != nil {
+			return nil, err
+		}
+
+		liquidity, err := getFillLiquidityInd(fill)
+		if err != nil {
+			return nil, err
+		}
+
+		trades = append(trades, models.UserTrade{
+			TradeID:        tradeID,
+			TradeSeq:       fillTradeSeq(tradeID),
+			Timestamp:      order.LastUpdateTimestamp,
+			State:          order.OrderState,
+			ReduceOnly:     order.ReduceOnly,
+			Price:          price,
+			PostOnly:       order.PostOnly,
+			OrderType:      order.OrderType,
+			OrderID:        order.OrderID,
+			Liquidity:      decodeLiquidity(liquidity),
+			Label:          order.Label,
+			InstrumentName: order.InstrumentName,
+			Direction:      order.Direction,
+			Amount:         amount,
+		})
+	}
+
+	return trades, nil
+}
+
+// fillTradeSeq returns the trade sequence of a FillExecID, which joins the instrument and
+// the sequence with '#', or 0 for another format.
+func fillTradeSeq(execID string) uint64 {
+	i := strings.LastIndexByte(execID, '#')
+	if i < 0 {
+		return 0
+	}
+	seq, err := strconv.ParseUint(execID[i+1:], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+func decodeLiquidity(liquidityInd int) string {
+	switch liquidityInd {
+	case liquidityIndAdded:
+		return "M"
+	case liquidityIndRemoved:
+		return "T"
+	default:
+		return ""
+	}
+}
+
+// idSet remembers the most recent IDs up to a fixed size.
+type idSet struct {
+	size  int
+	ids   map[string]struct{}
+	order []string
+}
+
+func newIDSet(size int) *idSet {
+	return &idSet{
+		size: size,
+		ids:  make(map[string]struct{}, size),
+	}
+}
+
+// Add adds id to the set and reports whether it was not already there.
+func (s *idSet) Add(id string) bool {
+	if _, ok := s.ids[id]; ok {
+		return false
+	}
+
+	if len(s.order) >= s.size {
+		delete(s.ids, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.ids[id] = struct{}{}
+	s.order = append(s.order, id)
+	return true
+}
+
 // nolint:funlen,cyclop
 func decodeExecutionReport(msg *quickfix.Message) (order models.Order, err error) {
 	status, err := getOrderStatus(msg)
@@ -145,6 +330,495 @@ func decodeExecutionReport(msg *quickfix.Message) (order models.Order, err error
 	return order, nil
 }
 
+// decodeRejectedOrder decodes the order of an ExecutionReport rejecting it. A rejection may lack
+// the fields of an accepted order, only the instrument is required.
+func decodeRejectedOrder(msg *quickfix.Message) (order models.Order, err error) {
+	if order.InstrumentName, err = getSymbol(msg); err != nil {
+		return order, err
+	}
+
+	order.OrderState = decodeOrderStatus(enum.OrdStatus_REJECTED)
+	order.API = true
+	if order.OrderID, _ = getOrderID(msg); order.OrderID == "" {
+		order.OrderID, _ = msg.Body.GetString(tag.ClOrdID)
+	}
+	if side, err := getSide(msg); err == nil {
+		order.Direction = decodeOrderSide(side)
+	}
+	if orderType, err := getOrdType(msg); err == nil {
+		order.OrderType = decodeOrderType(orderType)
+	}
+	order.Amount, _ = getOrderQty(msg)
+	order.Price, _ = getPrice(msg)
+	order.Label, _ = getDeribitLabel(msg)
+	if transactTime, err := getTransactTime(msg); err == nil {
+		order.LastUpdateTimestamp = uint64(transactTime.UnixMilli())
+		order.CreationTimestamp = order.LastUpdateTimestamp
+	}
+
+	return order, nil
+}
+
+// decodeTradeCaptureReport decodes the trade of a TradeCaptureReport message, as seen from
+// the first side of the report. TradeReportID is the sequence of the report.
+// nolint:cyclop
+func decodeTradeCaptureReport(msg *quickfix.Message) (trade models.UserTrade, err error) {
+	if trade.TradeID, err = msg.Body.GetString(tag.ExecID); err != nil {
+		return trade, err
+	}
+	if trade.InstrumentName, err = getSymbol(msg); err != nil {
+		return trade, err
+	}
+	if trade.Price, err = getLastPx(msg); err != nil {
+		return trade, err
+	}
+	if trade.Amount, err = getLastQty(msg); err != nil {
+		return trade, err
+	}
+	transactTime, err := getTransactTime(msg)
+	if err != nil {
+		return trade, err
+	}
+	trade.Timestamp = uint64(transactTime.UnixMilli())
+
+	reportID, err := msg.Body.GetString(tag.TradeReportID)
+	if err != nil {
+		return trade, err
+	}
+	if trade.TradeSeq, err = strconv.ParseUint(reportID, 10, 64); err != nil {
+		return trade, err
+	}
+
+	sides, err := getTradeCaptureSides(msg)
+	if err != nil {
+		return trade, err
+	}
+	if sides.Len() == 0 {
+		return trade, errors.New("no sides in trade capture report")
+	}
+	side := sides.Get(0)
+
+	var s string
+	if s, err = side.GetString(tag.Side); err != nil {
+		return trade, err
+	}
+	trade.Direction = decodeOrderSide(enum.Side(s))
+	if trade.OrderID, err = side.GetString(tag.OrderID); err != nil {
+		return trade, err
+	}
+	if side.Has(tag.Commission) {
+		if trade.Fee, err = getGroupFloat(side, tag.Commission); err != nil {
+			return trade, err
+		}
+	}
+	if side.Has(tag.AggressorIndicator) {
+		aggressor, err := side.GetBool(tag.AggressorIndicator)
+		if err != nil {
+			return trade, err
+		}
+		trade.Liquidity = "M"
+		if aggressor {
+			trade.Liquidity = "T"
+		}
+	}
+	if side.Has(tagDeribitLabel) {
+		if trade.Label, err = side.GetString(tagDeribitLabel); err != nil {
+			return trade, err
+		}
+	}
+
+	return trade, nil
+}
+
+// decodeTradeCaptureReports decodes the responses to a TradeCaptureReportRequest: an acknowledgement
+// followed by the reports, keeping the trades from TradeSeq startSeq and after the TradeSeq of their
+// instrument in lastSeqs.
+func decodeTradeCaptureReports(
+	msgs []*quickfix.Message, startSeq uint64, lastSeqs map[string]uint64,
+) ([]models.UserTrade, error) {
+	trades := make([]models.UserTrade, 0, len(msgs))
+	for _, msg := range msgs {
+		if msg.IsMsgTypeOf(string(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST_ACK)) {
+			status, err := getTradeRequestStatus(msg)
+			if err != nil {
+				return nil, err
+			}
+			if status == enum.TradeRequestStatus_ACCEPTED || status == enum.TradeRequestStatus_COMPLETED {
+				continue
+			}
+			if reason, err := getText(msg); err == nil {
+				return nil, fmt.Errorf("%w: %s", ErrTradeCaptureRejected, reason)
+			}
+			return nil, ErrTradeCaptureRejected
+		}
+
+		trade, err := decodeTradeCaptureReport(msg)
+		if err != nil {
+			return nil, err
+		}
+		if last, ok := lastSeqs[trade.InstrumentName]; ok && trade.TradeSeq <= last {
+			continue
+		}
+		if trade.TradeSeq >= startSeq {
+			trades = append(trades, trade)
+		}
+	}
+	return trades, nil
+}
+
+// decodeMassQuoteAck decodes a MassQuoteAcknowledgement, returning ErrMassQuoteRejected
+// if the whole request is rejected. Rejected entries are reported in the acknowledgement.
+// nolint:cyclop
+func decodeMassQuoteAck(msg *quickfix.Message) (ack MassQuoteAck, err error) {
+	if ack.QuoteID, err = msg.Body.GetString(tag.QuoteID); err != nil {
+		return ack, err
+	}
+	if ack.Status, err = getQuoteStatus(msg); err != nil {
+		return ack, err
+	}
+	if ack.Status == enum.QuoteStatus_REJECTED {
+		reason, _ := getText(msg)
+		if reason == "" {
+			code, _ := msg.Body.GetString(tag.QuoteRejectReason)
+			reason = decodeQuoteRejectReason(code)
+		}
+		return ack, fmt.Errorf("%w: %s", ErrMassQuoteRejected, reason)
+	}
+
+	sets, err := getMassQuoteAckQuoteSets(msg)
+	if err != nil {
+		return ack, err
+	}
+	for i := 0; i < sets.Len(); i++ {
+		set := sets.Get(i)
+		var setID string
+		if setID, err = getGroupString(set, tag.QuoteSetID); err != nil {
+			return ack, err
+		}
+		entries, err := getMassQuoteAckQuoteEntries(set)
+		if err != nil {
+			return ack, err
+		}
+
+		for j := 0; j < entries.Len(); j++ {
+			entry := entries.Get(j)
+			entryAck := QuoteEntryAck{QuoteSetID: setID}
+			if entryAck.QuoteEntryID, err = getGroupString(entry, tag.QuoteEntryID); err != nil {
+				return ack, err
+			}
+			if entryAck.Instrument, err = getGroupString(entry, tag.Symbol); err != nil {
+				return ack, err
+			}
+			status, err := getGroupString(entry, tag.QuoteEntryStatus)
+			if err != nil {
+				return ack, err
+			}
+			entryAck.Status = enum.QuoteEntryStatus(status)
+			code, err := getGroupString(entry, tag.QuoteEntryRejectReason)
+			if err != nil {
+				return ack, err
+			}
+			if code != "" {
+				entryAck.RejectReason = decodeQuoteRejectReason(code)
+			}
+			ack.Entries = append(ack.Entries, entryAck)
+		}
+	}
+
+	return ack, nil
+}
+
+// decodeOrderCancelReject returns ErrOrderCancelRejected with the Text and the CxlRejReason
+// of an OrderCancelReject message when they are present.
+func decodeOrderCancelReject(msg *quickfix.Message) error {
+	err := ErrOrderCancelRejected
+	if reason, err2 := getText(msg); err2 == nil {
+		err = fmt.Errorf("%w: %s", err, reason)
+	}
+	if code, err2 := getCxlRejReason(msg); err2 == nil {
+		err = fmt.Errorf("%w (reason %s)", err, code)
+	}
+	return err
+}
+
+// decodeOrderMassCancelReport returns the number of orders cancelled by a mass cancel request.
+func decodeOrderMassCancelReport(msg *quickfix.Message) (uint, error) {
+	response, err := getMassCancelResponse(msg)
+	if err != nil {
+		return 0, err
+	}
+
+	if response == enum.MassCancelResponse_CANCEL_REQUEST_REJECTED {
+		if reason, err := getText(msg); err == nil {
+			return 0, fmt.Errorf("%w: %s", ErrMassCancelRejected, reason)
+		}
+		return 0, ErrMassCancelRejected
+	}
+
+	return getTotalAffectedOrders(msg)
+}
+
+// decodePositionReport decodes the positions of a PositionReport message.
+// nolint:cyclop
+func decodePositionReport(msg *quickfix.Message) ([]models.Position, error) {
+	result, err := getPosReqResult(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result {
+	case enum.PosReqResult_VALID_REQUEST:
+	case enum.PosReqResult_NO_POSITIONS_FOUND_THAT_MATCH_CRITERIA:
+		return []models.Position{}, nil
+	default:
+		if reason, err := getText(msg); err == nil {
+			return nil, fmt.Errorf("%w: %s", ErrPositionsRejected, reason)
+		}
+		return nil, ErrPositionsRejected
+	}
+
+	groups, err := getPositions(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]models.Position, 0, groups.Len())
+	for i := 0; i < groups.Len(); i++ {
+		g := groups.Get(i)
+		symbol, err := getGroupString(g, tag.Symbol)
+		if err != nil {
+			return nil, err
+		}
+
+		longQty, err := getGroupFloat(g, tag.LongQty)
+		if err != nil {
+			return nil, err
+		}
+
+		shortQty, err := getGroupFloat(g, tag.ShortQty)
+		if err != nil {
+			return nil, err
+		}
+
+		avgPrice, err := getGroupFloat(g, tag.SettlPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		settlementPrice, err := getGroupFloat(g, tag.PriorSettlPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		markPrice, err := getGroupFloat(g, tagMarkPrice)
+		if err != nil {
+			return nil, err
+		}
+
+		size := longQty - shortQty
+		direction := "zero"
+		switch {
+		case size > 0:
+			direction = "buy"
+		case size < 0:
+			direction = "sell"
+		}
+
+		positions = append(positions, models.Position{
+			AveragePrice:    avgPrice,
+			Direction:       direction,
+			InstrumentName:  symbol,
+			MarkPrice:       markPrice,
+			SettlementPrice: settlementPrice,
+			Size:            size,
+		})
+	}
+
+	return positions, nil
+}
+
+// decodeSecurityList decodes the instruments of a SecurityList message.
+// nolint:funlen,cyclop
+func decodeSecurityList(msg *quickfix.Message) ([]models.Instrument, error) {
+	groups, err := getSecurityListRelatedSym(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	instruments := make([]models.Instrument, 0, groups.Len())
+	for i := 0; i < groups.Len(); i++ {
+		g := groups.Get(i)
+		ins := models.Instrument{IsActive: true}
+
+		if ins.InstrumentName, err = getGroupString(g, tag.Symbol); err != nil {
+			return nil, err
+		}
+
+		securityType, err := getGroupString(g, tag.SecurityType)
+		if err != nil {
+			return nil, err
+		}
+		ins.Kind = decodeSecurityType(enum.SecurityType(securityType))
+
+		putOrCall, err := getGroupString(g, tag.PutOrCall)
+		if err != nil {
+			return nil, err
+		}
+		ins.OptionType = decodePutOrCall(enum.PutOrCall(putOrCall))
+
+		for t, v := range map[quickfix.Tag]*float64{
+			tag.StrikePrice:        &ins.Strike,
+			tag.MinPriceIncrement:  &ins.TickSize,
+			tag.MinTradeVol:        &ins.MinTradeAmount,
+			tag.ContractMultiplier: &ins.ContractSize,
+		} {
+			if *v, err = getGroupFloat(g, t); err != nil {
+				return nil, err
+			}
+		}
+
+		if ins.BaseCurrency, err = getGroupString(g, tag.Currency); err != nil {
+			return nil, err
+		}
+		if ins.QuoteCurrency, err = getGroupString(g, tag.StrikeCurrency); err != nil {
+			return nil, err
+		}
+
+		issueDate, err := getGroupString(g, tag.IssueDate)
+		if err != nil {
+			return nil, err
+		}
+		if issueDate != "" {
+			t, err := parseFIXTime(issueDate)
+			if err != nil {
+				return nil, err
+			}
+			ins.CreationTimestamp = uint64(t.UnixMilli())
+		}
+
+		maturityDate, err := getGroupString(g, tag.MaturityDate)
+		if err != nil {
+			return nil, err
+		}
+		if maturityDate != "" {
+			maturityTime, err := getGroupString(g, tag.MaturityTime)
+			if err != nil {
+				return nil, err
+			}
+			if maturityTime != "" {
+				maturityDate += "-" + maturityTime
+			}
+			t, err := parseFIXTime(maturityDate)
+			if err != nil {
+				return nil, err
+			}
+			ins.ExpirationTimestamp = uint64(t.UnixMilli())
+		}
+
+		altIDs, err := getSecurityAltIDs(g)
+		if err != nil {
+			return nil, err
+		}
+		for j := 0; j < altIDs.Len(); j++ {
+			altID := altIDs.Get(j)
+			source, err := getGroupString(altID, tag.SecurityAltIDSource)
+			if err != nil {
+				return nil, err
+			}
+			if source != securityAltIDSourceMulticast {
+				continue
+			}
+
+			v, err := altID.GetInt(tag.SecurityAltID)
+			if err != nil {
+				return nil, err
+			}
+			ins.InstrumentID = uint32(v)
+		}
+
+		instruments = append(instruments, ins)
+	}
+
+	return instruments, nil
+}
+
+// parseFIXTime parses UTCTimestamp and LocalMktDate values.
+func parseFIXTime(s string) (t time.Time, err error) {
+	for _, layout := range []string{
+		"20060102-15:04:05.000",
+		"20060102-15:04:05",
+		"20060102",
+	} {
+		if t, err = time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return t, err
+}
+
+func encodeSecurityType(kind string) enum.SecurityType {
+	switch kind {
+	case "future":
+		return enum.SecurityType_FUTURE
+	case "option":
+		return enum.SecurityType_OPTION
+	case "spot":
+		return enum.SecurityType_FX_SPOT
+	default:
+		return enum.SecurityType(kind)
+	}
+}
+
+func decodeSecurityType(securityType enum.SecurityType) string {
+	switch securityType {
+	case enum.SecurityType_FUTURE:
+		return "future"
+	case enum.SecurityType_OPTION:
+		return "option"
+	case enum.SecurityType_FX_SPOT:
+		return "spot"
+	default:
+		return ""
+	}
+}
+
+func decodePutOrCall(putOrCall enum.PutOrCall) string {
+	switch putOrCall {
+	case enum.PutOrCall_PUT:
+		return "put"
+	case enum.PutOrCall_CALL:
+		return "call"
+	default:
+		return ""
+	}
+}
+
+// decodeQuoteRejectReason decodes a QuoteRejectReason or QuoteEntryRejectReason, which share their values.
+func decodeQuoteRejectReason(code string) string {
+	switch enum.QuoteEntryRejectReason(code) {
+	case enum.QuoteEntryRejectReason_UNKNOWN_SYMBOL:
+		return "unknown_symbol"
+	case enum.QuoteEntryRejectReason_EXHCNAGE:
+		return "exchange_closed"
+	case enum.QuoteEntryRejectReason_QUOTE_EXCEEDS_LIMIT:
+		return "quote_exceeds_limit"
+	case enum.QuoteEntryRejectReason_TOO_LATE_TO_ENTER:
+		return "too_late_to_enter"
+	case enum.QuoteEntryRejectReason_UNKNOWN_QUOTE:
+		return "unknown_quote"
+	case enum.QuoteEntryRejectReason_DUPLICATE_QUOTE:
+		return "duplicate_quote"
+	case enum.QuoteEntryRejectReason_INVALID_BID_ASK_SPREAD:
+		return "invalid_bid_ask_spread"
+	case enum.QuoteEntryRejectReason_INVALID_PRICE:
+		return "invalid_price"
+	case enum.QuoteEntryRejectReason_NOT_AUTHORIZED_TO_QUOTE_SECURITY:
+		return "not_authorized_to_quote_security"
+	default:
+		return "other"
+	}
+}
+
 func decodeOrderStatus(status enum.OrdStatus) string {
 	switch status {
 	case enum.OrdStatus_NEW, enum.OrdStatus_PARTIALLY_FILLED:
@@ -232,13 +906,17 @@ func getReqIDTagFromMsgType(msgType enum.MsgType) (quickfix.Tag, error) {
 	case enum.MsgType_ORDER_CANCEL_REJECT:
 		return tag.ClOrdID, nil
 	case enum.MsgType_ORDER_MASS_CANCEL_REPORT:
-		return tag.OrderID, nil
+		return tag.ClOrdID, nil
 	case enum.MsgType_POSITION_REPORT:
 		return tag.PosReqID, nil
 	case enum.MsgType_USER_RESPONSE:
 		return tag.UserRequestID, nil
 	case enum.MsgType_SECURITY_STATUS:
 		return tag.SecurityStatusReqID, nil
+	case enum.MsgType_TRADE_CAPTURE_REPORT, enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST_ACK:
+		return tag.TradeRequestID, nil
+	case enum.MsgType_MASS_QUOTE_ACKNOWLEDGEMENT:
+		return tag.QuoteID, nil
 	default:
 		return 0, ErrInvalidRequestIDTag
 	}
@@ -2,10 +2,12 @@ package fix
 
 import (
 	"bytes"
+	"strconv"
 	"testing"
 
 	"github.com/KyberNetwork/deribit-api/pkg/models"
 	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
 	"github.com/quickfixgo/quickfix"
 	"github.com/quickfixgo/tag"
 	"github.com/stretchr/testify/assert"
@@ -290,7 +292,98 @@ func TestDecodeExecutionReport(t *testing.T) {
 	}
 }
 
+// nolint:lll
+func TestDecodeFills(t *testing.T) {
+	msg := getMsgFromString("8=FIX.4.4\u00019=389\u000135=8\u000149=DERIBITSERVER\u000156=FIX_TEST\u000134=7534\u000152=20220912-11:12:19.623\u0001527=14230452591\u000137=14230452591\u000111=14230452591\u000141=14230452591\u0001150=F\u000139=2\u000154=1\u000160=20220912-11:12:19.623\u000112=0.003\u0001151=0.0\u000114=10.0\u000138=10.0\u000140=2\u000144=0.0865\u00016=0.0865\u000155=BTC-30JUN23-14000-P\u0001210=10.0\u0001100010=test\u00011362=2\u00011363=BTC-30JUN23-14000-P#83\u00011364=0.0865\u00011365=4.0\u00011443=1\u00011363=BTC-30JUN23-14000-P#84\u00011364=0.0865\u00011365=6.0\u00011443=2\u000110=095\u0001")
+	order, err := decodeExecutionReport(msg)
+	require.NoError(t, err)
+
+	trades, err := decodeFills(msg, order)
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+	assert.Equal(t, uint64(83), trades[0].TradeSeq)
+	assert.Equal(t, "M", trades[0].Liquidity)
+	assert.Equal(t, uint64(84), trades[1].TradeSeq)
+	assert.Equal(t, 6.0, trades[1].Amount)
+	for _, trade := range trades {
+		assert.Equal(t, uint64(1662981139623), trade.Timestamp)
+		// the commission of the order is cumulative, it is not the fee of a fill
+		assert.Zero(t, trade.Fee)
+	}
+
+	assert.Zero(t, fillTradeSeq("BTC-1234"))
+}
+
 // nolint:funlen
+func TestDecodeTradeCaptureReport(t *testing.T) {
+	tests := []struct {
+		msg            string
+		expectedOutput models.UserTrade
+		isError        bool
+	}{
+		{
+			"8=FIX.4.4\u00019=260\u000135=AE\u000149=DERIBITSERVER\u000156=DROP_COPY_TEST\u000134=12\u0001" +
+				"52=20220912-11:12:19.700\u0001571=83\u0001568=0a5f8a3b\u0001912=Y\u000117=BTC-30JUN23-14000-P#83\u0001" +
+				"55=BTC-30JUN23-14000-P\u000132=10.0\u000131=0.0865\u000160=20220912-11:12:19.623\u0001" +
+				"552=1\u000154=1\u000137=14230452591\u000111=14230452591\u000112=0.003\u00011057=Y\u0001" +
+				"100010=hedge\u000110=000\u0001",
+			models.UserTrade{
+				TradeSeq:       83,
+				TradeID:        "BTC-30JUN23-14000-P#83",
+				Timestamp:      1662981139623,
+				InstrumentName: "BTC-30JUN23-14000-P",
+				OrderID:        "14230452591",
+				Direction:      "buy",
+				Price:          0.0865,
+				Amount:         10,
+				Fee:            0.003,
+				Liquidity:      "T",
+				Label:          "hedge",
+			},
+			false,
+		},
+		{
+			"8=FIX.4.4\u00019=181\u000135=AE\u000149=DERIBITSERVER\u000156=DROP_COPY_TEST\u000134=13\u0001" +
+				"52=20220912-11:12:19.700\u0001571=84\u000117=BTC-PERPETUAL#84\u000155=BTC-PERPETUAL\u0001" +
+				"32=20\u000131=20010.5\u000160=20220912-11:12:19.623\u0001552=1\u000154=2\u000137=7\u00011057=N\u0001" +
+				"10=000\u0001",
+			models.UserTrade{
+				TradeSeq:       84,
+				TradeID:        "BTC-PERPETUAL#84",
+				Timestamp:      1662981139623,
+				InstrumentName: "BTC-PERPETUAL",
+				OrderID:        "7",
+				Direction:      "sell",
+				Price:          20010.5,
+				Amount:         20,
+				Liquidity:      "M",
+			},
+			false,
+		},
+		{
+			"8=FIX.4.4\u00019=158\u000135=AE\u000149=DERIBITSERVER\u000156=DROP_COPY_TEST\u000134=14\u0001" +
+				"52=20220912-11:12:19.700\u0001571=85\u000117=BTC-PERPETUAL#85\u000155=BTC-PERPETUAL\u0001" +
+				"32=20\u000131=20010.5\u000160=20220912-11:12:19.623\u000110=000\u0001",
+			models.UserTrade{},
+			true, // no sides
+		},
+	}
+
+	for _, test := range tests {
+		msg := quickfix.NewMessage()
+		err := quickfix.ParseMessage(msg, bytes.NewBufferString(test.msg))
+		require.NoError(t, err)
+
+		trade, err := decodeTradeCaptureReport(msg)
+		if test.isError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, test.expectedOutput, trade)
+	}
+}
+
 func TestGetReqIDTagFromMsgType(t *testing.T) {
 	tests := []struct {
 		msgType        enum.MsgType
@@ -340,13 +433,25 @@ func TestGetReqIDTagFromMsgType(t *testing.T) {
 
 		{
 			enum.MsgType_ORDER_MASS_CANCEL_REPORT,
-			tag.OrderID, nil,
+			tag.ClOrdID, nil,
 		},
 
 		{
 			enum.MsgType_SECURITY_LIST,
 			tag.SecurityReqID, nil,
 		},
+		{
+			enum.MsgType_TRADE_CAPTURE_REPORT,
+			tag.TradeRequestID, nil,
+		},
+		{
+			enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST_ACK,
+			tag.TradeRequestID, nil,
+		},
+		{
+			enum.MsgType_MASS_QUOTE_ACKNOWLEDGEMENT,
+			tag.QuoteID, nil,
+		},
 		{
 			enum.MsgType_HEARTBEAT,
 			0, ErrInvalidRequestIDTag,
@@ -363,3 +468,39 @@ func TestGetReqIDTagFromMsgType(t *testing.T) {
 		assert.ErrorIs(t, test.expectedError, err)
 	}
 }
+
+func TestDecodeTradeCaptureReportsFromLastSeqs(t *testing.T) {
+	var msgs []*quickfix.Message
+	for _, trade := range []struct {
+		symbol string
+		seq    int
+	}{{"BTC-PERPETUAL", 10}, {"ETH-PERPETUAL", 3}, {"ETH-PERPETUAL", 4}, {"BTC-PERPETUAL", 11}, {"SOL_USDC", 1}} {
+		msgs = append(msgs, newTradeCaptureReport("", testTrade{seq: trade.seq, order: testOrder{symbol: trade.symbol}}))
+	}
+
+	// every instrument resumes from its own sequence
+	trades, err := decodeTradeCaptureReports(msgs, 0, map[string]uint64{"BTC-PERPETUAL": 10, "ETH-PERPETUAL": 3})
+	require.NoError(t, err)
+	var seqs []string
+	for _, trade := range trades {
+		seqs = append(seqs, trade.InstrumentName+"#"+strconv.FormatUint(trade.TradeSeq, 10))
+	}
+	assert.Equal(t, []string{"ETH-PERPETUAL#4", "BTC-PERPETUAL#11", "SOL_USDC#1"}, seqs)
+}
+
+func TestDecodeOrderCancelReject(t *testing.T) {
+	msg := newTestMessage(enum.MsgType_ORDER_CANCEL_REJECT)
+	err := decodeOrderCancelReject(msg)
+	require.Equal(t, ErrOrderCancelRejected, err)
+
+	// the reason code is kept without Text
+	msg.Body.Set(field.NewCxlRejReason(enum.CxlRejReason_UNKNOWN_ORDER))
+	err = decodeOrderCancelReject(msg)
+	require.ErrorIs(t, err, ErrOrderCancelRejected)
+	require.EqualError(t, err, "order cancel rejected (reason 1)")
+
+	msg.Body.Set(field.NewText("order_not_found"))
+	err = decodeOrderCancelReject(msg)
+	require.ErrorIs(t, err, ErrOrderCancelRejected)
+	require.EqualError(t, err, "order cancel rejected: order_not_found (reason 1)")
+}
@@ -0,0 +1,225 @@
+package fix
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/chuckpreslar/emission"
+	"github.com/google/uuid"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+)
+
+// TradeCaptureReportParams selects the trades replayed by a TradeCaptureReportRequest.
+type TradeCaptureReportParams struct {
+	// Instrument limits the trades to an instrument if it is not empty.
+	Instrument string
+	// StartTime and EndTime bound the TransactTime of the trades if they are not zero.
+	StartTime time.Time
+	EndTime   time.Time
+	// StartSeq skips the trades with a lower TradeSeq. The server has no such filter,
+	// so it is applied to the trades returned by the request.
+	StartSeq uint64
+}
+
+// DropCopyClient is a read-only FIX session receiving the TradeCaptureReports of the account,
+// e.g. for compliance and back office. It logs on like Client and emits the trades of the
+// subscription as *models.UserTradesNotification on "user.trades.<instrument>.raw".
+type DropCopyClient struct {
+	c *Client
+
+	mu           sync.Mutex
+	subscription *TradeCaptureReportParams
+	subReqID     string
+}
+
+// NewDropCopyClient logs on a drop copy session. Its settings must use the SenderCompID of a
+// drop copy session, cfg.AutoReconnect restores the subscription from the last trade received of
+// every instrument.
+func NewDropCopyClient(ctx context.Context, cfg Config) (*DropCopyClient, error) {
+	c, err := New(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DropCopyClient{c: c}
+	c.On(EventConnected, func() {
+		// OnLogon must not wait for the response of the request.
+		go d.resubscribe()
+	})
+	return d, nil
+}
+
+// On adds a listener to a specific event.
+func (d *DropCopyClient) On(event interface{}, listener interface{}) *emission.Emitter {
+	return d.c.On(event, listener)
+}
+
+// Off removes a listener for an event.
+func (d *DropCopyClient) Off(event interface{}, listener interface{}) *emission.Emitter {
+	return d.c.Off(event, listener)
+}
+
+// IsConnected checks whether the connection is established or not.
+func (d *DropCopyClient) IsConnected() bool {
+	return d.c.IsConnected()
+}
+
+// Close closes underlying connection.
+func (d *DropCopyClient) Close() {
+	d.c.Close()
+}
+
+// GetTradeCaptureReports requests the past trades selected by params.
+func (d *DropCopyClient) GetTradeCaptureReports(
+	ctx context.Context, params TradeCaptureReportParams,
+) ([]models.UserTrade, error) {
+	_, trades, err := d.requestTradeCaptureReports(ctx, enum.SubscriptionRequestType_SNAPSHOT, params, nil)
+	return trades, err
+}
+
+// SubscribeTradeCaptureReports requests the past trades selected by params, which are returned,
+// then emits the new trades until UnsubscribeTradeCaptureReports is called.
+func (d *DropCopyClient) SubscribeTradeCaptureReports(
+	ctx context.Context, params TradeCaptureReportParams,
+) ([]models.UserTrade, error) {
+	reqID, trades, err := d.requestTradeCaptureReports(
+		ctx, enum.SubscriptionRequestType_SNAPSHOT_PLUS_UPDATES, params, nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	d.subscription = &params
+	d.subReqID = reqID
+	d.mu.Unlock()
+
+	// the trades are returned, not emitted, but must not be emitted again after a reconnect
+	d.c.addSeenTrades(trades)
+	return trades, nil
+}
+
+// UnsubscribeTradeCaptureReports stops the subscription to new trades.
+func (d *DropCopyClient) UnsubscribeTradeCaptureReports(ctx context.Context) error {
+	d.mu.Lock()
+	reqID := d.subReqID
+	d.subscription = nil
+	d.subReqID = ""
+	d.mu.Unlock()
+
+	if reqID == "" {
+		return nil
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST))
+	msg.Body.Set(field.NewTradeRequestID(reqID))
+	msg.Body.Set(field.NewTradeRequestType(enum.TradeRequestType_ALL_TRADES))
+	msg.Body.Set(field.NewSubscriptionRequestType(
+		enum.SubscriptionRequestType_DISABLE_PREVIOUS_SNAPSHOT_PLUS_UPDATE_REQUEST,
+	))
+
+	if _, err := d.c.send(ctx, reqID, msg, false); err != nil {
+		d.c.log.Errorw("Fail to unsubscribe from trade capture reports", "request", msg, "error", err)
+		return err
+	}
+	return nil
+}
+
+// resubscribe restores the subscription after a reconnect, emitting the trades done since the last
+// trade received of every instrument.
+func (d *DropCopyClient) resubscribe() {
+	d.mu.Lock()
+	subscription := d.subscription
+	d.mu.Unlock()
+	if subscription == nil {
+		return
+	}
+
+	d.c.mu.Lock()
+	lastSeqs := make(map[string]uint64, len(d.c.lastTradeSeqs))
+	for instrument, seq := range d.c.lastTradeSeqs {
+		lastSeqs[instrument] = seq
+	}
+	d.c.mu.Unlock()
+
+	reqID, trades, err := d.requestTradeCaptureReports(
+		d.c.ctx, enum.SubscriptionRequestType_SNAPSHOT_PLUS_UPDATES, *subscription, lastSeqs,
+	)
+	if err != nil {
+		d.c.log.Warnw("Fail to resubscribe to trade capture reports", "error", err)
+		return
+	}
+
+	d.mu.Lock()
+	if d.subscription == subscription {
+		d.subReqID = reqID
+	}
+	d.mu.Unlock()
+
+	d.c.emitUserTrades(trades)
+}
+
+// requestTradeCaptureReports requests the trades selected by params, skipping the trades of the
+// instruments of lastSeqs up to their TradeSeq.
+func (d *DropCopyClient) requestTradeCaptureReports(
+	ctx context.Context,
+	requestType enum.SubscriptionRequestType,
+	params TradeCaptureReportParams,
+	lastSeqs map[string]uint64,
+) (string, []models.UserTrade, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		d.c.log.Errorw("Fail to generate uuid", "error", err)
+		return "", nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST))
+
+	msg.Body.Set(field.NewTradeRequestID(id.String()))
+	msg.Body.Set(field.NewTradeRequestType(enum.TradeRequestType_ALL_TRADES))
+	msg.Body.Set(field.NewSubscriptionRequestType(requestType))
+	if params.Instrument != "" {
+		msg.Body.Set(field.NewSymbol(params.Instrument))
+	}
+	if !params.StartTime.IsZero() || !params.EndTime.IsZero() {
+		// the first date is the start and the optional second one the end of the range
+		startTime := params.StartTime
+		if startTime.IsZero() {
+			startTime = time.Unix(0, 0)
+		}
+		dates := newNoDatesRepeatingGroup()
+		dates.Add().Set(field.NewTransactTime(startTime))
+		if !params.EndTime.IsZero() {
+			dates.Add().Set(field.NewTransactTime(params.EndTime))
+		}
+		msg.Body.SetGroup(dates)
+	}
+
+	resps, err := d.c.callMulti(ctx, id.String(), msg, isLastTradeCaptureReport)
+	if err != nil {
+		d.c.log.Errorw(
+			"Fail to request trade capture reports",
+			"request", msg,
+			"error", err,
+		)
+		return "", nil, err
+	}
+
+	trades, err := decodeTradeCaptureReports(resps, params.StartSeq, lastSeqs)
+	if err != nil {
+		d.c.log.Errorw(
+			"Fail to decode TradeCaptureReport messages",
+			"request", msg,
+			"error", err,
+		)
+		return "", nil, err
+	}
+
+	return id.String(), trades, nil
+}
@@ -4,4 +4,13 @@ import "github.com/quickfixgo/enum"
 
 const (
 	orderTypeStopMarket enum.OrdType = "S"
+
+	// securityAltIDSourceMulticast marks a SecurityAltID as the multicast instrument ID.
+	securityAltIDSourceMulticast = "101"
+)
+
+// FillLiquidityInd values.
+const (
+	liquidityIndAdded   = 1
+	liquidityIndRemoved = 2
 )
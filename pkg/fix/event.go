@@ -1,18 +1,40 @@
 package fix
 
-import "github.com/chuckpreslar/emission"
+import (
+	"github.com/KyberNetwork/deribit-api/pkg/router"
+	"github.com/chuckpreslar/emission"
+)
 
-// On adds a listener to a specific event.
+const (
+	// EventConnected is emitted when the session is logged on.
+	EventConnected = "connected"
+	// EventDisconnected is emitted when a logged on session is logged out.
+	EventDisconnected = "disconnected"
+)
+
+// On adds a listener to a specific event, or to every channel matching a pattern with wildcards,
+// e.g. "book.BTC-*" or "ticker.*-PERPETUAL".
 func (c *Client) On(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		if err := c.router.On(event.(string), listener); err != nil {
+			c.log.Warnw("Fail to add listener", "pattern", event, "error", err)
+		}
+		return c.emitter
+	}
 	return c.emitter.On(event, listener)
 }
 
 // Emit emits an event.
 func (c *Client) Emit(event interface{}, args ...interface{}) *emission.Emitter {
+	c.router.Emit(event, args...)
 	return c.emitter.Emit(event, args...)
 }
 
 // Off removes a listener for an event.
 func (c *Client) Off(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		c.router.Off(event.(string), listener)
+		return c.emitter
+	}
 	return c.emitter.Off(event, listener)
 }
@@ -101,6 +101,15 @@ func getMDEntrySize(g *quickfix.Group) (float64, error) {
 	return strconv.ParseFloat(entrySizeS, 64)
 }
 
+// getMDEntryValue returns the MDEntryPx of price entries or the MDEntrySize of quantity entries
+// such as trade volume and open interest.
+func getMDEntryValue(g *quickfix.Group) (float64, error) {
+	if g.Has(tag.MDEntryPx) {
+		return getMDEntryPx(g)
+	}
+	return getMDEntrySize(g)
+}
+
 func hasMDUpdateAction(g *quickfix.Group) bool {
 	return g.Has(tag.MDUpdateAction)
 }
@@ -157,6 +166,14 @@ func getText(msg *quickfix.Message) (v string, err error) {
 	return
 }
 
+func getCxlRejReason(msg *quickfix.Message) (v enum.CxlRejReason, err error) {
+	var f field.CxlRejReasonField
+	if err = msg.Body.Get(&f); err == nil {
+		v = f.Value()
+	}
+	return
+}
+
 func getOrderStatus(msg *quickfix.Message) (v enum.OrdStatus, err error) {
 	var f field.OrdStatusField
 	if err = msg.Body.Get(&f); err == nil {
@@ -237,6 +254,22 @@ func getMaxShow(msg *quickfix.Message) (float64, error) {
 	return strconv.ParseFloat(maxShowS, 64)
 }
 
+func getLastPx(msg *quickfix.Message) (float64, error) {
+	lastPxS, err := msg.Body.GetString(tag.LastPx)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(lastPxS, 64)
+}
+
+func getLastQty(msg *quickfix.Message) (float64, error) {
+	lastQtyS, err := msg.Body.GetString(tag.LastQty)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(lastQtyS, 64)
+}
+
 func getTransactTime(msg *quickfix.Message) (v time.Time, err error) {
 	var f field.TransactTimeField
 	if err = msg.Body.Get(&f); err == nil {
@@ -287,3 +320,318 @@ func getGroupSide(g *quickfix.Group) (v enum.Side, err error) {
 func getGroupTradeID(g *quickfix.Group) (string, error) {
 	return g.GetString(tagDeribitTradeID)
 }
+
+func getMassCancelResponse(msg *quickfix.Message) (v enum.MassCancelResponse, err error) {
+	var f field.MassCancelResponseField
+	if err = msg.Body.Get(&f); err == nil {
+		v = f.Value()
+	}
+	return
+}
+
+func getTotalAffectedOrders(msg *quickfix.Message) (uint, error) {
+	if !msg.Body.Has(tag.TotalAffectedOrders) {
+		return 0, nil
+	}
+	v, err := msg.Body.GetInt(tag.TotalAffectedOrders)
+	if err != nil {
+		return 0, err
+	}
+	return uint(v), nil
+}
+
+func newNoFillsRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoFills,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.FillExecID),
+			quickfix.GroupElement(tag.FillPx),
+			quickfix.GroupElement(tag.FillQty),
+			quickfix.GroupElement(tag.FillLiquidityInd),
+		},
+	)
+}
+
+func hasFills(msg *quickfix.Message) bool {
+	return msg.Body.Has(tag.NoFills)
+}
+
+func getFills(msg *quickfix.Message) (*quickfix.RepeatingGroup, error) {
+	f := newNoFillsRepeatingGroup()
+	err := msg.Body.GetGroup(f)
+	return f, err
+}
+
+func getFillExecID(g *quickfix.Group) (string, error) {
+	return g.GetString(tag.FillExecID)
+}
+
+func getFillPx(g *quickfix.Group) (float64, error) {
+	fillPxS, err := g.GetString(tag.FillPx)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(fillPxS, 64)
+}
+
+func getFillQty(g *quickfix.Group) (float64, error) {
+	fillQtyS, err := g.GetString(tag.FillQty)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(fillQtyS, 64)
+}
+
+func getFillLiquidityInd(g *quickfix.Group) (int, error) {
+	if !g.Has(tag.FillLiquidityInd) {
+		return 0, nil
+	}
+	return g.GetInt(tag.FillLiquidityInd)
+}
+
+func isLastReportRequested(msg *quickfix.Message) bool {
+	v, err := msg.Body.GetBool(tag.LastRptRequested)
+	return err != nil || v
+}
+
+func getPosReqResult(msg *quickfix.Message) (v enum.PosReqResult, err error) {
+	var f field.PosReqResultField
+	if err = msg.Body.Get(&f); err == nil {
+		v = f.Value()
+	}
+	return
+}
+
+func newNoPositionsRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoPositions,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.PosType),
+			quickfix.GroupElement(tag.LongQty),
+			quickfix.GroupElement(tag.ShortQty),
+			quickfix.GroupElement(tag.Symbol),
+			quickfix.GroupElement(tag.SettlPrice),
+			quickfix.GroupElement(tag.SettlPriceType),
+			quickfix.GroupElement(tag.PriorSettlPrice),
+			quickfix.GroupElement(tagMarkPrice),
+		},
+	)
+}
+
+func getPositions(msg *quickfix.Message) (*quickfix.RepeatingGroup, error) {
+	f := newNoPositionsRepeatingGroup()
+	if !msg.Body.Has(tag.NoPositions) {
+		return f, nil
+	}
+	err := msg.Body.GetGroup(f)
+	return f, err
+}
+
+func newSecurityListNoRelatedSymRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoRelatedSym,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.Symbol),
+			quickfix.GroupElement(tag.SecurityDesc),
+			quickfix.GroupElement(tag.SecurityType),
+			quickfix.GroupElement(tag.PutOrCall),
+			quickfix.GroupElement(tag.StrikePrice),
+			quickfix.GroupElement(tag.StrikeCurrency),
+			quickfix.GroupElement(tag.Currency),
+			quickfix.GroupElement(tag.MinPriceIncrement),
+			quickfix.GroupElement(tag.MinTradeVol),
+			quickfix.GroupElement(tag.ContractMultiplier),
+			quickfix.GroupElement(tag.IssueDate),
+			quickfix.GroupElement(tag.MaturityDate),
+			quickfix.GroupElement(tag.MaturityTime),
+			quickfix.GroupElement(tag.UnderlyingSymbol),
+			quickfix.NewRepeatingGroup(
+				tag.NoSecurityAltID,
+				quickfix.GroupTemplate{
+					quickfix.GroupElement(tag.SecurityAltID),
+					quickfix.GroupElement(tag.SecurityAltIDSource),
+				},
+			),
+		},
+	)
+}
+
+func getSecurityListRelatedSym(msg *quickfix.Message) (*quickfix.RepeatingGroup, error) {
+	f := newSecurityListNoRelatedSymRepeatingGroup()
+	if !msg.Body.Has(tag.NoRelatedSym) {
+		return f, nil
+	}
+	err := msg.Body.GetGroup(f)
+	return f, err
+}
+
+func getSecurityAltIDs(g *quickfix.Group) (*quickfix.RepeatingGroup, error) {
+	f := quickfix.NewRepeatingGroup(
+		tag.NoSecurityAltID,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.SecurityAltID),
+			quickfix.GroupElement(tag.SecurityAltIDSource),
+		},
+	)
+	if !g.Has(tag.NoSecurityAltID) {
+		return f, nil
+	}
+	err := g.GetGroup(f)
+	return f, err
+}
+
+// getGroupFloat returns the value of an optional float field of a group, or 0 if it is missing.
+func getGroupFloat(g *quickfix.Group, t quickfix.Tag) (float64, error) {
+	if !g.Has(t) {
+		return 0, nil
+	}
+	s, err := g.GetString(t)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// getGroupString returns the value of an optional string field of a group, or "" if it is missing.
+func getGroupString(g *quickfix.Group, t quickfix.Tag) (string, error) {
+	if !g.Has(t) {
+		return "", nil
+	}
+	return g.GetString(t)
+}
+
+func newTradeCaptureNoSidesRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoSides,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.Side),
+			quickfix.GroupElement(tag.OrderID),
+			quickfix.GroupElement(tag.ClOrdID),
+			quickfix.GroupElement(tag.Commission),
+			quickfix.GroupElement(tag.AggressorIndicator),
+			quickfix.GroupElement(tagDeribitLabel),
+		},
+	)
+}
+
+func getTradeCaptureSides(msg *quickfix.Message) (*quickfix.RepeatingGroup, error) {
+	f := newTradeCaptureNoSidesRepeatingGroup()
+	err := msg.Body.GetGroup(f)
+	return f, err
+}
+
+func newNoDatesRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoDates,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.TransactTime),
+		},
+	)
+}
+
+func getTradeRequestStatus(msg *quickfix.Message) (v enum.TradeRequestStatus, err error) {
+	var f field.TradeRequestStatusField
+	if err = msg.Body.Get(&f); err == nil {
+		v = f.Value()
+	}
+	return
+}
+
+// isLastTradeCaptureReport reports whether msg completes a TradeCaptureReportRequest: a rejected
+// or empty TradeCaptureReportRequestAck, or the TradeCaptureReport with LastRptRequested.
+func isLastTradeCaptureReport(msg *quickfix.Message) bool {
+	if msg.IsMsgTypeOf(string(enum.MsgType_TRADE_CAPTURE_REPORT)) {
+		v, err := msg.Body.GetBool(tag.LastRptRequested)
+		return err == nil && v
+	}
+
+	status, err := getTradeRequestStatus(msg)
+	if err != nil || status != enum.TradeRequestStatus_ACCEPTED {
+		return true
+	}
+	total, err := msg.Body.GetInt(tag.TotNumTradeReports)
+	return err == nil && total == 0
+}
+
+func newMassQuoteNoQuoteEntriesRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoQuoteEntries,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.QuoteEntryID),
+			quickfix.GroupElement(tag.Symbol),
+			quickfix.GroupElement(tag.BidPx),
+			quickfix.GroupElement(tag.OfferPx),
+			quickfix.GroupElement(tag.BidSize),
+			quickfix.GroupElement(tag.OfferSize),
+		},
+	)
+}
+
+func newMassQuoteNoQuoteSetsRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoQuoteSets,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.QuoteSetID),
+			quickfix.GroupElement(tag.TotNoQuoteEntries),
+			newMassQuoteNoQuoteEntriesRepeatingGroup(),
+		},
+	)
+}
+
+func newMassQuoteAckNoQuoteEntriesRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoQuoteEntries,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.QuoteEntryID),
+			quickfix.GroupElement(tag.Symbol),
+			quickfix.GroupElement(tag.QuoteEntryStatus),
+			quickfix.GroupElement(tag.QuoteEntryRejectReason),
+		},
+	)
+}
+
+func newMassQuoteAckNoQuoteSetsRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoQuoteSets,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.QuoteSetID),
+			quickfix.GroupElement(tag.TotNoQuoteEntries),
+			newMassQuoteAckNoQuoteEntriesRepeatingGroup(),
+		},
+	)
+}
+
+func newQuoteCancelNoQuoteEntriesRepeatingGroup() *quickfix.RepeatingGroup {
+	return quickfix.NewRepeatingGroup(
+		tag.NoQuoteEntries,
+		quickfix.GroupTemplate{
+			quickfix.GroupElement(tag.Symbol),
+		},
+	)
+}
+
+func getQuoteStatus(msg *quickfix.Message) (v enum.QuoteStatus, err error) {
+	var f field.QuoteStatusField
+	if err = msg.Body.Get(&f); err == nil {
+		v = f.Value()
+	}
+	return
+}
+
+func getMassQuoteAckQuoteSets(msg *quickfix.Message) (*quickfix.RepeatingGroup, error) {
+	f := newMassQuoteAckNoQuoteSetsRepeatingGroup()
+	if !msg.Body.Has(tag.NoQuoteSets) {
+		return f, nil
+	}
+	err := msg.Body.GetGroup(f)
+	return f, err
+}
+
+func getMassQuoteAckQuoteEntries(g *quickfix.Group) (*quickfix.RepeatingGroup, error) {
+	f := newMassQuoteAckNoQuoteEntriesRepeatingGroup()
+	if !g.Has(tag.NoQuoteEntries) {
+		return f, nil
+	}
+	err := g.GetGroup(f)
+	return f, err
+}
@@ -47,10 +47,29 @@ func createMockInitiator(
 	return i, nil
 }
 
+// pendingInitiator connects without logging on, onStart drives the logon instead.
+type pendingInitiator struct {
+	onStart func()
+	stopped bool
+}
+
+func (i *pendingInitiator) Start() error {
+	if i.onStart != nil {
+		i.onStart()
+	}
+	return nil
+}
+
+func (i *pendingInitiator) Stop() {
+	i.stopped = true
+}
+
 func (i *MockInitiator) Start() error {
 	for sessionID, s := range i.sessionSettings {
 		// send Logon message
-		i.app.ToAdmin(quickfix.NewMessage(), sessionID)
+		logon := quickfix.NewMessage()
+		logon.Header.SetString(tag.MsgType, string(enum.MsgType_LOGON))
+		i.app.ToAdmin(logon, sessionID)
 
 		if !s.HasSetting("SocketConnectHost") {
 			return errors.New("Conditionally Required Setting: SocketConnectHost")
@@ -100,9 +119,24 @@ func (i *MockInitiator) send(msg *quickfix.Message) error {
 	var err2 error
 	var reqIDTag quickfix.Tag
 	fixMsgType := enum.MsgType(msgType)
-	if fixMsgType == enum.MsgType_ORDER_SINGLE {
+	switch fixMsgType {
+	case enum.MsgType_ORDER_SINGLE,
+		enum.MsgType_ORDER_CANCEL_REQUEST,
+		enum.MsgType_ORDER_CANCEL_REPLACE_REQUEST,
+		enum.MsgType_ORDER_MASS_CANCEL_REQUEST,
+		enum.MsgType_ORDER_STATUS_REQUEST:
 		reqIDTag = tag.ClOrdID
-	} else {
+	case enum.MsgType_ORDER_MASS_STATUS_REQUEST:
+		reqIDTag = tag.MassStatusReqID
+	case enum.MsgType_REQUEST_FOR_POSITIONS:
+		reqIDTag = tag.PosReqID
+	case enum.MsgType_SECURITY_LIST_REQUEST:
+		reqIDTag = tag.SecurityReqID
+	case enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST:
+		reqIDTag = tag.TradeRequestID
+	case enum.MsgType_MASS_QUOTE, enum.MsgType_QUOTE_CANCEL:
+		reqIDTag = tag.QuoteID
+	default:
 		reqIDTag, err2 = getReqIDTagFromMsgType(enum.MsgType(msgType))
 		if err2 != nil {
 			return err2
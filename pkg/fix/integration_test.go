@@ -0,0 +1,363 @@
+package fix
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/quickfixgo/enum"
+	"github.com/stretchr/testify/require"
+)
+
+func newAcceptorClient(t *testing.T, a *testAcceptor, heartBtInt int, secret string) (*Client, error) {
+	t.Helper()
+
+	c, err := New(context.Background(), Config{
+		APIKey:       apiKey,
+		SecretKey:    secret,
+		Settings:     a.clientSettings(heartBtInt),
+		LogonTimeout: 5 * time.Second,
+	})
+	if err == nil {
+		t.Cleanup(c.Close)
+	}
+	return c, err
+}
+
+func TestAcceptorLogon(t *testing.T) {
+	a := newTestAcceptor(t)
+
+	_, err := newAcceptorClient(t, a, 30, "wrong_secret")
+	var logonErr *LogonError
+	require.ErrorAs(t, err, &logonErr)
+	require.Equal(t, "invalid_credentials", logonErr.Reason)
+
+	a.rejectNext(enum.MsgType_LOGON, "too_many_requests")
+	_, err = newAcceptorClient(t, a, 30, secretKey)
+	require.ErrorAs(t, err, &logonErr)
+	require.Equal(t, "too_many_requests", logonErr.Reason)
+
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+	require.True(t, c.IsConnected())
+}
+
+func TestAcceptorHeartbeat(t *testing.T) {
+	a := newTestAcceptor(t)
+	c, err := newAcceptorClient(t, a, 1, secretKey)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return a.heartbeatCount() >= 2 }, 5*time.Second, 50*time.Millisecond)
+	require.True(t, c.IsConnected())
+}
+
+// nolint:funlen
+func TestAcceptorMarketData(t *testing.T) {
+	a := newTestAcceptor(t)
+	a.setMarketData("BTC-PERPETUAL", testMarketData{
+		markPrice: 20000.5,
+		snapshot: []testMDEntry{
+			{entryType: enum.MDEntryType_BID, price: 20000, size: 100},
+			{entryType: enum.MDEntryType_OFFER, price: 20001, size: 50},
+		},
+		updates: [][]testMDEntry{
+			{
+				{action: enum.MDUpdateAction_CHANGE, entryType: enum.MDEntryType_BID, price: 20000, size: 80},
+				{action: enum.MDUpdateAction_DELETE, entryType: enum.MDEntryType_OFFER, price: 20001, size: 0},
+			},
+		},
+	})
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+
+	type bookEvent struct {
+		event    *models.OrderBookRawNotification
+		snapshot bool
+	}
+	events := make(chan bookEvent, 2)
+	c.On("book.BTC-PERPETUAL", func(e *models.OrderBookRawNotification, snapshot bool) {
+		events <- bookEvent{e, snapshot}
+	})
+
+	require.NoError(t, c.Subscribe(context.Background(), []string{"book.BTC-PERPETUAL"}))
+
+	snapshot := <-events
+	require.True(t, snapshot.snapshot)
+	require.Equal(t, []models.OrderBookNotificationItem{{Action: "new", Price: 20000, Amount: 100}}, snapshot.event.Bids)
+	require.Equal(t, []models.OrderBookNotificationItem{{Action: "new", Price: 20001, Amount: 50}}, snapshot.event.Asks)
+
+	update := <-events
+	require.False(t, update.snapshot)
+	require.Equal(t, []models.OrderBookNotificationItem{{Action: "change", Price: 20000, Amount: 80}}, update.event.Bids)
+	require.Equal(t, []models.OrderBookNotificationItem{{Action: "delete", Price: 20001, Amount: 0}}, update.event.Asks)
+
+	require.NoError(t, c.Unsubscribe(context.Background(), []string{"book.BTC-PERPETUAL"}))
+
+	a.rejectNext(enum.MsgType_MARKET_DATA_REQUEST, "instrument_not_found")
+	require.EqualError(t, c.Subscribe(context.Background(), []string{"trades.UNKNOWN"}), "instrument_not_found")
+}
+
+// nolint:funlen
+func TestAcceptorOrders(t *testing.T) {
+	a := newTestAcceptor(t)
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	trades := make(chan *models.UserTradesNotification, 2)
+	c.On("user.trades.BTC-PERPETUAL.raw", func(e *models.UserTradesNotification) {
+		trades <- e
+	})
+
+	sell, err := c.CreateOrder(ctx, "BTC-PERPETUAL", enum.Side_SELL, 100, 20010,
+		enum.OrdType_LIMIT, enum.TimeInForce_GOOD_TILL_CANCEL, "", "maker")
+	require.NoError(t, err)
+	require.Equal(t, "open", sell.OrderState)
+	require.Equal(t, "sell", sell.Direction)
+	require.Equal(t, "maker", sell.Label)
+
+	buy, err := c.CreateOrder(ctx, "BTC-PERPETUAL", enum.Side_BUY, 40, 20020,
+		enum.OrdType_LIMIT, enum.TimeInForce_GOOD_TILL_CANCEL, "", "taker")
+	require.NoError(t, err)
+	require.Equal(t, "filled", buy.OrderState)
+	require.Equal(t, 40.0, buy.FilledAmount)
+	require.Equal(t, 20010.0, buy.AveragePrice)
+
+	liquidity := make(map[string]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case e := <-trades:
+			require.Len(t, *e, 1)
+			trade := (*e)[0]
+			require.Equal(t, 40.0, trade.Amount)
+			require.Equal(t, 20010.0, trade.Price)
+			liquidity[trade.OrderID] = trade.Liquidity
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing user trade")
+		}
+	}
+	require.Equal(t, map[string]string{sell.OrderID: "M", buy.OrderID: "T"}, liquidity)
+
+	cancelled, err := c.CancelOrder(ctx, sell.OrderID)
+	require.NoError(t, err)
+	require.Equal(t, "cancelled", cancelled.OrderState)
+	require.Equal(t, 40.0, cancelled.FilledAmount)
+
+	_, err = c.CancelOrder(ctx, sell.OrderID)
+	require.ErrorIs(t, err, ErrOrderCancelRejected)
+	require.EqualError(t, err, "order cancel rejected: order_not_found (reason 1)")
+
+	a.rejectNext(enum.MsgType_ORDER_SINGLE, "not_enough_funds")
+	_, err = c.CreateOrder(ctx, "BTC-PERPETUAL", enum.Side_BUY, 40, 20020,
+		enum.OrdType_LIMIT, enum.TimeInForce_GOOD_TILL_CANCEL, "", "")
+	require.EqualError(t, err, "not_enough_funds")
+}
+
+// nolint:funlen
+func TestAcceptorDropCopy(t *testing.T) {
+	a := newTestAcceptor(t)
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+	d, err := NewDropCopyClient(context.Background(), Config{
+		APIKey:       apiKey,
+		SecretKey:    secretKey,
+		Settings:     a.dropCopySettings(30),
+		LogonTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+	ctx := context.Background()
+
+	trade := func(side enum.Side, amount float64) {
+		_, err := c.CreateOrder(ctx, "BTC-PERPETUAL", side, amount, 20010,
+			enum.OrdType_LIMIT, enum.TimeInForce_GOOD_TILL_CANCEL, "", "")
+		require.NoError(t, err)
+	}
+	trade(enum.Side_SELL, 100)
+	trade(enum.Side_BUY, 40)
+	time.Sleep(20 * time.Millisecond)
+	startTime := time.Now()
+	trade(enum.Side_BUY, 10)
+
+	trades, err := d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{})
+	require.NoError(t, err)
+	require.Len(t, trades, 4)
+	for i, trade := range trades {
+		require.Equal(t, uint64(i+1), trade.TradeSeq)
+		require.Equal(t, "BTC-PERPETUAL", trade.InstrumentName)
+		require.Equal(t, 20010.0, trade.Price)
+	}
+	require.Equal(t, "sell", trades[0].Direction)
+	require.Equal(t, "M", trades[0].Liquidity)
+	require.Equal(t, "buy", trades[1].Direction)
+	require.Equal(t, "T", trades[1].Liquidity)
+	require.Equal(t, 40.0, trades[1].Amount)
+
+	trades, err = d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{StartTime: startTime})
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+	require.Equal(t, 10.0, trades[0].Amount)
+
+	trades, err = d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{StartSeq: 4})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+	require.Equal(t, uint64(4), trades[0].TradeSeq)
+
+	trades, err = d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{Instrument: "ETH-PERPETUAL"})
+	require.NoError(t, err)
+	require.Empty(t, trades)
+
+	a.rejectNext(enum.MsgType_TRADE_CAPTURE_REPORT_REQUEST, "not_authorized")
+	_, err = d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{})
+	require.ErrorIs(t, err, ErrTradeCaptureRejected)
+	require.EqualError(t, err, "trade capture report request rejected: not_authorized")
+
+	live := make(chan models.UserTrade, 4)
+	d.On("user.trades.BTC-PERPETUAL.raw", func(e *models.UserTradesNotification) {
+		for _, trade := range *e {
+			live <- trade
+		}
+	})
+	trades, err = d.SubscribeTradeCaptureReports(ctx, TradeCaptureReportParams{StartSeq: 4})
+	require.NoError(t, err)
+	require.Len(t, trades, 1)
+
+	trade(enum.Side_BUY, 5)
+	liquidity := make(map[uint64]string)
+	for i := 0; i < 2; i++ {
+		select {
+		case trade := <-live:
+			require.Equal(t, 5.0, trade.Amount)
+			liquidity[trade.TradeSeq] = trade.Liquidity
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing trade capture report")
+		}
+	}
+	require.Equal(t, map[uint64]string{5: "M", 6: "T"}, liquidity)
+
+	require.NoError(t, d.UnsubscribeTradeCaptureReports(ctx))
+	// the unsubscription is not acknowledged, wait for it with a request
+	_, err = d.GetTradeCaptureReports(ctx, TradeCaptureReportParams{StartSeq: 7})
+	require.NoError(t, err)
+	trade(enum.Side_BUY, 5)
+	require.Equal(t, 8, a.tradeCount())
+	select {
+	case trade := <-live:
+		t.Fatalf("unexpected trade %+v after unsubscription", trade)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// nolint:funlen
+func TestAcceptorMassQuote(t *testing.T) {
+	a := newTestAcceptor(t)
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	perpetuals := NewQuoteSet("perpetuals").
+		Quote("btc", "BTC-PERPETUAL", 20000, 10, 20010, 10).
+		Bid("eth", "ETH-PERPETUAL", 1500, 5)
+	futures := NewQuoteSet("futures").
+		Quote("btc", "BTC-27DEC24", 20100, 10, 20050, 10).
+		Offer("eth", "ETH-27DEC24", 0, 5)
+
+	ack, err := c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.NoError(t, err)
+	require.Equal(t, enum.QuoteStatus_ACCEPTED, ack.Status)
+	require.Len(t, ack.Entries, 4)
+	require.Equal(t, []QuoteEntryAck{
+		{
+			QuoteSetID: "futures", QuoteEntryID: "btc", Instrument: "BTC-27DEC24",
+			Status: enum.QuoteEntryStatus_REJECTED, RejectReason: "invalid_bid_ask_spread",
+		},
+		{
+			QuoteSetID: "futures", QuoteEntryID: "eth", Instrument: "ETH-27DEC24",
+			Status: enum.QuoteEntryStatus_REJECTED, RejectReason: "invalid_price",
+		},
+	}, ack.Rejected())
+
+	// the changed and the rejected entries are sent again
+	perpetuals.Entries[1].BidPrice = 1501
+	_, err = c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.NoError(t, err)
+
+	futures.Entries[0].BidPrice = 20000
+	futures.Entries[1].OfferPrice = 1510
+	_, err = c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.NoError(t, err)
+
+	ack, err = c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.NoError(t, err)
+	require.Empty(t, ack.QuoteID)
+
+	quotes, counts := a.quoteState()
+	require.Equal(t, []int{4, 3, 2}, counts)
+	require.Len(t, quotes, 4)
+	require.Equal(t, perpetuals.Entries[1], quotes["perpetuals/eth"])
+
+	a.rejectNext(enum.MsgType_MASS_QUOTE, "mmp_frozen")
+	perpetuals.Entries[0].OfferSize = 20
+	_, err = c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.ErrorIs(t, err, ErrMassQuoteRejected)
+	require.EqualError(t, err, "mass quote rejected: mmp_frozen")
+
+	ack, err = c.CancelQuotes(ctx, "ETH-PERPETUAL", "ETH-27DEC24")
+	require.NoError(t, err)
+	require.Equal(t, enum.QuoteStatus_CANCEL_FOR_SYMBOL, ack.Status)
+	quotes, _ = a.quoteState()
+	require.Len(t, quotes, 2)
+
+	// the entry of the rejected request and the cancelled ones are sent again
+	_, err = c.MassQuote(ctx, []QuoteSet{*perpetuals, *futures})
+	require.NoError(t, err)
+	quotes, counts = a.quoteState()
+	require.Equal(t, []int{4, 3, 2, 3}, counts)
+	require.Len(t, quotes, 4)
+	require.Equal(t, 20.0, quotes["perpetuals/btc"].OfferSize)
+
+	ack, err = c.CancelQuotes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, enum.QuoteStatus_CANCELED_ALL, ack.Status)
+	quotes, _ = a.quoteState()
+	require.Empty(t, quotes)
+}
+
+func TestAcceptorMassQuoteFill(t *testing.T) {
+	a := newTestAcceptor(t)
+	c, err := newAcceptorClient(t, a, 30, secretKey)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	trades := make(chan *models.UserTradesNotification, 2)
+	c.On("user.trades.BTC-PERPETUAL.raw", func(e *models.UserTradesNotification) {
+		trades <- e
+	})
+
+	quotes := NewQuoteSet("perpetuals").Quote("btc", "BTC-PERPETUAL", 20000, 10, 20010, 10)
+	_, err = c.MassQuote(ctx, []QuoteSet{*quotes})
+	require.NoError(t, err)
+
+	_, err = c.CreateOrder(ctx, "BTC-PERPETUAL", enum.Side_SELL, 10, 20000,
+		enum.OrdType_LIMIT, enum.TimeInForce_GOOD_TILL_CANCEL, "", "")
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-trades:
+		case <-time.After(5 * time.Second):
+			t.Fatal("missing user trade")
+		}
+	}
+
+	// the bid is filled, the unchanged entry is sent again to quote it back
+	ack, err := c.MassQuote(ctx, []QuoteSet{*quotes})
+	require.NoError(t, err)
+	require.NotEmpty(t, ack.QuoteID)
+	_, counts := a.quoteState()
+	require.Equal(t, []int{1, 1}, counts)
+
+	ack, err = c.MassQuote(ctx, []QuoteSet{*quotes})
+	require.NoError(t, err)
+	require.Empty(t, ack.QuoteID)
+}
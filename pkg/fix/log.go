@@ -0,0 +1,123 @@
+package fix
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/quickfixgo/quickfix"
+)
+
+const (
+	auditTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+	soh             = '\x01'
+)
+
+// nolint:gochecknoglobals
+var (
+	passwordField = []byte("\x01554=")
+	logoutMsgType = []byte("\x0135=5\x01")
+)
+
+// auditLogFactory creates logs that share a single writer.
+type auditLogFactory struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLogFactory returns a LogFactory that writes every raw FIX message and session event to w,
+// one timestamped line each, with SOH shown as '|' and the password redacted.
+func NewAuditLogFactory(w io.Writer) quickfix.LogFactory {
+	return &auditLogFactory{mu: &sync.Mutex{}, w: w}
+}
+
+func (f *auditLogFactory) Create() (quickfix.Log, error) {
+	return &auditLog{factory: f, prefix: "GLOBAL"}, nil
+}
+
+func (f *auditLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	return &auditLog{factory: f, prefix: sessionID.String()}, nil
+}
+
+type auditLog struct {
+	factory *auditLogFactory
+	prefix  string
+}
+
+func (l *auditLog) OnIncoming(msg []byte) {
+	l.write("IN", formatRawMessage(msg))
+}
+
+func (l *auditLog) OnOutgoing(msg []byte) {
+	l.write("OUT", formatRawMessage(msg))
+}
+
+func (l *auditLog) OnEvent(text string) {
+	l.write("EVENT", text)
+}
+
+func (l *auditLog) OnEventf(format string, a ...interface{}) {
+	l.write("EVENT", fmt.Sprintf(format, a...))
+}
+
+func (l *auditLog) write(direction, text string) {
+	l.factory.mu.Lock()
+	defer l.factory.mu.Unlock()
+
+	_, _ = fmt.Fprintf(
+		l.factory.w, "%s %s %s %s\n",
+		time.Now().UTC().Format(auditTimeFormat), l.prefix, direction, text,
+	)
+}
+
+// formatRawMessage makes a raw FIX message printable and hides the password of Logon messages.
+func formatRawMessage(msg []byte) string {
+	out := make([]byte, len(msg))
+	copy(out, msg)
+
+	if i := bytes.Index(out, passwordField); i >= 0 {
+		start := i + len(passwordField)
+		end := bytes.IndexByte(out[start:], soh)
+		if end < 0 {
+			end = len(out) - start
+		}
+		out = append(out[:start], append([]byte("***"), out[start+end:]...)...)
+	}
+
+	return string(bytes.ReplaceAll(out, []byte{soh}, []byte{'|'}))
+}
+
+// logonLogFactory watches incoming messages for the Logout a server sends to reject a Logon,
+// which quickfix drops without calling FromAdmin.
+type logonLogFactory struct {
+	quickfix.LogFactory
+	c *Client
+}
+
+func (f logonLogFactory) CreateSessionLog(sessionID quickfix.SessionID) (quickfix.Log, error) {
+	log, err := f.LogFactory.CreateSessionLog(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return logonLog{Log: log, c: f.c}, nil
+}
+
+type logonLog struct {
+	quickfix.Log
+	c *Client
+}
+
+func (l logonLog) OnIncoming(msg []byte) {
+	l.Log.OnIncoming(msg)
+
+	if !bytes.Contains(msg, logoutMsgType) || l.c.IsConnected() {
+		return
+	}
+	logout := quickfix.NewMessage()
+	if err := quickfix.ParseMessage(logout, bytes.NewBuffer(msg)); err != nil {
+		return
+	}
+	l.c.rejectLogon(logout)
+}
@@ -0,0 +1,45 @@
+package fix
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/quickfixgo/quickfix"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	factory := NewAuditLogFactory(&buf)
+	sessionID := quickfix.SessionID{BeginString: "FIX.4.4", SenderCompID: "FIX_TEST", TargetCompID: "DERIBITSERVER"}
+	log, err := factory.CreateSessionLog(sessionID)
+	require.NoError(t, err)
+
+	log.OnOutgoing([]byte("8=FIX.4.4\x019=40\x0135=A\x01553=api_key\x01554=secret\x01108=30\x0110=000\x01"))
+	log.OnIncoming([]byte("8=FIX.4.4\x019=5\x0135=0\x0110=000\x01"))
+	log.OnEventf("Sent %s", "logon")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasSuffix(lines[0],
+		"FIX.4.4:FIX_TEST->DERIBITSERVER OUT 8=FIX.4.4|9=40|35=A|553=api_key|554=***|108=30|10=000|"))
+	require.True(t, strings.HasSuffix(lines[1], "FIX.4.4:FIX_TEST->DERIBITSERVER IN 8=FIX.4.4|9=5|35=0|10=000|"))
+	require.True(t, strings.HasSuffix(lines[2], "FIX.4.4:FIX_TEST->DERIBITSERVER EVENT Sent logon"))
+	require.NotContains(t, buf.String(), "secret")
+}
+
+func TestFormatRawMessage(t *testing.T) {
+	tests := []struct {
+		msg      string
+		expected string
+	}{
+		{"8=FIX.4.4\x0135=0\x01", "8=FIX.4.4|35=0|"},
+		{"8=FIX.4.4\x01554=secret\x01", "8=FIX.4.4|554=***|"},
+		{"8=FIX.4.4\x01554=secret", "8=FIX.4.4|554=***"},
+		{"8=FIX.4.4\x011554=value\x01", "8=FIX.4.4|1554=value|"},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.expected, formatRawMessage([]byte(test.msg)))
+	}
+}
@@ -0,0 +1,276 @@
+package fix
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/quickfixgo/enum"
+	"github.com/quickfixgo/field"
+	"github.com/quickfixgo/quickfix"
+	"github.com/quickfixgo/tag"
+)
+
+// QuoteEntry is a two-sided quote of an instrument. A side with a zero size is not quoted.
+type QuoteEntry struct {
+	ID         string
+	Instrument string
+	BidPrice   float64
+	BidSize    float64
+	OfferPrice float64
+	OfferSize  float64
+}
+
+// QuoteSet is a set of quote entries sent in a MassQuote, e.g. the entries of an underlying.
+// Entry IDs are unique within their set.
+type QuoteSet struct {
+	ID      string
+	Entries []QuoteEntry
+}
+
+// NewQuoteSet returns an empty quote set.
+func NewQuoteSet(id string) *QuoteSet {
+	return &QuoteSet{ID: id}
+}
+
+// Quote adds a two-sided entry to the set.
+func (s *QuoteSet) Quote(id, instrument string, bidPrice, bidSize, offerPrice, offerSize float64) *QuoteSet {
+	s.Entries = append(s.Entries, QuoteEntry{
+		ID:         id,
+		Instrument: instrument,
+		BidPrice:   bidPrice,
+		BidSize:    bidSize,
+		OfferPrice: offerPrice,
+		OfferSize:  offerSize,
+	})
+	return s
+}
+
+// Bid adds an entry quoting only the bid side to the set.
+func (s *QuoteSet) Bid(id, instrument string, price, size float64) *QuoteSet {
+	return s.Quote(id, instrument, price, size, 0, 0)
+}
+
+// Offer adds an entry quoting only the offer side to the set.
+func (s *QuoteSet) Offer(id, instrument string, price, size float64) *QuoteSet {
+	return s.Quote(id, instrument, 0, 0, price, size)
+}
+
+// MassQuoteAck is the acknowledgement of a MassQuote or QuoteCancel.
+type MassQuoteAck struct {
+	QuoteID string
+	Status  enum.QuoteStatus
+	// Entries are the entries reported by the server, with the reason of the rejected ones.
+	Entries []QuoteEntryAck
+}
+
+// QuoteEntryAck is the status of a quote entry in a MassQuoteAck.
+type QuoteEntryAck struct {
+	QuoteSetID   string
+	QuoteEntryID string
+	Instrument   string
+	Status       enum.QuoteEntryStatus
+	// RejectReason is set if the entry is rejected, e.g. "invalid_price".
+	RejectReason string
+}
+
+// Rejected returns the rejected entries.
+func (a *MassQuoteAck) Rejected() []QuoteEntryAck {
+	var rejected []QuoteEntryAck
+	for _, entry := range a.Entries {
+		if entry.Status == enum.QuoteEntryStatus_REJECTED || entry.RejectReason != "" {
+			rejected = append(rejected, entry)
+		}
+	}
+	return rejected
+}
+
+func quoteKey(setID, entryID string) string {
+	return setID + "/" + entryID
+}
+
+// MassQuote sends the entries of sets which differ from the last accepted ones, so that a full
+// re-quote only sends the changed entries. If no entry changed, nothing is sent and the returned
+// acknowledgement has no QuoteID. Rejected entries are sent again by the next MassQuote.
+// The quote state is reset by CancelQuotes and on logout, and the entries of an instrument are
+// sent again after any of its orders is filled.
+func (c *Client) MassQuote(ctx context.Context, sets []QuoteSet) (*MassQuoteAck, error) {
+	c.quoting.Lock()
+	defer c.quoting.Unlock()
+
+	changed := c.changedQuoteSets(sets)
+	if len(changed) == 0 {
+		return &MassQuoteAck{Status: enum.QuoteStatus_ACCEPTED}, nil
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_MASS_QUOTE))
+
+	msg.Body.Set(field.NewQuoteID(id.String()))
+	msg.Body.Set(field.NewQuoteResponseLevel(enum.QuoteResponseLevel_ACKNOWLEDGE_EACH_QUOTE_MESSAGE))
+	msg.Body.SetGroup(newMassQuoteSets(changed))
+
+	ack, err := c.callMassQuote(ctx, id.String(), msg)
+	c.updateQuotes(changed, ack, err)
+	return ack, err
+}
+
+// CancelQuotes cancels the quotes of instruments, or all quotes if instruments is empty.
+func (c *Client) CancelQuotes(ctx context.Context, instruments ...string) (*MassQuoteAck, error) {
+	c.quoting.Lock()
+	defer c.quoting.Unlock()
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		c.log.Errorw("Fail to generate uuid", "error", err)
+		return nil, err
+	}
+
+	msg := quickfix.NewMessage()
+	msg.Header.Set(field.NewMsgType(enum.MsgType_QUOTE_CANCEL))
+
+	msg.Body.Set(field.NewQuoteID(id.String()))
+	if len(instruments) == 0 {
+		msg.Body.Set(field.NewQuoteCancelType(enum.QuoteCancelType_CANCEL_ALL_QUOTES))
+	} else {
+		msg.Body.Set(field.NewQuoteCancelType(enum.QuoteCancelType_CANCEL_FOR_ONE_OR_MORE_SECURITIES))
+		entries := newQuoteCancelNoQuoteEntriesRepeatingGroup()
+		for _, instrument := range instruments {
+			entries.Add().Set(field.NewSymbol(instrument))
+		}
+		msg.Body.SetGroup(entries)
+	}
+
+	// the quotes may be cancelled even if the acknowledgement is lost
+	c.mu.Lock()
+	cancelled := make(map[string]bool, len(instruments))
+	for _, instrument := range instruments {
+		cancelled[instrument] = true
+	}
+	for key, entry := range c.quotes {
+		if len(instruments) == 0 || cancelled[entry.Instrument] {
+			delete(c.quotes, key)
+		}
+	}
+	c.mu.Unlock()
+
+	return c.callMassQuote(ctx, id.String(), msg)
+}
+
+func (c *Client) callMassQuote(ctx context.Context, id string, msg *quickfix.Message) (*MassQuoteAck, error) {
+	resp, err := c.Call(ctx, id, msg)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to send quotes",
+			"request", msg,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	ack, err := decodeMassQuoteAck(resp)
+	if err != nil {
+		c.log.Errorw(
+			"Fail to decode MassQuoteAcknowledgement message",
+			"request", msg,
+			"response", resp,
+			"error", err,
+		)
+		return nil, err
+	}
+
+	return &ack, nil
+}
+
+// changedQuoteSets returns the sets with only the entries differing from the quote state.
+func (c *Client) changedQuoteSets(sets []QuoteSet) []QuoteSet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.filledQuotes = make(map[string]bool)
+	var changed []QuoteSet
+	for _, set := range sets {
+		var entries []QuoteEntry
+		for _, entry := range set.Entries {
+			if quoted, ok := c.quotes[quoteKey(set.ID, entry.ID)]; !ok || quoted != entry {
+				entries = append(entries, entry)
+			}
+		}
+		if len(entries) > 0 {
+			changed = append(changed, QuoteSet{ID: set.ID, Entries: entries})
+		}
+	}
+	return changed
+}
+
+// updateQuotes records the entries of sets accepted by ack. The state of all the entries is unknown
+// if the request failed, they are sent again by the next MassQuote, as are the entries of the
+// instruments filled while the request was pending.
+func (c *Client) updateQuotes(sets []QuoteSet, ack *MassQuoteAck, err error) {
+	rejected := make(map[string]bool)
+	if ack != nil {
+		for _, entry := range ack.Rejected() {
+			rejected[quoteKey(entry.QuoteSetID, entry.QuoteEntryID)] = true
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, set := range sets {
+		for _, entry := range set.Entries {
+			key := quoteKey(set.ID, entry.ID)
+			if err != nil || rejected[key] || c.filledQuotes[entry.Instrument] {
+				delete(c.quotes, key)
+			} else {
+				c.quotes[key] = entry
+			}
+		}
+	}
+}
+
+// invalidateQuotes drops the quote state of instrument after one of its orders is filled.
+// ExecutionReports do not tell quote orders apart, and a filled quote no longer rests with the
+// size of its entry, so the next MassQuote sends the entries of instrument again.
+func (c *Client) invalidateQuotes(instrument string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key, entry := range c.quotes {
+		if entry.Instrument == instrument {
+			delete(c.quotes, key)
+		}
+	}
+	c.filledQuotes[instrument] = true
+}
+
+func newMassQuoteSets(sets []QuoteSet) *quickfix.RepeatingGroup {
+	group := newMassQuoteNoQuoteSetsRepeatingGroup()
+	for _, set := range sets {
+		g := group.Add()
+		g.SetString(tag.QuoteSetID, set.ID)
+		g.SetInt(tag.TotNoQuoteEntries, len(set.Entries))
+
+		entries := newMassQuoteNoQuoteEntriesRepeatingGroup()
+		for _, entry := range set.Entries {
+			e := entries.Add()
+			e.SetString(tag.QuoteEntryID, entry.ID)
+			e.SetString(tag.Symbol, entry.Instrument)
+			if entry.BidSize > 0 {
+				e.SetString(tag.BidPx, floatToStr(entry.BidPrice))
+				e.SetString(tag.BidSize, floatToStr(entry.BidSize))
+			}
+			if entry.OfferSize > 0 {
+				e.SetString(tag.OfferPx, floatToStr(entry.OfferPrice))
+				e.SetString(tag.OfferSize, floatToStr(entry.OfferSize))
+			}
+		}
+		g.SetGroup(entries)
+	}
+	return group
+}
@@ -4,8 +4,12 @@ import "github.com/quickfixgo/quickfix"
 
 const (
 	tagCancelOnDisconnect quickfix.Tag = 9001
-	tagDeribitTradeID     quickfix.Tag = 100009
-	tagDeribitLabel       quickfix.Tag = 100010
-	tagMarkPrice          quickfix.Tag = 100090
-	tagDeribitLiquidation quickfix.Tag = 100091
+	tagDeribitAppID       quickfix.Tag = 9004
+	tagDeribitAppSig      quickfix.Tag = 9005
+	// tagDisplayMulticastInstrumentID requests multicast instrument IDs in SecurityList.
+	tagDisplayMulticastInstrumentID quickfix.Tag = 9013
+	tagDeribitTradeID               quickfix.Tag = 100009
+	tagDeribitLabel                 quickfix.Tag = 100010
+	tagMarkPrice                    quickfix.Tag = 100090
+	tagDeribitLiquidation           quickfix.Tag = 100091
 )
@@ -0,0 +1,462 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"go.uber.org/zap"
+)
+
+// Dataset is a kind of historical data the Downloader can fetch.
+type Dataset string
+
+const (
+	DatasetCandles    Dataset = "candles"
+	DatasetTrades     Dataset = "trades"
+	DatasetFunding    Dataset = "funding"
+	DatasetVolatility Dataset = "volatility"
+
+	defaultResolution   = "60"
+	defaultPageSize     = 1000
+	defaultTradesWindow = 24 * time.Hour
+	candlesPerWindow    = 1000
+	// fundingWindow keeps a window of hourly funding rates within one response.
+	fundingWindow = 30 * 24 * time.Hour
+	// volatilityLookback is the range covered by public/get_historical_volatility, which only
+	// returns the recent hourly series.
+	volatilityLookback = 15 * 24 * time.Hour
+	tmpFileSuffix      = ".tmp"
+	outputFilePerm     = 0o644
+	outputDirPerm      = 0o755
+)
+
+var (
+	ErrInvalidTimeRange  = errors.New("invalid time range")
+	ErrInvalidResolution = errors.New("invalid resolution")
+	ErrNoInstruments     = errors.New("no instruments or currencies given")
+	ErrBeyondLookback    = errors.New("start time is older than the lookback of the dataset")
+)
+
+// Client is the subset of the Deribit API used by the Downloader.
+type Client interface {
+	GetInstruments(ctx context.Context, params *models.GetInstrumentsParams) ([]models.Instrument, error)
+	GetTradingviewChartData(
+		ctx context.Context, params *models.GetTradingviewChartDataParams,
+	) (models.GetTradingviewChartDataResponse, error)
+	GetLastTradesByInstrumentAndTime(
+		ctx context.Context, params *models.GetLastTradesByInstrumentAndTimeParams,
+	) (models.GetLastTradesResponse, error)
+	GetLastTradesByInstrument(
+		ctx context.Context, params *models.GetLastTradesByInstrumentParams,
+	) (models.GetLastTradesResponse, error)
+	GetFundingRateHistory(
+		ctx context.Context, params *models.GetFundingRateHistoryParams,
+	) (models.GetFundingRateHistoryResponse, error)
+	GetHistoricalVolatility(
+		ctx context.Context, params *models.GetHistoricalVolatilityParams,
+	) (models.GetHistoricalVolatilityResponse, error)
+}
+
+type Config struct {
+	// Instruments to download. Currencies are expanded to their instruments of Kind.
+	Instruments []string
+	Currencies  []string
+	Kind        string
+	Datasets    []Dataset
+	Start       time.Time
+	End         time.Time
+	// Resolution is a TradingView resolution: minutes ("1", "60", ...) or "1D".
+	Resolution string
+	// Window overrides the time range covered by one output file of candles, trades and funding.
+	Window    time.Duration
+	PageSize  int
+	Format    Format
+	OutputDir string
+}
+
+// Downloader fetches historical market data into one file per dataset,
+// instrument and window. Files are written atomically, so an interrupted
+// run resumes by skipping the windows whose file already exists. A window
+// without any record is written as an empty file, so it is not fetched again.
+type Downloader struct {
+	log    *zap.SugaredLogger
+	client Client
+	cfg    Config
+}
+
+// NewDownloader creates a new Downloader instance.
+func NewDownloader(client Client, cfg Config) (*Downloader, error) {
+	if !cfg.End.After(cfg.Start) {
+		return nil, ErrInvalidTimeRange
+	}
+	if len(cfg.Instruments) == 0 && len(cfg.Currencies) == 0 {
+		return nil, ErrNoInstruments
+	}
+	if cfg.Resolution == "" {
+		cfg.Resolution = defaultResolution
+	}
+	if _, err := parseResolution(cfg.Resolution); err != nil {
+		return nil, err
+	}
+	if cfg.PageSize == 0 {
+		cfg.PageSize = defaultPageSize
+	}
+	if cfg.Format == "" {
+		cfg.Format = FormatCSV
+	}
+	for _, dataset := range cfg.Datasets {
+		if dataset == DatasetVolatility && cfg.Start.Before(time.Now().Add(-volatilityLookback)) {
+			return nil, fmt.Errorf("%w: %s covers the last %s", ErrBeyondLookback, dataset, volatilityLookback)
+		}
+	}
+
+	return &Downloader{
+		log:    zap.S(),
+		client: client,
+		cfg:    cfg,
+	}, nil
+}
+
+// Run downloads every configured dataset.
+func (d *Downloader) Run(ctx context.Context) error {
+	instruments, err := d.instruments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, dataset := range d.cfg.Datasets {
+		switch dataset {
+		case DatasetCandles, DatasetTrades, DatasetFunding:
+			err = d.downloadWindows(ctx, dataset, instruments)
+		case DatasetVolatility:
+			err = d.downloadVolatility(ctx, d.currencies(instruments))
+		default:
+			err = fmt.Errorf("unsupported dataset: %s", dataset)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Downloader) instruments(ctx context.Context) ([]string, error) {
+	result := append([]string(nil), d.cfg.Instruments...)
+	for _, currency := range d.cfg.Currencies {
+		ins, err := d.client.GetInstruments(ctx, &models.GetInstrumentsParams{
+			Currency: currency,
+			Kind:     d.cfg.Kind,
+		})
+		if err != nil {
+			d.log.Errorw("Fail to get instruments", "currency", currency, "error", err)
+			return nil, err
+		}
+		for _, in := range ins {
+			result = append(result, in.InstrumentName)
+		}
+	}
+	return result, nil
+}
+
+func (d *Downloader) currencies(instruments []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	add := func(currency string) {
+		if currency != "" && !seen[currency] {
+			seen[currency] = true
+			result = append(result, currency)
+		}
+	}
+
+	for _, currency := range d.cfg.Currencies {
+		add(currency)
+	}
+	for _, instrument := range instruments {
+		add(strings.Split(instrument, "-")[0])
+	}
+	return result
+}
+
+func (d *Downloader) window(dataset Dataset) time.Duration {
+	if d.cfg.Window > 0 {
+		return d.cfg.Window
+	}
+	switch dataset {
+	case DatasetTrades:
+		return defaultTradesWindow
+	case DatasetFunding:
+		return fundingWindow
+	}
+	resolution, _ := parseResolution(d.cfg.Resolution)
+	return resolution * candlesPerWindow
+}
+
+func (d *Downloader) downloadWindows(ctx context.Context, dataset Dataset, instruments []string) error {
+	windows := splitRange(d.cfg.Start, d.cfg.End, d.window(dataset))
+	for _, instrument := range instruments {
+		for _, w := range windows {
+			path := d.outputPath(dataset, instrument, w[0], w[1])
+			if fileExists(path) {
+				d.log.Debugw("Skip downloaded window", "path", path)
+				continue
+			}
+
+			var (
+				records   []Record
+				prototype Record
+				err       error
+			)
+			switch dataset {
+			case DatasetCandles:
+				prototype = Candle{}
+				records, err = d.fetchCandles(ctx, instrument, w[0], w[1])
+			case DatasetTrades:
+				prototype = Trade{}
+				records, err = d.fetchTrades(ctx, instrument, w[0], w[1])
+			default:
+				prototype = Funding{}
+				records, err = d.fetchFunding(ctx, instrument, w[0], w[1])
+			}
+			if err != nil {
+				d.log.Errorw("Fail to fetch window", "dataset", dataset, "instrument", instrument, "error", err)
+				return err
+			}
+
+			if err := d.writeFile(path, prototype, records); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (d *Downloader) fetchCandles(ctx context.Context, instrument string, start, end int64) ([]Record, error) {
+	res, err := d.client.GetTradingviewChartData(ctx, &models.GetTradingviewChartDataParams{
+		InstrumentName: instrument,
+		StartTimestamp: uint64(start),
+		EndTimestamp:   uint64(end - 1),
+		Resolution:     d.cfg.Resolution,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(res.Ticks))
+	for i, tick := range res.Ticks {
+		records = append(records, Candle{
+			InstrumentName: instrument,
+			Timestamp:      int64(tick),
+			Open:           valueAt(res.Open, i),
+			High:           valueAt(res.High, i),
+			Low:            valueAt(res.Low, i),
+			Close:          valueAt(res.Close, i),
+			Volume:         valueAt(res.Volume, i),
+			Cost:           valueAt(res.Cost, i),
+		})
+	}
+	return records, nil
+}
+
+// fetchTrades pages through the trades of [start, end). The first page is looked up by time and
+// the following ones by trade sequence, so the trades sharing a millisecond are never skipped.
+// Trades are deduplicated by TradeID.
+func (d *Downloader) fetchTrades(ctx context.Context, instrument string, start, end int64) ([]Record, error) {
+	includeOld := true
+	res, err := d.client.GetLastTradesByInstrumentAndTime(ctx, &models.GetLastTradesByInstrumentAndTimeParams{
+		InstrumentName: instrument,
+		StartTimestamp: uint64(start),
+		EndTimestamp:   uint64(end - 1),
+		Count:          d.cfg.PageSize,
+		IncludeOld:     &includeOld,
+		Sorting:        "asc",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params := models.GetLastTradesByInstrumentParams{
+		InstrumentName: instrument,
+		Count:          d.cfg.PageSize,
+		IncludeOld:     &includeOld,
+		Sorting:        "asc",
+	}
+	seen := make(map[string]struct{})
+	var records []Record
+	for {
+		for _, trade := range res.Trades {
+			if int64(trade.Timestamp) >= end {
+				return records, nil
+			}
+			if _, ok := seen[trade.TradeID]; ok {
+				continue
+			}
+			seen[trade.TradeID] = struct{}{}
+			records = append(records, newTrade(trade))
+		}
+
+		if !res.HasMore || len(res.Trades) == 0 {
+			return records, nil
+		}
+		params.StartSeq = int64(res.Trades[len(res.Trades)-1].TradeSeq) + 1
+		if res, err = d.client.GetLastTradesByInstrument(ctx, &params); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (d *Downloader) fetchFunding(ctx context.Context, instrument string, start, end int64) ([]Record, error) {
+	res, err := d.client.GetFundingRateHistory(ctx, &models.GetFundingRateHistoryParams{
+		InstrumentName: instrument,
+		StartTimestamp: uint64(start),
+		EndTimestamp:   uint64(end - 1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(res))
+	for _, data := range res {
+		if inRange(int64(data.Timestamp), start, end) {
+			records = append(records, Funding{
+				InstrumentName: instrument,
+				Timestamp:      int64(data.Timestamp),
+				IndexPrice:     data.IndexPrice,
+				Interest8H:     data.Interest8H,
+			})
+		}
+	}
+	return records, nil
+}
+
+func (d *Downloader) downloadVolatility(ctx context.Context, currencies []string) error {
+	start, end := d.cfg.Start.UnixMilli(), d.cfg.End.UnixMilli()
+	for _, currency := range currencies {
+		path := d.outputPath(DatasetVolatility, currency, start, end)
+		if fileExists(path) {
+			continue
+		}
+
+		res, err := d.client.GetHistoricalVolatility(ctx, &models.GetHistoricalVolatilityParams{
+			Currency: currency,
+		})
+		if err != nil {
+			d.log.Errorw("Fail to get historical volatility", "currency", currency, "error", err)
+			return err
+		}
+
+		var records []Record
+		for _, v := range res {
+			if inRange(int64(v.Timestamp), start, end) {
+				records = append(records, Volatility{
+					Currency:  currency,
+					Timestamp: int64(v.Timestamp),
+					Value:     v.Value,
+				})
+			}
+		}
+
+		if err := d.writeFile(path, Volatility{}, records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *Downloader) outputPath(dataset Dataset, name string, start, end int64) string {
+	return filepath.Join(
+		d.cfg.OutputDir,
+		string(dataset),
+		name,
+		fmt.Sprintf("%d-%d.%s", start, end, d.cfg.Format),
+	)
+}
+
+// writeFile writes records to a temporary file and renames it to path once complete.
+func (d *Downloader) writeFile(path string, prototype Record, records []Record) (err error) {
+	if err = os.MkdirAll(filepath.Dir(path), outputDirPerm); err != nil {
+		return err
+	}
+
+	tmpPath := path + tmpFileSuffix
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, outputFilePerm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	w, err := NewWriter(d.cfg.Format, f, prototype)
+	if err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err = w.Write(record); err != nil {
+			return err
+		}
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	d.log.Infow("Wrote history file", "path", path, "records", len(records))
+	return os.Rename(tmpPath, path)
+}
+
+// parseResolution converts a TradingView resolution to a duration.
+func parseResolution(resolution string) (time.Duration, error) {
+	if resolution == "1D" {
+		return 24 * time.Hour, nil
+	}
+
+	minutes, err := strconv.Atoi(resolution)
+	if err != nil || minutes <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidResolution, resolution)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// splitRange splits [start, end) into consecutive windows in milliseconds.
+func splitRange(start, end time.Time, window time.Duration) [][2]int64 {
+	var windows [][2]int64
+	for s := start; s.Before(end); s = s.Add(window) {
+		e := s.Add(window)
+		if e.After(end) {
+			e = end
+		}
+		windows = append(windows, [2]int64{s.UnixMilli(), e.UnixMilli()})
+	}
+	return windows
+}
+
+func inRange(ts, start, end int64) bool {
+	return ts >= start && ts < end
+}
+
+func valueAt(values []float64, i int) float64 {
+	if i < len(values) {
+		return values[i]
+	}
+	return 0
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,318 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClient struct {
+	tradePages   []models.GetLastTradesResponse
+	tradeCalls   []models.GetLastTradesByInstrumentAndTimeParams
+	seqCalls     []models.GetLastTradesByInstrumentParams
+	candleCalls  []models.GetTradingviewChartDataParams
+	fundingRates models.GetFundingRateHistoryResponse
+	fundingCalls []models.GetFundingRateHistoryParams
+	volatility   models.GetHistoricalVolatilityResponse
+}
+
+func (c *mockClient) GetInstruments(
+	_ context.Context, params *models.GetInstrumentsParams,
+) ([]models.Instrument, error) {
+	return []models.Instrument{{InstrumentName: params.Currency + "-PERPETUAL"}}, nil
+}
+
+func (c *mockClient) GetTradingviewChartData(
+	_ context.Context, params *models.GetTradingviewChartDataParams,
+) (models.GetTradingviewChartDataResponse, error) {
+	c.candleCalls = append(c.candleCalls, *params)
+	return models.GetTradingviewChartDataResponse{
+		Ticks:  []uint64{params.StartTimestamp},
+		Open:   []float64{1},
+		High:   []float64{2},
+		Low:    []float64{0.5},
+		Close:  []float64{1.5},
+		Volume: []float64{10},
+		Cost:   []float64{15},
+		Status: "ok",
+	}, nil
+}
+
+func (c *mockClient) GetLastTradesByInstrumentAndTime(
+	_ context.Context, params *models.GetLastTradesByInstrumentAndTimeParams,
+) (models.GetLastTradesResponse, error) {
+	c.tradeCalls = append(c.tradeCalls, *params)
+	return c.nextTradePage(), nil
+}
+
+func (c *mockClient) GetLastTradesByInstrument(
+	_ context.Context, params *models.GetLastTradesByInstrumentParams,
+) (models.GetLastTradesResponse, error) {
+	c.seqCalls = append(c.seqCalls, *params)
+	return c.nextTradePage(), nil
+}
+
+func (c *mockClient) nextTradePage() models.GetLastTradesResponse {
+	if len(c.tradePages) == 0 {
+		return models.GetLastTradesResponse{}
+	}
+	page := c.tradePages[0]
+	c.tradePages = c.tradePages[1:]
+	return page
+}
+
+func (c *mockClient) GetFundingRateHistory(
+	_ context.Context, params *models.GetFundingRateHistoryParams,
+) (models.GetFundingRateHistoryResponse, error) {
+	c.fundingCalls = append(c.fundingCalls, *params)
+	var res models.GetFundingRateHistoryResponse
+	for _, rate := range c.fundingRates {
+		if rate.Timestamp >= params.StartTimestamp && rate.Timestamp <= params.EndTimestamp {
+			res = append(res, rate)
+		}
+	}
+	return res, nil
+}
+
+func (c *mockClient) GetHistoricalVolatility(
+	_ context.Context, _ *models.GetHistoricalVolatilityParams,
+) (models.GetHistoricalVolatilityResponse, error) {
+	return c.volatility, nil
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestNewDownloaderValidation(t *testing.T) {
+	_, err := NewDownloader(&mockClient{}, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Start:       time.UnixMilli(2000),
+		End:         time.UnixMilli(1000),
+	})
+	require.ErrorIs(t, err, ErrInvalidTimeRange)
+
+	_, err = NewDownloader(&mockClient{}, Config{
+		Start: time.UnixMilli(1000),
+		End:   time.UnixMilli(2000),
+	})
+	require.ErrorIs(t, err, ErrNoInstruments)
+
+	_, err = NewDownloader(&mockClient{}, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Start:       time.UnixMilli(1000),
+		End:         time.UnixMilli(2000),
+		Resolution:  "1W",
+	})
+	require.ErrorIs(t, err, ErrInvalidResolution)
+
+	_, err = NewDownloader(&mockClient{}, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Datasets:    []Dataset{DatasetVolatility},
+		Start:       time.Now().Add(-2 * volatilityLookback),
+		End:         time.Now(),
+	})
+	require.ErrorIs(t, err, ErrBeyondLookback)
+}
+
+func TestDownloadTradesDeduplicatesAndResumes(t *testing.T) {
+	dir := t.TempDir()
+	client := &mockClient{
+		tradePages: []models.GetLastTradesResponse{
+			{
+				Trades: []models.Trade{
+					{TradeID: "1", TradeSeq: 1, Timestamp: 1000, InstrumentName: "BTC-PERPETUAL", Price: 10},
+					{TradeID: "2", TradeSeq: 2, Timestamp: 1100, InstrumentName: "BTC-PERPETUAL", Price: 11},
+				},
+				HasMore: true,
+			},
+			{
+				Trades: []models.Trade{
+					{TradeID: "2", TradeSeq: 2, Timestamp: 1100, InstrumentName: "BTC-PERPETUAL", Price: 11},
+					{TradeID: "3", TradeSeq: 3, Timestamp: 1200, InstrumentName: "BTC-PERPETUAL", Price: 12},
+					{TradeID: "4", TradeSeq: 4, Timestamp: 2100, InstrumentName: "BTC-PERPETUAL", Price: 13},
+				},
+				HasMore: true,
+			},
+		},
+	}
+
+	d, err := NewDownloader(client, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Datasets:    []Dataset{DatasetTrades},
+		Start:       time.UnixMilli(1000),
+		End:         time.UnixMilli(3000),
+		Window:      time.Second,
+		PageSize:    2,
+		Format:      FormatCSV,
+		OutputDir:   dir,
+	})
+	require.NoError(t, err)
+	require.NoError(t, d.Run(context.Background()))
+
+	require.Len(t, client.tradeCalls, 2)
+	assert.Equal(t, uint64(1000), client.tradeCalls[0].StartTimestamp)
+	assert.Equal(t, uint64(1999), client.tradeCalls[0].EndTimestamp)
+	assert.Equal(t, uint64(2000), client.tradeCalls[1].StartTimestamp)
+	require.Len(t, client.seqCalls, 1)
+	assert.Equal(t, int64(3), client.seqCalls[0].StartSeq)
+
+	content := readFile(t, filepath.Join(dir, "trades", "BTC-PERPETUAL", "1000-2000.csv"))
+	assert.Equal(t,
+		"instrument_name,trade_id,trade_seq,timestamp,direction,price,amount,index_price,mark_price,iv,tick_direction,liquidation,block_trade_id\n"+
+			"BTC-PERPETUAL,1,1,1000,,10,0,0,0,0,0,,\n"+
+			"BTC-PERPETUAL,2,2,1100,,11,0,0,0,0,0,,\n"+
+			"BTC-PERPETUAL,3,3,1200,,12,0,0,0,0,0,,\n",
+		content,
+	)
+	assert.FileExists(t, filepath.Join(dir, "trades", "BTC-PERPETUAL", "2000-3000.csv"))
+
+	// A second run finds every window on disk and fetches nothing.
+	require.NoError(t, d.Run(context.Background()))
+	assert.Len(t, client.tradeCalls, 2)
+}
+
+func TestDownloadTradesSharingTimestamp(t *testing.T) {
+	client := &mockClient{
+		tradePages: []models.GetLastTradesResponse{
+			{
+				Trades: []models.Trade{
+					{TradeID: "1", TradeSeq: 1, Timestamp: 1000, InstrumentName: "BTC-PERPETUAL"},
+					{TradeID: "2", TradeSeq: 2, Timestamp: 1000, InstrumentName: "BTC-PERPETUAL"},
+				},
+				HasMore: true,
+			},
+			{
+				Trades: []models.Trade{
+					{TradeID: "3", TradeSeq: 3, Timestamp: 1000, InstrumentName: "BTC-PERPETUAL"},
+					{TradeID: "4", TradeSeq: 4, Timestamp: 1500, InstrumentName: "BTC-PERPETUAL"},
+				},
+				HasMore: false,
+			},
+		},
+	}
+
+	d, err := NewDownloader(client, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Start:       time.UnixMilli(1000),
+		End:         time.UnixMilli(2000),
+		PageSize:    2,
+	})
+	require.NoError(t, err)
+	records, err := d.fetchTrades(context.Background(), "BTC-PERPETUAL", 1000, 2000)
+	require.NoError(t, err)
+
+	require.Len(t, client.tradeCalls, 1)
+	require.Len(t, client.seqCalls, 1)
+	assert.Equal(t, int64(3), client.seqCalls[0].StartSeq)
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.(Trade).TradeID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4"}, ids)
+}
+
+func TestDownloadCandlesFromCurrencies(t *testing.T) {
+	dir := t.TempDir()
+	client := &mockClient{}
+
+	d, err := NewDownloader(client, Config{
+		Currencies: []string{"ETH"},
+		Datasets:   []Dataset{DatasetCandles},
+		Start:      time.UnixMilli(0),
+		End:        time.UnixMilli(90 * 60 * 1000),
+		Resolution: "1",
+		Window:     time.Hour,
+		Format:     FormatJSONL,
+		OutputDir:  dir,
+	})
+	require.NoError(t, err)
+	require.NoError(t, d.Run(context.Background()))
+
+	require.Len(t, client.candleCalls, 2)
+	assert.Equal(t, "ETH-PERPETUAL", client.candleCalls[0].InstrumentName)
+	assert.Equal(t, "1", client.candleCalls[0].Resolution)
+
+	content := readFile(t, filepath.Join(dir, "candles", "ETH-PERPETUAL", "3600000-5400000.jsonl"))
+	assert.Equal(t,
+		`{"instrument_name":"ETH-PERPETUAL","timestamp":3600000,"open":1,"high":2,"low":0.5,"close":1.5,"volume":10,"cost":15}`+"\n",
+		content,
+	)
+}
+
+func TestDownloadFunding(t *testing.T) {
+	dir := t.TempDir()
+	client := &mockClient{fundingRates: models.GetFundingRateHistoryResponse{
+		{Timestamp: 500, IndexPrice: 1, Interest8H: 0.1},
+		{Timestamp: 1500, IndexPrice: 2, Interest8H: 0.2},
+		{Timestamp: 4000, IndexPrice: 3, Interest8H: 0.3},
+	}}
+
+	d, err := NewDownloader(client, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Datasets:    []Dataset{DatasetFunding},
+		Start:       time.UnixMilli(1000),
+		End:         time.UnixMilli(3000),
+		Window:      time.Second,
+		Format:      FormatCSV,
+		OutputDir:   dir,
+	})
+	require.NoError(t, err)
+	require.NoError(t, d.Run(context.Background()))
+
+	require.Len(t, client.fundingCalls, 2)
+	assert.Equal(t, uint64(2000), client.fundingCalls[1].StartTimestamp)
+	assert.Equal(t, uint64(2999), client.fundingCalls[1].EndTimestamp)
+	assert.Equal(t,
+		"instrument_name,timestamp,index_price,interest_8h\nBTC-PERPETUAL,1500,2,0.2\n",
+		readFile(t, filepath.Join(dir, "funding", "BTC-PERPETUAL", "1000-2000.csv")),
+	)
+
+	// The empty window is written too, the next run fetches nothing.
+	assert.Empty(t, readFile(t, filepath.Join(dir, "funding", "BTC-PERPETUAL", "2000-3000.csv")))
+	require.NoError(t, d.Run(context.Background()))
+	assert.Len(t, client.fundingCalls, 2)
+}
+
+func TestDownloadVolatility(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour).Truncate(time.Hour)
+	end := start.Add(time.Hour)
+	client := &mockClient{volatility: models.GetHistoricalVolatilityResponse{
+		{Timestamp: uint64(start.UnixMilli()), Value: 55.5},
+		{Timestamp: uint64(end.UnixMilli()), Value: 56},
+	}}
+
+	d, err := NewDownloader(client, Config{
+		Instruments: []string{"BTC-PERPETUAL"},
+		Datasets:    []Dataset{DatasetVolatility},
+		Start:       start,
+		End:         end,
+		Format:      FormatCSV,
+		OutputDir:   dir,
+	})
+	require.NoError(t, err)
+	path := filepath.Join(dir, "volatility", "BTC", fmt.Sprintf("%d-%d.csv", start.UnixMilli(), end.UnixMilli()))
+
+	require.NoError(t, d.Run(context.Background()))
+	assert.Equal(t,
+		fmt.Sprintf("currency,timestamp,value\nBTC,%d,55.5\n", start.UnixMilli()),
+		readFile(t, path),
+	)
+}
+
+func TestSplitRange(t *testing.T) {
+	windows := splitRange(time.UnixMilli(0), time.UnixMilli(2500), time.Second)
+	assert.Equal(t, [][2]int64{{0, 1000}, {1000, 2000}, {2000, 2500}}, windows)
+}
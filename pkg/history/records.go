@@ -0,0 +1,148 @@
+package history
+
+import (
+	"strconv"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+// Record is a row of a dataset.
+type Record interface {
+	// Header returns the CSV column names.
+	Header() []string
+	// Row returns the CSV values in the order of Header.
+	Row() []string
+}
+
+// Candle is an OHLCV bar returned by public/get_tradingview_chart_data.
+type Candle struct {
+	InstrumentName string  `json:"instrument_name" parquet:"name=instrument_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Timestamp      int64   `json:"timestamp" parquet:"name=timestamp, type=INT64"`
+	Open           float64 `json:"open" parquet:"name=open, type=DOUBLE"`
+	High           float64 `json:"high" parquet:"name=high, type=DOUBLE"`
+	Low            float64 `json:"low" parquet:"name=low, type=DOUBLE"`
+	Close          float64 `json:"close" parquet:"name=close, type=DOUBLE"`
+	Volume         float64 `json:"volume" parquet:"name=volume, type=DOUBLE"`
+	Cost           float64 `json:"cost" parquet:"name=cost, type=DOUBLE"`
+}
+
+func (Candle) Header() []string {
+	return []string{"instrument_name", "timestamp", "open", "high", "low", "close", "volume", "cost"}
+}
+
+func (c Candle) Row() []string {
+	return []string{
+		c.InstrumentName,
+		strconv.FormatInt(c.Timestamp, 10),
+		formatFloat(c.Open),
+		formatFloat(c.High),
+		formatFloat(c.Low),
+		formatFloat(c.Close),
+		formatFloat(c.Volume),
+		formatFloat(c.Cost),
+	}
+}
+
+// Trade is a public trade returned by public/get_last_trades_by_instrument_and_time.
+type Trade struct {
+	InstrumentName string  `json:"instrument_name" parquet:"name=instrument_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TradeID        string  `json:"trade_id" parquet:"name=trade_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TradeSeq       int64   `json:"trade_seq" parquet:"name=trade_seq, type=INT64"`
+	Timestamp      int64   `json:"timestamp" parquet:"name=timestamp, type=INT64"`
+	Direction      string  `json:"direction" parquet:"name=direction, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Price          float64 `json:"price" parquet:"name=price, type=DOUBLE"`
+	Amount         float64 `json:"amount" parquet:"name=amount, type=DOUBLE"`
+	IndexPrice     float64 `json:"index_price" parquet:"name=index_price, type=DOUBLE"`
+	MarkPrice      float64 `json:"mark_price" parquet:"name=mark_price, type=DOUBLE"`
+	IV             float64 `json:"iv" parquet:"name=iv, type=DOUBLE"`
+	TickDirection  int32   `json:"tick_direction" parquet:"name=tick_direction, type=INT32"`
+	Liquidation    string  `json:"liquidation" parquet:"name=liquidation, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BlockTradeID   string  `json:"block_trade_id" parquet:"name=block_trade_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+}
+
+func newTrade(t models.Trade) Trade {
+	return Trade{
+		InstrumentName: t.InstrumentName,
+		TradeID:        t.TradeID,
+		TradeSeq:       int64(t.TradeSeq),
+		Timestamp:      int64(t.Timestamp),
+		Direction:      t.Direction,
+		Price:          t.Price,
+		Amount:         t.Amount,
+		IndexPrice:     t.IndexPrice,
+		MarkPrice:      t.MarkPrice,
+		IV:             t.IV,
+		TickDirection:  int32(t.TickDirection),
+		Liquidation:    t.Liquidation,
+		BlockTradeID:   t.BlockTradeID,
+	}
+}
+
+func (Trade) Header() []string {
+	return []string{
+		"instrument_name", "trade_id", "trade_seq", "timestamp", "direction", "price", "amount",
+		"index_price", "mark_price", "iv", "tick_direction", "liquidation", "block_trade_id",
+	}
+}
+
+func (t Trade) Row() []string {
+	return []string{
+		t.InstrumentName,
+		t.TradeID,
+		strconv.FormatInt(t.TradeSeq, 10),
+		strconv.FormatInt(t.Timestamp, 10),
+		t.Direction,
+		formatFloat(t.Price),
+		formatFloat(t.Amount),
+		formatFloat(t.IndexPrice),
+		formatFloat(t.MarkPrice),
+		formatFloat(t.IV),
+		strconv.FormatInt(int64(t.TickDirection), 10),
+		t.Liquidation,
+		t.BlockTradeID,
+	}
+}
+
+// Funding is a funding rate sample returned by public/get_funding_rate_history.
+type Funding struct {
+	InstrumentName string  `json:"instrument_name" parquet:"name=instrument_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Timestamp      int64   `json:"timestamp" parquet:"name=timestamp, type=INT64"`
+	IndexPrice     float64 `json:"index_price" parquet:"name=index_price, type=DOUBLE"`
+	Interest8H     float64 `json:"interest_8h" parquet:"name=interest_8h, type=DOUBLE"`
+}
+
+func (Funding) Header() []string {
+	return []string{"instrument_name", "timestamp", "index_price", "interest_8h"}
+}
+
+func (f Funding) Row() []string {
+	return []string{
+		f.InstrumentName,
+		strconv.FormatInt(f.Timestamp, 10),
+		formatFloat(f.IndexPrice),
+		formatFloat(f.Interest8H),
+	}
+}
+
+// Volatility is a historical volatility sample returned by public/get_historical_volatility.
+type Volatility struct {
+	Currency  string  `json:"currency" parquet:"name=currency, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Timestamp int64   `json:"timestamp" parquet:"name=timestamp, type=INT64"`
+	Value     float64 `json:"value" parquet:"name=value, type=DOUBLE"`
+}
+
+func (Volatility) Header() []string {
+	return []string{"currency", "timestamp", "value"}
+}
+
+func (v Volatility) Row() []string {
+	return []string{
+		v.Currency,
+		strconv.FormatInt(v.Timestamp, 10),
+		formatFloat(v.Value),
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
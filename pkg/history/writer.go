@@ -0,0 +1,99 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// Format is the output file format.
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatJSONL   Format = "jsonl"
+	FormatParquet Format = "parquet"
+
+	parquetParallelism = 1
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// Writer writes records of a single type.
+type Writer interface {
+	Write(record Record) error
+	// Close flushes buffered data. It does not close the underlying io.Writer.
+	Close() error
+}
+
+// NewWriter returns a Writer encoding records of the same type as prototype in format.
+func NewWriter(format Format, w io.Writer, prototype Record) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case FormatParquet:
+		// The parquet schema is derived from a pointer to the record struct.
+		schema := prototype
+		if t := reflect.TypeOf(prototype); t.Kind() != reflect.Ptr {
+			schema = reflect.New(t).Interface().(Record)
+		}
+		pw, err := writer.NewParquetWriterFromWriter(w, schema, parquetParallelism)
+		if err != nil {
+			return nil, err
+		}
+		return &parquetWriter{w: pw}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+type csvWriter struct {
+	w             *csv.Writer
+	headerWritten bool
+}
+
+func (w *csvWriter) Write(record Record) error {
+	if !w.headerWritten {
+		if err := w.w.Write(record.Header()); err != nil {
+			return err
+		}
+		w.headerWritten = true
+	}
+	return w.w.Write(record.Row())
+}
+
+func (w *csvWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonlWriter) Write(record Record) error {
+	return w.enc.Encode(record)
+}
+
+func (w *jsonlWriter) Close() error {
+	return nil
+}
+
+type parquetWriter struct {
+	w *writer.ParquetWriter
+}
+
+func (w *parquetWriter) Write(record Record) error {
+	return w.w.Write(record)
+}
+
+func (w *parquetWriter) Close() error {
+	return w.w.WriteStop()
+}
@@ -0,0 +1,51 @@
+package history
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriterFormats(t *testing.T) {
+	record := Volatility{Currency: "BTC", Timestamp: 1, Value: 2.5}
+
+	tests := []struct {
+		format Format
+		check  func(t *testing.T, data []byte)
+	}{
+		{
+			FormatCSV,
+			func(t *testing.T, data []byte) {
+				assert.Equal(t, "currency,timestamp,value\nBTC,1,2.5\n", string(data))
+			},
+		},
+		{
+			FormatJSONL,
+			func(t *testing.T, data []byte) {
+				assert.Equal(t, `{"currency":"BTC","timestamp":1,"value":2.5}`+"\n", string(data))
+			},
+		},
+		{
+			FormatParquet,
+			func(t *testing.T, data []byte) {
+				require.Greater(t, len(data), 8)
+				assert.Equal(t, "PAR1", string(data[:4]))
+				assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+			},
+		},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		w, err := NewWriter(test.format, &buf, Volatility{})
+		require.NoError(t, err)
+		require.NoError(t, w.Write(record))
+		require.NoError(t, w.Close())
+		test.check(t, buf.Bytes())
+	}
+
+	_, err := NewWriter("xml", &bytes.Buffer{}, Volatility{})
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,367 @@
+package instruments
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTimeout       = 10 * time.Second
+	defaultRetryInterval = 5 * time.Second
+	defaultMaxAttempts   = 3
+	defaultMaxPending    = 1000
+)
+
+// Getter fetches instruments, e.g. websocket.Client, rest.Client or fix.Client.
+type Getter interface {
+	GetInstruments(ctx context.Context, params *models.GetInstrumentsParams) ([]models.Instrument, error)
+}
+
+type Config struct {
+	// Currencies whose instruments are loaded, they are fetched again to resolve unknown IDs.
+	Currencies []string
+	// Timeout of fetching the instruments of all the currencies. Defaults to 10 seconds.
+	Timeout time.Duration
+	// RetryInterval is the minimum time between two fetches of unknown IDs. Defaults to 5 seconds.
+	RetryInterval time.Duration
+	// MaxAttempts is the number of fetches after which an unknown ID is given up. Defaults to 3.
+	MaxAttempts int
+	// MaxPending is the number of callbacks queued for an unknown ID, the oldest are dropped
+	// beyond it. Defaults to 1000.
+	MaxPending int
+}
+
+// Filter selects instruments, a zero field matches any instrument.
+type Filter struct {
+	Currency   string // base currency
+	Kind       string
+	Expiration uint64 // expiration timestamp in milliseconds
+}
+
+func (f Filter) match(ins *models.Instrument) bool {
+	return (f.Currency == "" || f.Currency == ins.BaseCurrency) &&
+		(f.Kind == "" || f.Kind == ins.Kind) &&
+		(f.Expiration == 0 || f.Expiration == ins.ExpirationTimestamp)
+}
+
+// ResolveFunc receives the instrument of a resolved ID, ok is false if it was given up.
+type ResolveFunc func(ins models.Instrument, ok bool)
+
+type pending struct {
+	fns      []ResolveFunc
+	attempts int
+	draining bool
+	dropped  int
+}
+
+// Registry is a concurrent set of instruments indexed by ID and name.
+//
+// It is loaded with Load and kept up to date with Set, e.g. from the instrument events of a feed.
+// Resolve defers the handling of an event referring to an instrument the registry does not know yet,
+// such as a newly listed option, until the instruments are fetched again.
+type Registry struct {
+	log           *zap.SugaredLogger
+	getter        Getter
+	currencies    []string
+	timeout       time.Duration
+	retryInterval time.Duration
+	maxAttempts   int
+	maxPending    int
+
+	mu        sync.RWMutex
+	byID      map[uint32]models.Instrument
+	byName    map[string]uint32
+	pending   map[uint32]*pending
+	fetching  bool
+	lastFetch time.Time
+}
+
+// New creates a new Registry instance.
+func New(getter Getter, cfg Config) *Registry {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = defaultRetryInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultMaxAttempts
+	}
+	if cfg.MaxPending <= 0 {
+		cfg.MaxPending = defaultMaxPending
+	}
+
+	return &Registry{
+		log:           zap.S(),
+		getter:        getter,
+		currencies:    cfg.Currencies,
+		timeout:       cfg.Timeout,
+		retryInterval: cfg.RetryInterval,
+		maxAttempts:   cfg.MaxAttempts,
+		maxPending:    cfg.MaxPending,
+		byID:          make(map[uint32]models.Instrument),
+		byName:        make(map[string]uint32),
+		pending:       make(map[uint32]*pending),
+	}
+}
+
+// Load fetches the instruments of all the currencies and adds them to the registry.
+func (r *Registry) Load(ctx context.Context) error {
+	instruments, err := r.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	for _, ins := range instruments {
+		r.set(ins)
+	}
+	r.lastFetch = time.Now()
+	r.mu.Unlock()
+
+	return nil
+}
+
+func (r *Registry) fetch(ctx context.Context) ([]models.Instrument, error) {
+	result := make([]models.Instrument, 0)
+	for _, currency := range r.currencies {
+		ins, err := r.getter.GetInstruments(ctx, &models.GetInstrumentsParams{
+			Currency: currency,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ins...)
+	}
+	return result, nil
+}
+
+// Set adds or updates an instrument. The callbacks waiting for its ID are called before Set returns.
+func (r *Registry) Set(ins models.Instrument) {
+	r.mu.Lock()
+	r.set(ins)
+	p, ok := r.pending[ins.InstrumentID]
+	drain := ok && !p.draining
+	if drain {
+		p.draining = true
+	}
+	r.mu.Unlock()
+
+	if drain {
+		r.drain(ins.InstrumentID)
+	}
+}
+
+func (r *Registry) set(ins models.Instrument) {
+	if old, ok := r.byID[ins.InstrumentID]; ok && old.InstrumentName != ins.InstrumentName {
+		delete(r.byName, old.InstrumentName)
+	}
+	r.byID[ins.InstrumentID] = ins
+	r.byName[ins.InstrumentName] = ins.InstrumentID
+}
+
+// Get returns the instrument with the given ID.
+func (r *Registry) Get(id uint32) (models.Instrument, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ins, ok := r.byID[id]
+	return ins, ok
+}
+
+// GetByName returns the instrument with the given name.
+func (r *Registry) GetByName(name string) (models.Instrument, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, ok := r.byName[name]
+	if !ok {
+		return models.Instrument{}, false
+	}
+	return r.byID[id], true
+}
+
+// Len returns the number of instruments.
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.byID)
+}
+
+// Find returns the instruments matching the filter sorted by name.
+func (r *Registry) Find(filter Filter) []models.Instrument {
+	r.mu.RLock()
+	result := make([]models.Instrument, 0)
+	for _, ins := range r.byID {
+		ins := ins
+		if filter.match(&ins) {
+			result = append(result, ins)
+		}
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].InstrumentName < result[j].InstrumentName
+	})
+	return result
+}
+
+// Expirations returns the sorted expiration timestamps of the instruments of a currency and kind,
+// e.g. the expiries of the BTC options.
+func (r *Registry) Expirations(currency, kind string) []uint64 {
+	filter := Filter{Currency: currency, Kind: kind}
+	seen := make(map[uint64]struct{})
+	result := make([]uint64, 0)
+
+	r.mu.RLock()
+	for _, ins := range r.byID {
+		ins := ins
+		if _, ok := seen[ins.ExpirationTimestamp]; ok || !filter.match(&ins) {
+			continue
+		}
+		seen[ins.ExpirationTimestamp] = struct{}{}
+		result = append(result, ins.ExpirationTimestamp)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// Resolve calls fn with the instrument of id. It is called right away if the instrument is known,
+// otherwise fn is queued and the instruments are fetched in the background. The callbacks of an ID
+// are called in the order of the Resolve calls, once the instrument is fetched or Set, or with
+// ok=false once it is given up.
+func (r *Registry) Resolve(id uint32, fn ResolveFunc) {
+	r.mu.RLock()
+	ins, known := r.byID[id]
+	_, waiting := r.pending[id]
+	r.mu.RUnlock()
+	if known && !waiting {
+		fn(ins, true)
+		return
+	}
+
+	r.mu.Lock()
+	if p, ok := r.pending[id]; ok {
+		r.enqueue(id, p, fn)
+		r.mu.Unlock()
+		return
+	}
+	if ins, ok := r.byID[id]; ok {
+		r.mu.Unlock()
+		fn(ins, true)
+		return
+	}
+
+	r.pending[id] = &pending{fns: []ResolveFunc{fn}}
+	start := !r.fetching
+	r.fetching = true
+	r.mu.Unlock()
+
+	if start {
+		go r.fetchPending()
+	}
+}
+
+// Pending returns the number of unknown IDs being resolved.
+func (r *Registry) Pending() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.pending)
+}
+
+func (r *Registry) enqueue(id uint32, p *pending, fn ResolveFunc) {
+	if len(p.fns) >= r.maxPending {
+		if p.dropped == 0 {
+			r.log.Warnw("Too many events waiting for an unknown instrument, dropping the oldest",
+				"instrument_id", id, "max_pending", r.maxPending)
+		}
+		p.dropped++
+		p.fns = append(p.fns[:0], p.fns[1:]...)
+	}
+	p.fns = append(p.fns, fn)
+}
+
+// fetchPending fetches the instruments until every pending ID is resolved or given up.
+func (r *Registry) fetchPending() {
+	for {
+		r.mu.RLock()
+		wait := time.Until(r.lastFetch.Add(r.retryInterval))
+		r.mu.RUnlock()
+		if wait > 0 {
+			time.Sleep(wait)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+		instruments, err := r.fetch(ctx)
+		cancel()
+		if err != nil {
+			r.log.Warnw("Fail to fetch instruments of unknown IDs", "error", err)
+		}
+
+		r.mu.Lock()
+		r.lastFetch = time.Now()
+		for _, ins := range instruments {
+			r.set(ins)
+		}
+
+		ready := make([]uint32, 0, len(r.pending))
+		remaining := 0
+		for id, p := range r.pending {
+			if p.draining {
+				continue
+			}
+			p.attempts++
+			_, known := r.byID[id]
+			if !known && p.attempts < r.maxAttempts {
+				remaining++
+				continue
+			}
+			if !known {
+				r.log.Warnw("Give up unknown instrument", "instrument_id", id, "attempts", p.attempts)
+			}
+			p.draining = true
+			ready = append(ready, id)
+		}
+		if remaining == 0 {
+			r.fetching = false
+		}
+		r.mu.Unlock()
+
+		for _, id := range ready {
+			r.drain(id)
+		}
+		if remaining == 0 {
+			return
+		}
+	}
+}
+
+// drain calls the callbacks of id, including those queued meanwhile, then forgets id.
+func (r *Registry) drain(id uint32) {
+	for {
+		r.mu.Lock()
+		p := r.pending[id]
+		if len(p.fns) == 0 {
+			delete(r.pending, id)
+			r.mu.Unlock()
+			return
+		}
+		fns := p.fns
+		p.fns = nil
+		ins, ok := r.byID[id]
+		r.mu.Unlock()
+
+		for _, fn := range fns {
+			fn(ins, ok)
+		}
+	}
+}
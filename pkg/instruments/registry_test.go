@@ -0,0 +1,296 @@
+package instruments
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInvalidCurrency = errors.New("invalid currency")
+
+// fakeGetter serves the instruments of each currency, more can be listed while it is used.
+type fakeGetter struct {
+	mu          sync.Mutex
+	instruments map[string][]models.Instrument
+	calls       int
+}
+
+func (g *fakeGetter) GetInstruments(
+	_ context.Context, params *models.GetInstrumentsParams,
+) ([]models.Instrument, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.calls++
+	ins, ok := g.instruments[params.Currency]
+	if !ok {
+		return nil, errInvalidCurrency
+	}
+	return append([]models.Instrument(nil), ins...), nil
+}
+
+func (g *fakeGetter) list(ins models.Instrument) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.instruments[ins.BaseCurrency] = append(g.instruments[ins.BaseCurrency], ins)
+}
+
+func (g *fakeGetter) numCalls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.calls
+}
+
+var (
+	btcPerpetual = models.Instrument{
+		InstrumentID: 210838, InstrumentName: "BTC-PERPETUAL", BaseCurrency: "BTC", Kind: "future",
+		ExpirationTimestamp: 32503708800000,
+	}
+	btcFuture = models.Instrument{
+		InstrumentID: 211267, InstrumentName: "BTC-30DEC22", BaseCurrency: "BTC", Kind: "future",
+		ExpirationTimestamp: 1672387200000,
+	}
+	btcCall = models.Instrument{
+		InstrumentID: 213012, InstrumentName: "BTC-30DEC22-20000-C", BaseCurrency: "BTC", Kind: "option",
+		ExpirationTimestamp: 1672387200000, OptionType: "call", Strike: 20000,
+	}
+	btcPut = models.Instrument{
+		InstrumentID: 213013, InstrumentName: "BTC-6JAN23-20000-P", BaseCurrency: "BTC", Kind: "option",
+		ExpirationTimestamp: 1672992000000, OptionType: "put", Strike: 20000,
+	}
+	ethPerpetual = models.Instrument{
+		InstrumentID: 210760, InstrumentName: "ETH-PERPETUAL", BaseCurrency: "ETH", Kind: "future",
+		ExpirationTimestamp: 32503708800000,
+	}
+)
+
+func newTestRegistry(t *testing.T) (*Registry, *fakeGetter) {
+	t.Helper()
+
+	g := &fakeGetter{instruments: map[string][]models.Instrument{
+		"BTC": {btcPerpetual, btcFuture, btcCall},
+		"ETH": {ethPerpetual},
+	}}
+	r := New(g, Config{
+		Currencies:    []string{"BTC", "ETH"},
+		RetryInterval: time.Millisecond,
+		MaxAttempts:   2,
+	})
+	require.NoError(t, r.Load(context.Background()))
+	return r, g
+}
+
+// resolved records the callbacks of Resolve.
+type resolved struct {
+	mu    sync.Mutex
+	calls []string
+	done  chan struct{}
+}
+
+func newResolved() *resolved {
+	return &resolved{done: make(chan struct{}, 100)}
+}
+
+func (r *resolved) fn(tag string) ResolveFunc {
+	return func(ins models.Instrument, ok bool) {
+		r.mu.Lock()
+		if !ok {
+			tag += ":unknown"
+		} else {
+			tag += ":" + ins.InstrumentName
+		}
+		r.calls = append(r.calls, tag)
+		r.mu.Unlock()
+		r.done <- struct{}{}
+	}
+}
+
+func (r *resolved) wait(t *testing.T, n int) []string {
+	t.Helper()
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-r.done:
+		case <-time.After(time.Second):
+			t.Fatalf("%d callbacks out of %d", i, n)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+func TestLoad(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	assert.Equal(t, 4, r.Len())
+
+	ins, ok := r.Get(btcCall.InstrumentID)
+	assert.True(t, ok)
+	assert.Equal(t, btcCall, ins)
+
+	ins, ok = r.GetByName("ETH-PERPETUAL")
+	assert.True(t, ok)
+	assert.Equal(t, ethPerpetual, ins)
+
+	_, ok = r.Get(1)
+	assert.False(t, ok)
+	_, ok = r.GetByName("BTC-6JAN23-20000-P")
+	assert.False(t, ok)
+
+	failing := New(&fakeGetter{}, Config{Currencies: []string{"SHIB"}})
+	assert.ErrorIs(t, failing.Load(context.Background()), errInvalidCurrency)
+	assert.Zero(t, failing.Len())
+}
+
+func TestFind(t *testing.T) {
+	r, _ := newTestRegistry(t)
+	r.Set(btcPut)
+
+	assert.Equal(t, []models.Instrument{btcFuture, btcCall, btcPut, btcPerpetual, ethPerpetual}, r.Find(Filter{}))
+	assert.Equal(t, []models.Instrument{btcCall, btcPut}, r.Find(Filter{Currency: "BTC", Kind: "option"}))
+	assert.Equal(t, []models.Instrument{btcFuture, btcCall}, r.Find(Filter{Expiration: 1672387200000}))
+	assert.Empty(t, r.Find(Filter{Currency: "SOL"}))
+
+	assert.Equal(t, []uint64{1672387200000, 1672992000000}, r.Expirations("BTC", "option"))
+	assert.Equal(t, []uint64{1672387200000, 32503708800000}, r.Expirations("BTC", "future"))
+	assert.Empty(t, r.Expirations("ETH", "option"))
+}
+
+func TestSetRename(t *testing.T) {
+	r, _ := newTestRegistry(t)
+
+	renamed := btcFuture
+	renamed.InstrumentName = "BTC-30DEC22-RENAMED"
+	r.Set(renamed)
+
+	_, ok := r.GetByName("BTC-30DEC22")
+	assert.False(t, ok)
+	ins, ok := r.GetByName("BTC-30DEC22-RENAMED")
+	assert.True(t, ok)
+	assert.Equal(t, renamed, ins)
+	assert.Equal(t, 4, r.Len())
+}
+
+func TestResolveKnown(t *testing.T) {
+	r, g := newTestRegistry(t)
+	calls := g.numCalls()
+
+	res := newResolved()
+	r.Resolve(btcPerpetual.InstrumentID, res.fn("1"))
+	// A known ID is resolved before Resolve returns.
+	assert.Len(t, res.done, 1)
+	assert.Equal(t, []string{"1:BTC-PERPETUAL"}, res.wait(t, 1))
+	assert.Equal(t, calls, g.numCalls())
+	assert.Zero(t, r.Pending())
+}
+
+func TestResolveFetched(t *testing.T) {
+	r, g := newTestRegistry(t)
+	g.list(btcPut)
+
+	res := newResolved()
+	r.Resolve(btcPut.InstrumentID, res.fn("1"))
+	r.Resolve(btcPut.InstrumentID, res.fn("2"))
+	r.Resolve(btcPerpetual.InstrumentID, res.fn("3"))
+	r.Resolve(btcPut.InstrumentID, res.fn("4"))
+
+	var puts []string
+	for _, call := range res.wait(t, 4) {
+		if call != "3:BTC-PERPETUAL" {
+			puts = append(puts, call)
+		}
+	}
+	assert.Equal(t, []string{"1:BTC-6JAN23-20000-P", "2:BTC-6JAN23-20000-P", "4:BTC-6JAN23-20000-P"}, puts)
+
+	ins, ok := r.Get(btcPut.InstrumentID)
+	assert.True(t, ok)
+	assert.Equal(t, btcPut, ins)
+	assert.Eventually(t, func() bool { return r.Pending() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestResolveSet(t *testing.T) {
+	g := &fakeGetter{instruments: map[string][]models.Instrument{"BTC": {}}}
+	r := New(g, Config{Currencies: []string{"BTC"}, RetryInterval: time.Hour})
+
+	res := newResolved()
+	r.Resolve(btcPut.InstrumentID, res.fn("1"))
+	r.Resolve(btcPut.InstrumentID, res.fn("2"))
+	assert.Equal(t, 1, r.Pending())
+
+	// An instrument event resolves the waiting callbacks without a fetch.
+	r.Set(btcPut)
+	assert.Equal(t, []string{"1:BTC-6JAN23-20000-P", "2:BTC-6JAN23-20000-P"}, res.wait(t, 2))
+	assert.Zero(t, r.Pending())
+
+	r.Resolve(btcPut.InstrumentID, res.fn("3"))
+	assert.Equal(t, "3:BTC-6JAN23-20000-P", res.wait(t, 1)[2])
+}
+
+func TestResolveGiveUp(t *testing.T) {
+	r, g := newTestRegistry(t)
+	calls := g.numCalls()
+
+	res := newResolved()
+	r.Resolve(1, res.fn("1"))
+	r.Resolve(1, res.fn("2"))
+
+	assert.Equal(t, []string{"1:unknown", "2:unknown"}, res.wait(t, 2))
+	// Two attempts, each of them fetches both currencies.
+	assert.Equal(t, calls+4, g.numCalls())
+	assert.Eventually(t, func() bool { return r.Pending() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestResolveMaxPending(t *testing.T) {
+	g := &fakeGetter{instruments: map[string][]models.Instrument{"BTC": {}}}
+	r := New(g, Config{Currencies: []string{"BTC"}, RetryInterval: time.Hour, MaxPending: 2})
+
+	res := newResolved()
+	r.Resolve(btcPut.InstrumentID, res.fn("1"))
+	r.Resolve(btcPut.InstrumentID, res.fn("2"))
+	r.Resolve(btcPut.InstrumentID, res.fn("3"))
+
+	r.Set(btcPut)
+	assert.Equal(t, []string{"2:BTC-6JAN23-20000-P", "3:BTC-6JAN23-20000-P"}, res.wait(t, 2))
+}
+
+func TestResolveConcurrent(t *testing.T) {
+	r, g := newTestRegistry(t)
+	g.list(btcPut)
+
+	// Callbacks queued while the fetched ID is drained keep their order.
+	const n = 200
+	var mu sync.Mutex
+	var got []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < n; i++ {
+			i := i
+			r.Resolve(btcPut.InstrumentID, func(ins models.Instrument, ok bool) {
+				assert.True(t, ok)
+				mu.Lock()
+				got = append(got, i)
+				mu.Unlock()
+			})
+		}
+	}()
+	<-done
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(got) == n
+	}, time.Second, time.Millisecond)
+	for i := range got {
+		assert.Equal(t, i, got[i])
+	}
+}
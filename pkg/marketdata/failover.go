@@ -0,0 +1,342 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"go.uber.org/zap"
+)
+
+// SourceChannel receives the new Source of a Failover when it switches.
+const SourceChannel = "source"
+
+// stream is the state of the events of an instrument seen by the subscribers of a Failover.
+type stream struct {
+	mu sync.Mutex
+	// changeID is the last book change emitted, primaryChangeID the last one received from
+	// the primary feed, both are loaded atomically by the other instruments.
+	changeID        int64
+	primaryChangeID int64
+	tradeSeq        uint64
+	tickerTimestamp uint64
+	// backfilling is set while the trades missed by the switch are fetched, the trades received
+	// meanwhile are pending.
+	backfilling bool
+	pending     []*Trades
+}
+
+// book reports whether a book update follows the last one emitted, the changes after a gap are
+// dropped until the next snapshot.
+func (s *stream) book(book *Book) bool {
+	changeID := atomic.LoadInt64(&s.changeID)
+	if book.ChangeID <= changeID {
+		return false
+	}
+	if !book.IsSnapshot && book.PrevChangeID != changeID {
+		return false
+	}
+	atomic.StoreInt64(&s.changeID, book.ChangeID)
+	return true
+}
+
+// trades removes the trades already emitted.
+func (s *stream) trades(trades []models.Trade) []models.Trade {
+	result := trades[:0:0]
+	for _, trade := range trades {
+		if trade.TradeSeq != 0 && trade.TradeSeq <= s.tradeSeq {
+			continue
+		}
+		if trade.TradeSeq > s.tradeSeq {
+			s.tradeSeq = trade.TradeSeq
+		}
+		result = append(result, trade)
+	}
+	return result
+}
+
+// caughtUp reports whether the primary feed has delivered the last book emitted.
+func (s *stream) caughtUp() bool {
+	primary := atomic.LoadInt64(&s.primaryChangeID)
+	return primary > 0 && primary >= atomic.LoadInt64(&s.changeID)
+}
+
+// Failover is the Feed of the multicast feed while it is healthy, it subscribes to the websocket
+// feed when a multicast group goes stale and unsubscribes once the multicast feed is healthy and
+// has caught up with the books emitted.
+//
+// The events of both feeds are merged per instrument so that the subscribers see neither a gap nor
+// a duplicate: the books are chained by change ID, a book starts with a snapshot and restarts with
+// one after a gap, the trades are filtered by sequence number and the ones missed by the switch are
+// fetched from the websocket client, and the tickers only move forward in time. The missed trades
+// are fetched after the last trade emitted, the trades of an instrument without any trade emitted
+// before the switch may have a gap.
+type Failover struct {
+	emitter
+	log     *zap.SugaredLogger
+	primary *MulticastFeed
+	backup  *WebsocketFeed
+
+	mu          sync.Mutex
+	instruments instruments
+	streams     map[string]*stream
+	active      Source
+	restoring   int32
+
+	onBook   func(*Book)
+	onTrades func(*Trades)
+	onTicker func(*Ticker)
+	onStale  func(*Stale)
+}
+
+// NewFailover creates a new Failover instance over a multicast and a websocket feed.
+func NewFailover(primary *MulticastFeed, backup *WebsocketFeed) *Failover {
+	f := &Failover{
+		emitter:     newEmitter(),
+		log:         zap.S(),
+		primary:     primary,
+		backup:      backup,
+		instruments: make(instruments),
+		streams:     make(map[string]*stream),
+		active:      SourceMulticast,
+	}
+	f.onBook = f.book
+	f.onTrades = f.trades
+	f.onTicker = f.ticker
+	f.onStale = f.stale
+
+	for _, feed := range []Feed{primary, backup} {
+		feed.On("book.*", f.onBook)
+		feed.On("trades.*", f.onTrades)
+		feed.On("ticker.*", f.onTicker)
+	}
+	primary.On(StaleChannel, f.onStale)
+	return f
+}
+
+// Close removes the listeners of the failover from the feeds.
+func (f *Failover) Close() {
+	for _, feed := range []Feed{f.primary, f.backup} {
+		feed.Off("book.*", f.onBook)
+		feed.Off("trades.*", f.onTrades)
+		feed.Off("ticker.*", f.onTicker)
+	}
+	f.primary.Off(StaleChannel, f.onStale)
+}
+
+// Active returns the source of the events emitted.
+func (f *Failover) Active() Source {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// Subscribe subscribes to instruments on the multicast feed, and on the websocket feed if it is active.
+func (f *Failover) Subscribe(ctx context.Context, names []string) error {
+	f.mu.Lock()
+	added := f.instruments.add(names)
+	for _, name := range added {
+		f.streams[name] = &stream{}
+	}
+	active := f.active
+	f.mu.Unlock()
+
+	if err := f.primary.Subscribe(ctx, added); err != nil {
+		return err
+	}
+	if active == SourceWebsocket {
+		return f.backup.Subscribe(ctx, added)
+	}
+	return nil
+}
+
+// Unsubscribe unsubscribes from instruments on both feeds.
+func (f *Failover) Unsubscribe(ctx context.Context, names []string) error {
+	f.mu.Lock()
+	removed := f.instruments.remove(names)
+	for _, name := range removed {
+		delete(f.streams, name)
+	}
+	f.mu.Unlock()
+
+	if err := f.primary.Unsubscribe(ctx, removed); err != nil {
+		return err
+	}
+	return f.backup.Unsubscribe(ctx, removed)
+}
+
+func (f *Failover) stream(instrument string) *stream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.streams[instrument]
+}
+
+func (f *Failover) book(book *Book) {
+	s := f.stream(book.InstrumentName)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if book.Source == SourceMulticast && book.ChangeID > atomic.LoadInt64(&s.primaryChangeID) {
+		atomic.StoreInt64(&s.primaryChangeID, book.ChangeID)
+	}
+	if s.book(book) {
+		f.Emit(BookChannel(book.InstrumentName), book)
+	}
+	s.mu.Unlock()
+
+	if book.Source == SourceMulticast && f.Active() == SourceWebsocket {
+		f.tryRestore()
+	}
+}
+
+func (f *Failover) trades(trades *Trades) {
+	s := f.stream(trades.InstrumentName)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.backfilling {
+		s.pending = append(s.pending, trades)
+		return
+	}
+	f.emitTrades(s, trades)
+}
+
+func (f *Failover) emitTrades(s *stream, trades *Trades) {
+	list := s.trades(trades.Trades)
+	if len(list) == 0 {
+		return
+	}
+	f.Emit(TradesChannel(trades.InstrumentName), &Trades{
+		Source:         trades.Source,
+		InstrumentName: trades.InstrumentName,
+		Trades:         list,
+	})
+}
+
+func (f *Failover) ticker(ticker *Ticker) {
+	s := f.stream(ticker.InstrumentName)
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ticker.Timestamp <= s.tickerTimestamp {
+		return
+	}
+	s.tickerTimestamp = ticker.Timestamp
+	f.Emit(TickerChannel(ticker.InstrumentName), ticker)
+}
+
+// stale switches to the websocket feed, the trades missed since the last one emitted are fetched
+// before the trades received from the websocket feed are emitted. The instruments without any trade
+// emitted have no sequence number to fetch from, their trades missed by the switch are lost.
+func (f *Failover) stale(event *Stale) {
+	f.mu.Lock()
+	if f.active == SourceWebsocket {
+		f.mu.Unlock()
+		return
+	}
+	f.active = SourceWebsocket
+	streams := make(map[string]*stream, len(f.streams))
+	for name, s := range f.streams {
+		streams[name] = s
+	}
+	f.mu.Unlock()
+
+	names := make([]string, 0, len(streams))
+	backfills := make(map[string]uint64)
+	for name, s := range streams {
+		names = append(names, name)
+		s.mu.Lock()
+		if s.tradeSeq > 0 {
+			s.backfilling = true
+			backfills[name] = s.tradeSeq
+		}
+		s.mu.Unlock()
+	}
+
+	f.log.Warnw("Multicast feed is stale, switching to websocket",
+		"address", event.Address, "silence", event.Silence)
+	f.Emit(SourceChannel, SourceWebsocket)
+
+	ctx := context.Background()
+	if err := f.backup.Subscribe(ctx, names); err != nil {
+		f.log.Errorw("Fail to subscribe to websocket feed", "error", err)
+	}
+	for name, tradeSeq := range backfills {
+		f.backfill(ctx, name, tradeSeq)
+	}
+}
+
+// backfill emits the trades of an instrument after tradeSeq, then the pending ones. If fetching
+// fails, the trades fetched before are emitted and the rest is a gap.
+func (f *Failover) backfill(ctx context.Context, instrument string, tradeSeq uint64) {
+	trades, err := f.backup.TradesSince(ctx, instrument, tradeSeq+1)
+
+	s := f.stream(instrument)
+	if s != nil {
+		s.mu.Lock()
+		if len(trades) > 0 {
+			f.emitTrades(s, &Trades{Source: SourceWebsocket, InstrumentName: instrument, Trades: trades})
+		}
+		for _, pending := range s.pending {
+			f.emitTrades(s, pending)
+		}
+		s.pending = nil
+		s.backfilling = false
+		s.mu.Unlock()
+	}
+
+	if err != nil {
+		f.log.Errorw("Fail to backfill trades", "instrument", instrument, "trade_seq", tradeSeq,
+			"fetched", len(trades), "error", err)
+	}
+}
+
+// tryRestore switches back to the multicast feed once it is healthy and has caught up with every
+// book emitted. It runs in its own goroutine, the websocket feed can not be unsubscribed from one
+// of its listeners.
+func (f *Failover) tryRestore() {
+	if !f.primary.Healthy() || !atomic.CompareAndSwapInt32(&f.restoring, 0, 1) {
+		return
+	}
+
+	f.mu.Lock()
+	for _, s := range f.streams {
+		if !s.caughtUp() {
+			f.mu.Unlock()
+			atomic.StoreInt32(&f.restoring, 0)
+			return
+		}
+	}
+	f.mu.Unlock()
+
+	go func() {
+		defer atomic.StoreInt32(&f.restoring, 0)
+
+		f.mu.Lock()
+		if f.active != SourceWebsocket {
+			f.mu.Unlock()
+			return
+		}
+		f.active = SourceMulticast
+		names := make([]string, 0, len(f.instruments))
+		for name := range f.instruments {
+			names = append(names, name)
+		}
+		f.mu.Unlock()
+
+		f.log.Infow("Multicast feed has recovered, switching back from websocket")
+		f.Emit(SourceChannel, SourceMulticast)
+		if err := f.backup.Unsubscribe(context.Background(), names); err != nil {
+			f.log.Errorw("Fail to unsubscribe from websocket feed", "error", err)
+		}
+	}()
+}
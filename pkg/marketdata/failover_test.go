@@ -0,0 +1,223 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const btcPerpetual = "BTC-PERPETUAL"
+
+type failoverTest struct {
+	multicast *fakeMulticast
+	websocket *fakeWebsocket
+	failover  *Failover
+	events    *recorder
+
+	mu      sync.Mutex
+	sources []Source
+}
+
+func newFailoverTest(t *testing.T) *failoverTest {
+	t.Helper()
+
+	ft := &failoverTest{multicast: newFakeMulticast(), websocket: newFakeWebsocket()}
+	ft.failover = NewFailover(NewMulticastFeed(ft.multicast), NewWebsocketFeed(ft.websocket))
+	ft.events = record(ft.failover)
+	ft.failover.On(SourceChannel, func(source Source) {
+		ft.mu.Lock()
+		ft.sources = append(ft.sources, source)
+		ft.mu.Unlock()
+	})
+	require.NoError(t, ft.failover.Subscribe(context.Background(), []string{btcPerpetual}))
+	return ft
+}
+
+func (ft *failoverTest) switches() []Source {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	return append([]Source(nil), ft.sources...)
+}
+
+func (ft *failoverTest) stale() {
+	ft.multicast.setStale(true)
+	ft.multicast.Emit(multicast.StaleEventChannel, &multicast.StaleEvent{Address: "239.111.111.1:6100"})
+}
+
+func (ft *failoverTest) multicastBook(prev, changeID int64) {
+	book := &models.OrderBookRawNotification{InstrumentName: btcPerpetual, PrevChangeID: prev, ChangeID: changeID}
+	if prev == 0 {
+		ft.multicast.Emit("snapshot."+btcPerpetual, book)
+		return
+	}
+	ft.multicast.Emit("book."+btcPerpetual, book)
+}
+
+func (ft *failoverTest) websocketBook(prev, changeID int64) {
+	ft.websocket.Emit("book."+btcPerpetual+".raw", &models.OrderBookRawNotification{
+		InstrumentName: btcPerpetual, PrevChangeID: prev, ChangeID: changeID,
+	})
+}
+
+// books returns the change IDs emitted with their source.
+func (ft *failoverTest) books() []string {
+	var books []string
+	for _, event := range ft.events.get() {
+		if book, ok := event.(*Book); ok {
+			id := string(book.Source[0])
+			if book.IsSnapshot {
+				id += "s"
+			}
+			books = append(books, id+string(rune('0'+book.ChangeID)))
+		}
+	}
+	return books
+}
+
+func (ft *failoverTest) tradeSeqs() []uint64 {
+	var seqs []uint64
+	for _, event := range ft.events.get() {
+		if trades, ok := event.(*Trades); ok {
+			for _, trade := range trades.Trades {
+				seqs = append(seqs, trade.TradeSeq)
+			}
+		}
+	}
+	return seqs
+}
+
+func TestFailoverBooks(t *testing.T) {
+	ft := newFailoverTest(t)
+
+	// The changes before the first snapshot are dropped.
+	ft.multicastBook(1, 2)
+	ft.multicastBook(0, 2)
+	ft.multicastBook(2, 3)
+	assert.Equal(t, SourceMulticast, ft.failover.Active())
+	assert.Empty(t, ft.websocket.subscriptions())
+
+	ft.stale()
+	assert.Equal(t, SourceWebsocket, ft.failover.Active())
+	assert.Len(t, ft.websocket.subscriptions(), 3)
+
+	// The websocket snapshot is behind, its changes continue the book.
+	ft.websocketBook(0, 3)
+	ft.websocketBook(3, 4)
+	ft.websocketBook(4, 5)
+	// The multicast feed catches up while it is still stale.
+	ft.multicastBook(3, 4)
+	ft.multicastBook(4, 5)
+	assert.Equal(t, SourceWebsocket, ft.failover.Active())
+
+	ft.multicast.setStale(false)
+	ft.websocketBook(5, 6)
+	ft.multicastBook(5, 6)
+	require.Eventually(t, func() bool {
+		return ft.failover.Active() == SourceMulticast && len(ft.websocket.subscriptions()) == 0
+	}, time.Second, time.Millisecond)
+
+	// A gap is skipped until the next snapshot.
+	ft.multicastBook(7, 8)
+	ft.multicastBook(0, 8)
+	ft.multicastBook(8, 9)
+
+	assert.Equal(t, []string{"ms2", "m3", "w4", "w5", "w6", "ms8", "m9"}, ft.books())
+	assert.Equal(t, []Source{SourceWebsocket, SourceMulticast}, ft.switches())
+}
+
+func TestFailoverTrades(t *testing.T) {
+	ft := newFailoverTest(t)
+	trade := func(seq uint64) models.Trade {
+		return models.Trade{InstrumentName: btcPerpetual, TradeSeq: seq}
+	}
+	for seq := uint64(1); seq <= 6; seq++ {
+		ft.websocket.trades = append(ft.websocket.trades, trade(seq))
+	}
+	release := make(chan struct{})
+	ft.websocket.release = release
+
+	ft.multicast.Emit("trades.future.BTC", &models.TradesNotification{trade(1), trade(2)})
+
+	// The trades 3 and 4 are missed by the switch, the websocket feed delivers 4 and 5 while
+	// the missed ones are fetched.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ft.stale()
+	}()
+	<-ft.websocket.subscribed
+	ft.websocket.Emit("trades."+btcPerpetual+".raw", &models.TradesNotification{trade(4), trade(5)})
+	ft.multicast.Emit("trades.future.BTC", &models.TradesNotification{trade(2)})
+	assert.Equal(t, []uint64{1, 2}, ft.tradeSeqs())
+
+	close(release)
+	<-done
+	ft.websocket.Emit("trades."+btcPerpetual+".raw", &models.TradesNotification{trade(5), trade(6)})
+
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, ft.tradeSeqs())
+}
+
+func TestFailoverPartialBackfill(t *testing.T) {
+	ft := newFailoverTest(t)
+	trade := func(seq uint64) models.Trade {
+		return models.Trade{InstrumentName: btcPerpetual, TradeSeq: seq}
+	}
+	for seq := uint64(1); seq <= 6; seq++ {
+		ft.websocket.trades = append(ft.websocket.trades, trade(seq))
+	}
+	// fetching the missed trades fails after the page of 3 and 4
+	ft.websocket.failSeq = 5
+
+	ft.multicast.Emit("trades.future.BTC", &models.TradesNotification{trade(1), trade(2)})
+	ft.stale()
+	<-ft.websocket.subscribed
+	ft.websocket.Emit("trades."+btcPerpetual+".raw", &models.TradesNotification{trade(6)})
+
+	assert.Equal(t, []uint64{1, 2, 3, 4, 6}, ft.tradeSeqs())
+}
+
+func TestFailoverTickers(t *testing.T) {
+	ft := newFailoverTest(t)
+	ticker := func(timestamp uint64) *models.TickerNotification {
+		return &models.TickerNotification{InstrumentName: btcPerpetual, Timestamp: timestamp}
+	}
+
+	ft.multicast.Emit("ticker."+btcPerpetual, ticker(1))
+	ft.stale()
+	ft.websocket.Emit("ticker."+btcPerpetual+".100ms", ticker(1))
+	ft.websocket.Emit("ticker."+btcPerpetual+".100ms", ticker(3))
+	ft.multicast.Emit("ticker."+btcPerpetual, ticker(2))
+	ft.multicast.Emit("ticker."+btcPerpetual, ticker(4))
+	ft.multicast.Emit("ticker.ETH-PERPETUAL", &models.TickerNotification{InstrumentName: "ETH-PERPETUAL"})
+
+	var got []string
+	for _, event := range ft.events.get() {
+		ticker := event.(*Ticker)
+		got = append(got, string(ticker.Source[0])+string(rune('0'+ticker.Timestamp)))
+	}
+	assert.Equal(t, []string{"m1", "w3", "m4"}, got)
+}
+
+func TestFailoverSubscribe(t *testing.T) {
+	ft := newFailoverTest(t)
+	ft.stale()
+
+	// The instruments subscribed while the websocket feed is active are subscribed on both feeds.
+	require.NoError(t, ft.failover.Subscribe(context.Background(), []string{"ETH-PERPETUAL"}))
+	assert.Len(t, ft.websocket.subscriptions(), 6)
+	ft.multicast.Emit("ticker.ETH-PERPETUAL", &models.TickerNotification{InstrumentName: "ETH-PERPETUAL", Timestamp: 1})
+	assert.Len(t, ft.events.get(), 1)
+
+	require.NoError(t, ft.failover.Unsubscribe(context.Background(), []string{btcPerpetual, "ETH-PERPETUAL"}))
+	assert.Empty(t, ft.websocket.subscriptions())
+	ft.multicast.Emit("ticker.ETH-PERPETUAL", &models.TickerNotification{InstrumentName: "ETH-PERPETUAL", Timestamp: 2})
+	assert.Len(t, ft.events.get(), 1)
+
+	ft.failover.Close()
+}
@@ -0,0 +1,157 @@
+// Package marketdata delivers the books, trades and tickers of the multicast, websocket and FIX
+// clients with one set of channels and event types.
+package marketdata
+
+import (
+	"context"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/router"
+	"github.com/chuckpreslar/emission"
+	"go.uber.org/zap"
+)
+
+// Source is the client an event was received from.
+type Source string
+
+const (
+	SourceMulticast Source = "multicast"
+	SourceWebsocket Source = "websocket"
+	SourceFIX       Source = "fix"
+)
+
+// StaleChannel receives a *Stale when the transport of a feed goes quiet.
+const StaleChannel = "stale"
+
+// Feed delivers the market data of the subscribed instruments, whatever the client behind it:
+//   - "book.<instrument>" receives a *Book,
+//   - "trades.<instrument>" receives a *Trades,
+//   - "ticker.<instrument>" receives a *Ticker.
+//
+// The channels accept patterns with wildcards, e.g. "book.BTC-*".
+type Feed interface {
+	Subscribe(ctx context.Context, instruments []string) error
+	Unsubscribe(ctx context.Context, instruments []string) error
+	On(event interface{}, listener interface{}) *emission.Emitter
+	Off(event interface{}, listener interface{}) *emission.Emitter
+}
+
+// Book is an order book update, either a full snapshot or the changes following PrevChangeID.
+type Book struct {
+	Source         Source
+	InstrumentName string
+	Timestamp      int64
+	IsSnapshot     bool
+	// PrevChangeID and ChangeID chain the updates of a book, they are zero when the source has
+	// none, i.e. FIX. PrevChangeID is zero for a snapshot.
+	PrevChangeID int64
+	ChangeID     int64
+	Bids         []models.OrderBookNotificationItem // [action, price, amount]
+	Asks         []models.OrderBookNotificationItem // [action, price, amount]
+}
+
+// Trades are trades of one instrument in the order of their sequence number.
+type Trades struct {
+	Source         Source
+	InstrumentName string
+	Trades         []models.Trade
+}
+
+// Ticker is the ticker of an instrument.
+type Ticker struct {
+	Source Source
+	models.TickerNotification
+}
+
+// Stale is a feed which received nothing for longer than the threshold of its client.
+type Stale struct {
+	Source  Source
+	Address string
+	Silence time.Duration
+}
+
+func BookChannel(instrument string) string {
+	return "book." + instrument
+}
+
+func TradesChannel(instrument string) string {
+	return "trades." + instrument
+}
+
+func TickerChannel(instrument string) string {
+	return "ticker." + instrument
+}
+
+// emitter dispatches the events of a feed, both to the listeners of a channel and of a pattern.
+type emitter struct {
+	log     *zap.SugaredLogger
+	emitter *emission.Emitter
+	router  *router.Router
+}
+
+func newEmitter() emitter {
+	return emitter{
+		log:     zap.S(),
+		emitter: emission.NewEmitter(),
+		router:  router.New(),
+	}
+}
+
+// On adds a listener to a specific event, or to every channel matching a pattern with wildcards.
+func (e *emitter) On(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		if err := e.router.On(event.(string), listener); err != nil {
+			e.log.Warnw("Fail to add listener", "pattern", event, "error", err)
+		}
+		return e.emitter
+	}
+	return e.emitter.On(event, listener)
+}
+
+// Emit emits an event.
+func (e *emitter) Emit(event interface{}, arguments ...interface{}) *emission.Emitter {
+	e.router.Emit(event, arguments...)
+	return e.emitter.Emit(event, arguments...)
+}
+
+// Off removes a listener for an event.
+func (e *emitter) Off(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		e.router.Off(event.(string), listener)
+		return e.emitter
+	}
+	return e.emitter.Off(event, listener)
+}
+
+// instruments is a set of subscribed instruments.
+type instruments map[string]struct{}
+
+func (s instruments) add(names []string) (added []string) {
+	for _, name := range names {
+		if _, ok := s[name]; !ok {
+			s[name] = struct{}{}
+			added = append(added, name)
+		}
+	}
+	return added
+}
+
+func (s instruments) remove(names []string) (removed []string) {
+	for _, name := range names {
+		if _, ok := s[name]; ok {
+			delete(s, name)
+			removed = append(removed, name)
+		}
+	}
+	return removed
+}
+
+// splitTrades groups trades by instrument, keeping their order.
+func splitTrades(trades models.TradesNotification) map[string][]models.Trade {
+	result := make(map[string][]models.Trade, 1)
+	for _, trade := range trades {
+		result[trade.InstrumentName] = append(result[trade.InstrumentName], trade)
+	}
+	return result
+}
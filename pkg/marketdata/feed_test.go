@@ -0,0 +1,356 @@
+package marketdata
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/fix"
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast"
+	"github.com/KyberNetwork/deribit-api/pkg/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ MulticastClient = (*multicast.Client)(nil)
+	_ WebsocketClient = (*websocket.Client)(nil)
+	_ FIXClient       = (*fix.Client)(nil)
+
+	_ Feed = (*MulticastFeed)(nil)
+	_ Feed = (*WebsocketFeed)(nil)
+	_ Feed = (*FIXFeed)(nil)
+	_ Feed = (*Failover)(nil)
+)
+
+var (
+	errSubscribe = errors.New("subscribe failed")
+	errTrades    = errors.New("get trades failed")
+)
+
+type fakeMulticast struct {
+	emitter
+
+	mu    sync.Mutex
+	stats multicast.Stats
+}
+
+func newFakeMulticast() *fakeMulticast {
+	return &fakeMulticast{emitter: newEmitter()}
+}
+
+func (c *fakeMulticast) Stats() multicast.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+func (c *fakeMulticast) setStale(stale bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.Groups = map[string]multicast.GroupStats{"239.111.111.1:6100": {Stale: stale}}
+}
+
+type fakeWebsocket struct {
+	emitter
+
+	mu         sync.Mutex
+	channels   map[string]bool
+	subscribed chan []string
+	err        error
+	trades     []models.Trade
+	release    chan struct{} // blocks GetLastTradesByInstrument until closed, if set
+	failSeq    uint64        // fails GetLastTradesByInstrument from this sequence number, if set
+}
+
+func newFakeWebsocket() *fakeWebsocket {
+	return &fakeWebsocket{
+		emitter:    newEmitter(),
+		channels:   make(map[string]bool),
+		subscribed: make(chan []string, 10),
+	}
+}
+
+func (c *fakeWebsocket) Subscribe(channels []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	for _, channel := range channels {
+		c.channels[channel] = true
+	}
+	c.subscribed <- channels
+	return nil
+}
+
+func (c *fakeWebsocket) UnSubscribe(channels []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, channel := range channels {
+		delete(c.channels, channel)
+	}
+	return nil
+}
+
+func (c *fakeWebsocket) subscriptions() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	channels := make([]string, 0, len(c.channels))
+	for channel := range c.channels {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// GetLastTradesByInstrument serves the trades by pages of two.
+func (c *fakeWebsocket) GetLastTradesByInstrument(
+	_ context.Context, params *models.GetLastTradesByInstrumentParams,
+) (models.GetLastTradesResponse, error) {
+	c.mu.Lock()
+	release := c.release
+	c.mu.Unlock()
+	if release != nil {
+		<-release
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var resp models.GetLastTradesResponse
+	if c.failSeq > 0 && uint64(params.StartSeq) >= c.failSeq {
+		return resp, errTrades
+	}
+	for _, trade := range c.trades {
+		if trade.InstrumentName != params.InstrumentName || trade.TradeSeq < uint64(params.StartSeq) {
+			continue
+		}
+		if len(resp.Trades) == 2 {
+			resp.HasMore = true
+			break
+		}
+		resp.Trades = append(resp.Trades, trade)
+	}
+	return resp, nil
+}
+
+type fakeFIX struct {
+	emitter
+
+	mu       sync.Mutex
+	channels []string
+}
+
+func (c *fakeFIX) Subscribe(_ context.Context, channels []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.channels = append(c.channels, channels...)
+	return nil
+}
+
+func (c *fakeFIX) Unsubscribe(_ context.Context, channels []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.channels = append(c.channels, "-")
+	c.channels = append(c.channels, channels...)
+	return nil
+}
+
+// recorder records the events of a feed in the order they are emitted.
+type recorder struct {
+	mu     sync.Mutex
+	events []interface{}
+}
+
+func record(feed Feed) *recorder {
+	r := &recorder{}
+	add := func(event interface{}) {
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+	feed.On("book.*", func(book *Book) { add(book) })
+	feed.On("trades.*", func(trades *Trades) { add(trades) })
+	feed.On("ticker.*", func(ticker *Ticker) { add(ticker) })
+	feed.On(StaleChannel, func(stale *Stale) { add(stale) })
+	return r
+}
+
+func (r *recorder) get() []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]interface{}(nil), r.events...)
+}
+
+func (r *recorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+func bookItems(price float64) []models.OrderBookNotificationItem {
+	return []models.OrderBookNotificationItem{{Action: "new", Price: price, Amount: 10}}
+}
+
+func TestMulticastFeed(t *testing.T) {
+	client := newFakeMulticast()
+	feed := NewMulticastFeed(client)
+	r := record(feed)
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+
+	client.Emit("snapshot.BTC-PERPETUAL", &models.OrderBookRawNotification{
+		Timestamp: 1, InstrumentName: "BTC-PERPETUAL", ChangeID: 10, Bids: bookItems(100),
+	})
+	client.Emit("book.BTC-PERPETUAL", &models.OrderBookRawNotification{
+		Timestamp: 2, InstrumentName: "BTC-PERPETUAL", PrevChangeID: 10, ChangeID: 11, Asks: bookItems(101),
+	})
+	client.Emit("book.ETH-PERPETUAL", &models.OrderBookRawNotification{InstrumentName: "ETH-PERPETUAL"})
+	client.Emit("trades.future.BTC", &models.TradesNotification{
+		{InstrumentName: "BTC-PERPETUAL", TradeSeq: 1},
+		{InstrumentName: "BTC-30DEC22", TradeSeq: 7},
+		{InstrumentName: "BTC-PERPETUAL", TradeSeq: 2},
+	})
+	client.Emit("ticker.BTC-PERPETUAL", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL", Timestamp: 3})
+	client.Emit(multicast.StaleEventChannel, &multicast.StaleEvent{Address: "239.111.111.1:6100", Silence: time.Second})
+
+	assert.Equal(t, []interface{}{
+		&Book{
+			Source: SourceMulticast, InstrumentName: "BTC-PERPETUAL", Timestamp: 1, IsSnapshot: true,
+			ChangeID: 10, Bids: bookItems(100),
+		},
+		&Book{
+			Source: SourceMulticast, InstrumentName: "BTC-PERPETUAL", Timestamp: 2,
+			PrevChangeID: 10, ChangeID: 11, Asks: bookItems(101),
+		},
+		&Trades{Source: SourceMulticast, InstrumentName: "BTC-PERPETUAL", Trades: []models.Trade{
+			{InstrumentName: "BTC-PERPETUAL", TradeSeq: 1}, {InstrumentName: "BTC-PERPETUAL", TradeSeq: 2},
+		}},
+		&Ticker{
+			Source:             SourceMulticast,
+			TickerNotification: models.TickerNotification{InstrumentName: "BTC-PERPETUAL", Timestamp: 3},
+		},
+		&Stale{Source: SourceMulticast, Address: "239.111.111.1:6100", Silence: time.Second},
+	}, r.get())
+	assert.True(t, feed.Healthy())
+	client.setStale(true)
+	assert.False(t, feed.Healthy())
+
+	r.reset()
+	require.NoError(t, feed.Unsubscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	client.Emit("ticker.BTC-PERPETUAL", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	feed.Close()
+	client.Emit("ticker.BTC-PERPETUAL", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+	assert.Empty(t, r.get())
+}
+
+func TestWebsocketFeed(t *testing.T) {
+	client := newFakeWebsocket()
+	feed := NewWebsocketFeed(client)
+	r := record(feed)
+
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	assert.Len(t, client.subscribed, 1)
+	assert.Equal(t,
+		[]string{"book.BTC-PERPETUAL.raw", "ticker.BTC-PERPETUAL.100ms", "trades.BTC-PERPETUAL.raw"},
+		client.subscriptions())
+
+	client.Emit("book.BTC-PERPETUAL.raw", &models.OrderBookRawNotification{
+		InstrumentName: "BTC-PERPETUAL", ChangeID: 10, Bids: bookItems(100),
+	})
+	client.Emit("book.BTC-PERPETUAL.raw", &models.OrderBookRawNotification{
+		InstrumentName: "BTC-PERPETUAL", PrevChangeID: 10, ChangeID: 11,
+	})
+	// Aggregated books are not part of the feed.
+	client.Emit("book.BTC-PERPETUAL.100ms", &models.OrderBookRawNotification{InstrumentName: "BTC-PERPETUAL"})
+	client.Emit("trades.BTC-PERPETUAL.raw", &models.TradesNotification{{InstrumentName: "BTC-PERPETUAL", TradeSeq: 1}})
+	client.Emit("ticker.BTC-PERPETUAL.100ms", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+
+	assert.Equal(t, []interface{}{
+		&Book{
+			Source: SourceWebsocket, InstrumentName: "BTC-PERPETUAL", IsSnapshot: true, ChangeID: 10,
+			Bids: bookItems(100),
+		},
+		&Book{Source: SourceWebsocket, InstrumentName: "BTC-PERPETUAL", PrevChangeID: 10, ChangeID: 11},
+		&Trades{Source: SourceWebsocket, InstrumentName: "BTC-PERPETUAL", Trades: []models.Trade{
+			{InstrumentName: "BTC-PERPETUAL", TradeSeq: 1},
+		}},
+		&Ticker{
+			Source:             SourceWebsocket,
+			TickerNotification: models.TickerNotification{InstrumentName: "BTC-PERPETUAL"},
+		},
+	}, r.get())
+
+	require.NoError(t, feed.Unsubscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	assert.Empty(t, client.subscriptions())
+
+	// A failed subscription is not recorded.
+	client.err = errSubscribe
+	require.ErrorIs(t, feed.Subscribe(context.Background(), []string{"ETH-PERPETUAL"}), errSubscribe)
+	client.err = nil
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"ETH-PERPETUAL"}))
+	assert.Len(t, client.subscriptions(), 3)
+}
+
+func TestTradesSince(t *testing.T) {
+	client := newFakeWebsocket()
+	for seq := uint64(1); seq <= 5; seq++ {
+		client.trades = append(client.trades, models.Trade{InstrumentName: "BTC-PERPETUAL", TradeSeq: seq})
+	}
+	feed := NewWebsocketFeed(client)
+
+	trades, err := feed.TradesSince(context.Background(), "BTC-PERPETUAL", 2)
+	require.NoError(t, err)
+	var seqs []uint64
+	for _, trade := range trades {
+		seqs = append(seqs, trade.TradeSeq)
+	}
+	assert.Equal(t, []uint64{2, 3, 4, 5}, seqs)
+
+	trades, err = feed.TradesSince(context.Background(), "BTC-PERPETUAL", 6)
+	require.NoError(t, err)
+	assert.Empty(t, trades)
+
+	// the pages fetched before an error are returned with it
+	client.failSeq = 4
+	trades, err = feed.TradesSince(context.Background(), "BTC-PERPETUAL", 2)
+	require.ErrorIs(t, err, errTrades)
+	require.Len(t, trades, 2)
+	assert.Equal(t, uint64(3), trades[1].TradeSeq)
+}
+
+func TestFIXFeed(t *testing.T) {
+	client := &fakeFIX{emitter: newEmitter()}
+	feed := NewFIXFeed(client)
+	r := record(feed)
+
+	require.NoError(t, feed.Subscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	client.Emit("book.BTC-PERPETUAL", &models.OrderBookRawNotification{
+		InstrumentName: "BTC-PERPETUAL", Timestamp: 1, Bids: bookItems(100),
+	}, true)
+	client.Emit("book.BTC-PERPETUAL", &models.OrderBookRawNotification{
+		InstrumentName: "BTC-PERPETUAL", Timestamp: 2, Asks: bookItems(101),
+	}, false)
+	client.Emit("trades.BTC-PERPETUAL", &models.TradesNotification{{InstrumentName: "BTC-PERPETUAL", TradeID: "1"}})
+	client.Emit("ticker.BTC-PERPETUAL", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+	require.NoError(t, feed.Unsubscribe(context.Background(), []string{"BTC-PERPETUAL"}))
+	client.Emit("ticker.BTC-PERPETUAL", &models.TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+
+	assert.Equal(t, []interface{}{
+		&Book{Source: SourceFIX, InstrumentName: "BTC-PERPETUAL", Timestamp: 1, IsSnapshot: true, Bids: bookItems(100)},
+		&Book{Source: SourceFIX, InstrumentName: "BTC-PERPETUAL", Timestamp: 2, Asks: bookItems(101)},
+		&Trades{Source: SourceFIX, InstrumentName: "BTC-PERPETUAL", Trades: []models.Trade{
+			{InstrumentName: "BTC-PERPETUAL", TradeID: "1"},
+		}},
+		&Ticker{Source: SourceFIX, TickerNotification: models.TickerNotification{InstrumentName: "BTC-PERPETUAL"}},
+	}, r.get())
+	assert.Equal(t, []string{
+		"book.BTC-PERPETUAL", "trades.BTC-PERPETUAL", "ticker.BTC-PERPETUAL",
+		"-", "book.BTC-PERPETUAL", "trades.BTC-PERPETUAL", "ticker.BTC-PERPETUAL",
+	}, client.channels)
+}
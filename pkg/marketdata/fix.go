@@ -0,0 +1,136 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/chuckpreslar/emission"
+)
+
+// FIXClient is the subset of the FIX client used by FIXFeed.
+type FIXClient interface {
+	On(event interface{}, listener interface{}) *emission.Emitter
+	Off(event interface{}, listener interface{}) *emission.Emitter
+	Subscribe(ctx context.Context, channels []string) error
+	Unsubscribe(ctx context.Context, channels []string) error
+}
+
+// FIXFeed is the Feed of a FIX client. The books of FIX have no change IDs, a full refresh is
+// emitted as a snapshot. The client is started by the caller.
+type FIXFeed struct {
+	emitter
+	client FIXClient
+
+	mu          sync.RWMutex
+	instruments instruments
+
+	onBook   func(*models.OrderBookRawNotification, bool)
+	onTrades func(*models.TradesNotification)
+	onTicker func(*models.TickerNotification)
+}
+
+// NewFIXFeed creates a new FIXFeed instance listening to the events of client.
+func NewFIXFeed(client FIXClient) *FIXFeed {
+	f := &FIXFeed{
+		emitter:     newEmitter(),
+		client:      client,
+		instruments: make(instruments),
+	}
+	f.onBook = f.book
+	f.onTrades = f.trades
+	f.onTicker = f.ticker
+
+	client.On("book.*", f.onBook)
+	client.On("trades.*", f.onTrades)
+	client.On("ticker.*", f.onTicker)
+	return f
+}
+
+// Close removes the listeners of the feed from the client, the subscriptions are kept.
+func (f *FIXFeed) Close() {
+	f.client.Off("book.*", f.onBook)
+	f.client.Off("trades.*", f.onTrades)
+	f.client.Off("ticker.*", f.onTicker)
+}
+
+func fixChannels(names []string) []string {
+	channels := make([]string, 0, 3*len(names))
+	for _, name := range names {
+		channels = append(channels, BookChannel(name), TradesChannel(name), TickerChannel(name))
+	}
+	return channels
+}
+
+// Subscribe subscribes to the books, trades and tickers of instruments.
+func (f *FIXFeed) Subscribe(ctx context.Context, names []string) error {
+	f.mu.Lock()
+	added := f.instruments.add(names)
+	f.mu.Unlock()
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := f.client.Subscribe(ctx, fixChannels(added)); err != nil {
+		f.mu.Lock()
+		f.instruments.remove(added)
+		f.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe unsubscribes from the books, trades and tickers of instruments.
+func (f *FIXFeed) Unsubscribe(ctx context.Context, names []string) error {
+	f.mu.Lock()
+	removed := f.instruments.remove(names)
+	f.mu.Unlock()
+	if len(removed) == 0 {
+		return nil
+	}
+	return f.client.Unsubscribe(ctx, fixChannels(removed))
+}
+
+func (f *FIXFeed) subscribed(instrument string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.instruments[instrument]
+	return ok
+}
+
+func (f *FIXFeed) book(book *models.OrderBookRawNotification, isSnapshot bool) {
+	if !f.subscribed(book.InstrumentName) {
+		return
+	}
+	f.Emit(BookChannel(book.InstrumentName), &Book{
+		Source:         SourceFIX,
+		InstrumentName: book.InstrumentName,
+		Timestamp:      book.Timestamp,
+		IsSnapshot:     isSnapshot,
+		Bids:           book.Bids,
+		Asks:           book.Asks,
+	})
+}
+
+func (f *FIXFeed) trades(trades *models.TradesNotification) {
+	for instrument, list := range splitTrades(*trades) {
+		if !f.subscribed(instrument) {
+			continue
+		}
+		f.Emit(TradesChannel(instrument), &Trades{
+			Source:         SourceFIX,
+			InstrumentName: instrument,
+			Trades:         list,
+		})
+	}
+}
+
+func (f *FIXFeed) ticker(ticker *models.TickerNotification) {
+	if !f.subscribed(ticker.InstrumentName) {
+		return
+	}
+	f.Emit(TickerChannel(ticker.InstrumentName), &Ticker{
+		Source:             SourceFIX,
+		TickerNotification: *ticker,
+	})
+}
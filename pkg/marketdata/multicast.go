@@ -0,0 +1,143 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast"
+	"github.com/chuckpreslar/emission"
+)
+
+// MulticastClient is the subset of the multicast client used by MulticastFeed.
+type MulticastClient interface {
+	On(event interface{}, listener interface{}) *emission.Emitter
+	Off(event interface{}, listener interface{}) *emission.Emitter
+	Stats() multicast.Stats
+}
+
+// MulticastFeed is the Feed of a multicast client. The multicast groups carry every instrument,
+// subscribing only selects the instruments emitted. The client is started by the caller.
+type MulticastFeed struct {
+	emitter
+	client MulticastClient
+
+	mu          sync.RWMutex
+	instruments instruments
+
+	onBook     func(*models.OrderBookRawNotification)
+	onSnapshot func(*models.OrderBookRawNotification)
+	onTrades   func(*models.TradesNotification)
+	onTicker   func(*models.TickerNotification)
+	onStale    func(*multicast.StaleEvent)
+}
+
+// NewMulticastFeed creates a new MulticastFeed instance listening to the events of client.
+func NewMulticastFeed(client MulticastClient) *MulticastFeed {
+	f := &MulticastFeed{
+		emitter:     newEmitter(),
+		client:      client,
+		instruments: make(instruments),
+	}
+	f.onBook = func(book *models.OrderBookRawNotification) { f.book(book, false) }
+	f.onSnapshot = func(book *models.OrderBookRawNotification) { f.book(book, true) }
+	f.onTrades = f.trades
+	f.onTicker = f.ticker
+	f.onStale = f.stale
+
+	client.On("book.*", f.onBook)
+	client.On("snapshot.*", f.onSnapshot)
+	client.On("trades.*.*", f.onTrades)
+	client.On("ticker.*", f.onTicker)
+	client.On(multicast.StaleEventChannel, f.onStale)
+	return f
+}
+
+// Close removes the listeners of the feed from the client.
+func (f *MulticastFeed) Close() {
+	f.client.Off("book.*", f.onBook)
+	f.client.Off("snapshot.*", f.onSnapshot)
+	f.client.Off("trades.*.*", f.onTrades)
+	f.client.Off("ticker.*", f.onTicker)
+	f.client.Off(multicast.StaleEventChannel, f.onStale)
+}
+
+// Subscribe adds instruments to the emitted ones.
+func (f *MulticastFeed) Subscribe(_ context.Context, names []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instruments.add(names)
+	return nil
+}
+
+// Unsubscribe removes instruments from the emitted ones.
+func (f *MulticastFeed) Unsubscribe(_ context.Context, names []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instruments.remove(names)
+	return nil
+}
+
+// Healthy reports whether none of the multicast groups is stale.
+func (f *MulticastFeed) Healthy() bool {
+	for _, group := range f.client.Stats().Groups {
+		if group.Stale {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *MulticastFeed) subscribed(instrument string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.instruments[instrument]
+	return ok
+}
+
+func (f *MulticastFeed) book(book *models.OrderBookRawNotification, isSnapshot bool) {
+	if !f.subscribed(book.InstrumentName) {
+		return
+	}
+	f.Emit(BookChannel(book.InstrumentName), &Book{
+		Source:         SourceMulticast,
+		InstrumentName: book.InstrumentName,
+		Timestamp:      book.Timestamp,
+		IsSnapshot:     isSnapshot,
+		PrevChangeID:   book.PrevChangeID,
+		ChangeID:       book.ChangeID,
+		Bids:           book.Bids,
+		Asks:           book.Asks,
+	})
+}
+
+func (f *MulticastFeed) trades(trades *models.TradesNotification) {
+	for instrument, list := range splitTrades(*trades) {
+		if !f.subscribed(instrument) {
+			continue
+		}
+		f.Emit(TradesChannel(instrument), &Trades{
+			Source:         SourceMulticast,
+			InstrumentName: instrument,
+			Trades:         list,
+		})
+	}
+}
+
+func (f *MulticastFeed) ticker(ticker *models.TickerNotification) {
+	if !f.subscribed(ticker.InstrumentName) {
+		return
+	}
+	f.Emit(TickerChannel(ticker.InstrumentName), &Ticker{
+		Source:             SourceMulticast,
+		TickerNotification: *ticker,
+	})
+}
+
+func (f *MulticastFeed) stale(event *multicast.StaleEvent) {
+	f.Emit(StaleChannel, &Stale{
+		Source:  SourceMulticast,
+		Address: event.Address,
+		Silence: event.Silence,
+	})
+}
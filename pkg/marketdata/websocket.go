@@ -0,0 +1,178 @@
+package marketdata
+
+import (
+	"context"
+	"sync"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/chuckpreslar/emission"
+)
+
+const (
+	websocketBookInterval   = "raw"
+	websocketTradesInterval = "raw"
+	websocketTickerInterval = "100ms"
+
+	// tradesPageSize is the number of trades fetched by request when trades are backfilled.
+	tradesPageSize = 1000
+)
+
+// WebsocketClient is the subset of the websocket client used by WebsocketFeed.
+type WebsocketClient interface {
+	On(event interface{}, listener interface{}) *emission.Emitter
+	Off(event interface{}, listener interface{}) *emission.Emitter
+	Subscribe(channels []string) error
+	UnSubscribe(channels []string) error
+	GetLastTradesByInstrument(
+		ctx context.Context, params *models.GetLastTradesByInstrumentParams,
+	) (models.GetLastTradesResponse, error)
+}
+
+// WebsocketFeed is the Feed of a websocket client, it subscribes to the raw books and trades and
+// to the 100ms tickers of the instruments. The client is started by the caller.
+type WebsocketFeed struct {
+	emitter
+	client WebsocketClient
+
+	mu          sync.RWMutex
+	instruments instruments
+
+	onBook   func(*models.OrderBookRawNotification)
+	onTrades func(*models.TradesNotification)
+	onTicker func(*models.TickerNotification)
+}
+
+// NewWebsocketFeed creates a new WebsocketFeed instance listening to the events of client.
+func NewWebsocketFeed(client WebsocketClient) *WebsocketFeed {
+	f := &WebsocketFeed{
+		emitter:     newEmitter(),
+		client:      client,
+		instruments: make(instruments),
+	}
+	f.onBook = f.book
+	f.onTrades = f.trades
+	f.onTicker = f.ticker
+
+	client.On("book.*."+websocketBookInterval, f.onBook)
+	client.On("trades.*."+websocketTradesInterval, f.onTrades)
+	client.On("ticker.*."+websocketTickerInterval, f.onTicker)
+	return f
+}
+
+// Close removes the listeners of the feed from the client, the subscriptions are kept.
+func (f *WebsocketFeed) Close() {
+	f.client.Off("book.*."+websocketBookInterval, f.onBook)
+	f.client.Off("trades.*."+websocketTradesInterval, f.onTrades)
+	f.client.Off("ticker.*."+websocketTickerInterval, f.onTicker)
+}
+
+func websocketChannels(names []string) []string {
+	channels := make([]string, 0, 3*len(names))
+	for _, name := range names {
+		channels = append(channels,
+			"book."+name+"."+websocketBookInterval,
+			"trades."+name+"."+websocketTradesInterval,
+			"ticker."+name+"."+websocketTickerInterval,
+		)
+	}
+	return channels
+}
+
+// Subscribe subscribes to the books, trades and tickers of instruments.
+func (f *WebsocketFeed) Subscribe(_ context.Context, names []string) error {
+	f.mu.Lock()
+	added := f.instruments.add(names)
+	f.mu.Unlock()
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := f.client.Subscribe(websocketChannels(added)); err != nil {
+		f.mu.Lock()
+		f.instruments.remove(added)
+		f.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe unsubscribes from the books, trades and tickers of instruments.
+func (f *WebsocketFeed) Unsubscribe(_ context.Context, names []string) error {
+	f.mu.Lock()
+	removed := f.instruments.remove(names)
+	f.mu.Unlock()
+	if len(removed) == 0 {
+		return nil
+	}
+	return f.client.UnSubscribe(websocketChannels(removed))
+}
+
+func (f *WebsocketFeed) subscribed(instrument string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	_, ok := f.instruments[instrument]
+	return ok
+}
+
+// book converts a raw book notification, the first one of a subscription is a snapshot
+// without PrevChangeID.
+func (f *WebsocketFeed) book(book *models.OrderBookRawNotification) {
+	if !f.subscribed(book.InstrumentName) {
+		return
+	}
+	f.Emit(BookChannel(book.InstrumentName), &Book{
+		Source:         SourceWebsocket,
+		InstrumentName: book.InstrumentName,
+		Timestamp:      book.Timestamp,
+		IsSnapshot:     book.PrevChangeID == 0,
+		PrevChangeID:   book.PrevChangeID,
+		ChangeID:       book.ChangeID,
+		Bids:           book.Bids,
+		Asks:           book.Asks,
+	})
+}
+
+func (f *WebsocketFeed) trades(trades *models.TradesNotification) {
+	for instrument, list := range splitTrades(*trades) {
+		if !f.subscribed(instrument) {
+			continue
+		}
+		f.Emit(TradesChannel(instrument), &Trades{
+			Source:         SourceWebsocket,
+			InstrumentName: instrument,
+			Trades:         list,
+		})
+	}
+}
+
+func (f *WebsocketFeed) ticker(ticker *models.TickerNotification) {
+	if !f.subscribed(ticker.InstrumentName) {
+		return
+	}
+	f.Emit(TickerChannel(ticker.InstrumentName), &Ticker{
+		Source:             SourceWebsocket,
+		TickerNotification: *ticker,
+	})
+}
+
+// TradesSince fetches the trades of an instrument from the sequence number startSeq. On error, the
+// trades of the pages fetched before are returned with it.
+func (f *WebsocketFeed) TradesSince(ctx context.Context, instrument string, startSeq uint64) ([]models.Trade, error) {
+	var trades []models.Trade
+	for {
+		resp, err := f.client.GetLastTradesByInstrument(ctx, &models.GetLastTradesByInstrumentParams{
+			InstrumentName: instrument,
+			StartSeq:       int64(startSeq),
+			Count:          tradesPageSize,
+			Sorting:        "asc",
+		})
+		if err != nil {
+			return trades, err
+		}
+		trades = append(trades, resp.Trades...)
+		if !resp.HasMore || len(resp.Trades) == 0 {
+			return trades, nil
+		}
+		startSeq = resp.Trades[len(resp.Trades)-1].TradeSeq + 1
+	}
+}
@@ -0,0 +1,11 @@
+package models
+
+type ComboLeg struct {
+	InstrumentName string `json:"instrument_name"`
+	Amount         int    `json:"amount"`
+}
+
+type ComboLegsNotification struct {
+	InstrumentName string     `json:"instrument_name"`
+	Legs           []ComboLeg `json:"legs"`
+}
@@ -1,6 +1,7 @@
 package models
 
 type GetAccountSummaryParams struct {
-	Currency string `json:"currency"`
-	Extended *bool  `json:"extended,omitempty"`
+	Currency     string `json:"currency"`
+	SubaccountID int    `json:"subaccount_id,omitempty"`
+	Extended     *bool  `json:"extended,omitempty"`
 }
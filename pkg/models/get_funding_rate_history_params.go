@@ -0,0 +1,7 @@
+package models
+
+type GetFundingRateHistoryParams struct {
+	InstrumentName string `json:"instrument_name"`
+	StartTimestamp uint64 `json:"start_timestamp"`
+	EndTimestamp   uint64 `json:"end_timestamp"`
+}
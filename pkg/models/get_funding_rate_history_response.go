@@ -0,0 +1,11 @@
+package models
+
+type FundingRateHistory struct {
+	Timestamp      uint64  `json:"timestamp"`
+	IndexPrice     float64 `json:"index_price"`
+	PrevIndexPrice float64 `json:"prev_index_price"`
+	Interest8H     float64 `json:"interest_8h"`
+	Interest1H     float64 `json:"interest_1h"`
+}
+
+type GetFundingRateHistoryResponse []FundingRateHistory
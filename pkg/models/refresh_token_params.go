@@ -0,0 +1,6 @@
+package models
+
+type RefreshTokenParams struct {
+	GrantType    string `json:"grant_type"`
+	RefreshToken string `json:"refresh_token"`
+}
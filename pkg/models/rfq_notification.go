@@ -0,0 +1,9 @@
+package models
+
+type RfqNotification struct {
+	State          bool    `json:"state"`
+	Side           string  `json:"side,omitempty"`
+	LastRfqTstamp  uint64  `json:"last_rfq_tstamp"`
+	InstrumentName string  `json:"instrument_name"`
+	Amount         float64 `json:"amount,omitempty"`
+}
@@ -0,0 +1,8 @@
+package models
+
+type SubmitTransferBetweenSubaccountsParams struct {
+	Currency    string  `json:"currency"`
+	Amount      float64 `json:"amount"`
+	Destination int     `json:"destination"`
+	Source      int     `json:"source,omitempty"`
+}
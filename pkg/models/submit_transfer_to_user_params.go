@@ -0,0 +1,8 @@
+package models
+
+type SubmitTransferToUserParams struct {
+	Currency    string  `json:"currency"`
+	Amount      float64 `json:"amount"`
+	Destination string  `json:"destination"`
+	Tfa         string  `json:"tfa,omitempty"`
+}
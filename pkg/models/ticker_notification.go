@@ -2,27 +2,42 @@ package models
 
 type Stats struct {
 	Volume      float64  `json:"volume"`
+	VolumeUSD   *float64 `json:"volume_usd,omitempty"`
 	PriceChange *float64 `json:"price_change"`
 	Low         float64  `json:"low"`
 	High        float64  `json:"high"`
 }
 
 type TickerNotification struct {
-	Timestamp       uint64   `json:"timestamp"`
-	Stats           Stats    `json:"stats"`
-	State           string   `json:"state"`
-	SettlementPrice float64  `json:"settlement_price"`
-	OpenInterest    float64  `json:"open_interest"`
-	MinPrice        float64  `json:"min_price"`
-	MaxPrice        float64  `json:"max_price"`
-	MarkPrice       float64  `json:"mark_price"`
-	LastPrice       float64  `json:"last_price"`
-	InstrumentName  string   `json:"instrument_name"`
-	IndexPrice      float64  `json:"index_price"`
-	Funding8H       float64  `json:"funding_8h"`
-	CurrentFunding  float64  `json:"current_funding"`
-	BestBidPrice    *float64 `json:"best_bid_price"`
-	BestBidAmount   float64  `json:"best_bid_amount"`
-	BestAskPrice    *float64 `json:"best_ask_price"`
-	BestAskAmount   float64  `json:"best_ask_amount"`
+	Timestamp              uint64   `json:"timestamp"`
+	Stats                  Stats    `json:"stats"`
+	State                  string   `json:"state"`
+	SettlementPrice        float64  `json:"settlement_price"`
+	OpenInterest           float64  `json:"open_interest"`
+	MinPrice               float64  `json:"min_price"`
+	MaxPrice               float64  `json:"max_price"`
+	MarkPrice              float64  `json:"mark_price"`
+	LastPrice              float64  `json:"last_price"`
+	InstrumentName         string   `json:"instrument_name"`
+	IndexPrice             float64  `json:"index_price"`
+	Funding8H              float64  `json:"funding_8h"`
+	CurrentFunding         float64  `json:"current_funding"`
+	BestBidPrice           *float64 `json:"best_bid_price"`
+	BestBidAmount          float64  `json:"best_bid_amount"`
+	BestAskPrice           *float64 `json:"best_ask_price"`
+	BestAskAmount          float64  `json:"best_ask_amount"`
+	EstimatedDeliveryPrice float64  `json:"estimated_delivery_price"`
+	// DeliveryPrice is only set once the instrument is settled.
+	DeliveryPrice *float64 `json:"delivery_price,omitempty"`
+	// InterestValue is the value used to compute the funding of perpetuals, nil for the others.
+	InterestValue *float64 `json:"interest_value,omitempty"`
+
+	// Options only, nil for futures and perpetuals.
+	Greeks          *Greeks  `json:"greeks,omitempty"`
+	MarkIV          *float64 `json:"mark_iv,omitempty"`
+	BidIV           *float64 `json:"bid_iv,omitempty"`
+	AskIV           *float64 `json:"ask_iv,omitempty"`
+	InterestRate    *float64 `json:"interest_rate,omitempty"`
+	UnderlyingIndex string   `json:"underlying_index,omitempty"`
+	UnderlyingPrice *float64 `json:"underlying_price,omitempty"`
 }
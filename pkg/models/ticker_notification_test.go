@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalOptionTicker(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{
+		"timestamp": 1662519695815,
+		"stats": {"volume_usd": 1250.5, "volume": 0.5, "price_change": null, "low": 0.01, "high": 0.02},
+		"state": "open",
+		"instrument_name": "BTC-30DEC22-20000-C",
+		"best_bid_price": null,
+		"best_ask_price": 0.0185,
+		"estimated_delivery_price": 19800.5,
+		"greeks": {"vega": 21.3, "theta": -12.1, "rho": 4.2, "gamma": 0.0001, "delta": 0.41},
+		"mark_iv": 61.8,
+		"bid_iv": 0,
+		"ask_iv": 63.2,
+		"interest_rate": 0,
+		"underlying_index": "BTC-30DEC22",
+		"underlying_price": 19850.1
+	}`)
+
+	var ticker TickerNotification
+	require.NoError(t, json.Unmarshal(data, &ticker))
+
+	require.Equal(t, 1250.5, *ticker.Stats.VolumeUSD)
+	require.Nil(t, ticker.Stats.PriceChange)
+	require.Nil(t, ticker.BestBidPrice)
+	require.Equal(t, 0.0185, *ticker.BestAskPrice)
+	require.Equal(t, 19800.5, ticker.EstimatedDeliveryPrice)
+	require.Nil(t, ticker.DeliveryPrice)
+	require.Equal(t, &Greeks{Delta: 0.41, Gamma: 0.0001, RHO: 4.2, Theta: -12.1, Vega: 21.3}, ticker.Greeks)
+	require.Equal(t, 61.8, *ticker.MarkIV)
+	require.Equal(t, 0.0, *ticker.BidIV)
+	require.Equal(t, 63.2, *ticker.AskIV)
+	require.Equal(t, 0.0, *ticker.InterestRate)
+	require.Equal(t, "BTC-30DEC22", ticker.UnderlyingIndex)
+	require.Equal(t, 19850.1, *ticker.UnderlyingPrice)
+}
+
+func TestMarshalFutureTicker(t *testing.T) {
+	t.Parallel()
+
+	// The optional fields are left out when they are not set.
+	data, err := json.Marshal(TickerNotification{InstrumentName: "BTC-PERPETUAL"})
+	require.NoError(t, err)
+	fields := []string{"greeks", "mark_iv", "bid_iv", "ask_iv", "underlying_price", "delivery_price", "interest_value"}
+	for _, field := range fields {
+		require.NotContains(t, string(data), `"`+field+`"`)
+	}
+	require.Contains(t, string(data), `"best_bid_price":null`)
+}
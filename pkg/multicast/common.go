@@ -1,6 +1,7 @@
 package multicast
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -43,6 +44,18 @@ func newSnapshotNotificationChannel(instrument string) string {
 	return "snapshot." + instrument
 }
 
+func newComboLegsNotificationChannel(instrument string) string {
+	return "combo_legs." + instrument
+}
+
+func newPriceIndexNotificationChannel(index string) string {
+	return "price_index." + index
+}
+
+func newRfqNotificationChannel(instrument string) string {
+	return "rfq." + instrument
+}
+
 func getCurrencyFromInstrument(instrument string) string {
 	return strings.Split(instrument, "-")[0]
 }
@@ -69,3 +82,11 @@ func getCurrencyFromBytesArray(array [8]byte) string {
 	}
 	return string(array[:id])
 }
+
+// getStringFromBytes returns the characters of a null-padded char array.
+func getStringFromBytes(array []byte) string {
+	if i := bytes.IndexByte(array, 0); i >= 0 {
+		return string(array[:i])
+	}
+	return string(array)
+}
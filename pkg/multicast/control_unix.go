@@ -19,3 +19,27 @@ func Control(network, address string, c syscall.RawConn) error {
 		}
 	})
 }
+
+// ReceiveControl returns a Control which also sets the receive buffer of the socket to receiveBufferSize
+// bytes when it is positive, and enables the kernel receive timestamps and drop counters where supported.
+func ReceiveControl(receiveBufferSize int) func(network, address string, c syscall.RawConn) error {
+	return func(network, address string, c syscall.RawConn) error {
+		if err := Control(network, address, c); err != nil {
+			return err
+		}
+
+		var sockErr error
+		err := c.Control(func(fd uintptr) {
+			if receiveBufferSize > 0 {
+				if sockErr = setReceiveBuffer(int(fd), receiveBufferSize); sockErr != nil {
+					return
+				}
+			}
+			sockErr = enableReceiveInfo(int(fd))
+		})
+		if err != nil {
+			return err
+		}
+		return sockErr
+	}
+}
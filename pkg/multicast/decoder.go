@@ -0,0 +1,301 @@
+package multicast
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast/sbe"
+)
+
+// decoder decodes UDP packages in place. The SBE messages are decoded from the package bytes into
+// structs reused from one package to the next, so decoding only allocates the events themselves.
+// A decoder must not be used concurrently.
+type decoder struct {
+	d             sbe.SbeGoDecoder
+	events        []Event
+	instrumentIDs []uint32  // of the events, zero for the events without an instrument
+	receivedAt    time.Time // of the package, zero if unknown
+
+	header       sbe.MessageHeader
+	instrument   sbe.Instrument
+	instrumentV2 sbe.InstrumentV2
+	book         sbe.Book
+	trades       sbe.Trades
+	ticker       sbe.Ticker
+	snapshot     sbe.Snapshot
+	start        sbe.SnapshotStart
+	end          sbe.SnapshotEnd
+	comboLegs    sbe.ComboLegs
+	priceIndex   sbe.PriceIndex
+	rfq          sbe.Rfq
+}
+
+func newDecoder() *decoder {
+	return &decoder{}
+}
+
+// handleBytes is Handle decoding from a byte slice, see Handle.
+func (c *Client) handleBytes(
+	dec *decoder,
+	data []byte,
+	chanelIDSeq map[uint16]uint32,
+	bookChangesMap map[string][]sbe.BookChangesList,
+	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
+) error {
+	dec.d.Reset(data)
+
+	// Package header: size, channel ID and sequence number.
+	_ = dec.d.Uint16()
+	channelID := dec.d.Uint16()
+	seq := dec.d.Uint32()
+	if err := dec.d.Err(); err != nil {
+		atomic.AddUint64(&c.stats.malformed, 1)
+		c.log.Errorw("failed to decode events", "err", err)
+		return err
+	}
+
+	err := c.checkPackageSeq(channelID, seq, chanelIDSeq)
+	if err != nil {
+		if errors.Is(err, ErrDuplicatedPackage) {
+			return nil
+		}
+		c.log.Errorw("failed to handle package header", "err", err)
+		return err
+	}
+
+	events, err := c.decodeEventsBytes(dec, bookChangesMap, snapshotLevelsMap)
+	if err != nil {
+		atomic.AddUint64(&c.stats.channel(channelID).decodeErrors, 1)
+		c.log.Errorw("failed to decode events", "err", err)
+		return err
+	}
+
+	c.emitResolvedEvents(events, dec.instrumentIDs)
+	return nil
+}
+
+// emitResolvedEvents emits the events once their instrument is known. The events of an instrument
+// missing from the registry, e.g. just listed, wait for it to be fetched while the others are
+// emitted right away, so the order is kept per instrument.
+func (c *Client) emitResolvedEvents(events []Event, instrumentIDs []uint32) {
+	for i, event := range events {
+		id := instrumentIDs[i]
+		if id == 0 {
+			c.emitEvent(event)
+			continue
+		}
+
+		event := event
+		c.instruments.Resolve(id, func(ins models.Instrument, ok bool) {
+			if !ok {
+				c.log.Warnw("Drop event of unknown instrument", "instrument_id", id, "type", event.Type)
+				return
+			}
+			c.emitEvent(withInstrument(event, ins))
+		})
+	}
+}
+
+// withInstrument sets the instrument of an event converted before the instrument was known.
+func withInstrument(event Event, ins models.Instrument) Event {
+	switch data := event.Data.(type) {
+	case models.OrderBookRawNotification:
+		data.InstrumentName = ins.InstrumentName
+		event.Data = data
+	case models.TradesNotification:
+		for i := range data {
+			data[i].InstrumentName = ins.InstrumentName
+			data[i].InstrumentKind = ins.Kind
+		}
+	case models.TickerNotification:
+		data.InstrumentName = ins.InstrumentName
+		event.Data = data
+	case models.ComboLegsNotification:
+		data.InstrumentName = ins.InstrumentName
+		event.Data = data
+	case models.RfqNotification:
+		data.InstrumentName = ins.InstrumentName
+		event.Data = data
+	}
+	return event
+}
+
+// decodeEventsBytes decodes the remaining bytes of the decoder into a list of events.
+// The list is reused by the next call.
+func (c *Client) decodeEventsBytes(
+	dec *decoder,
+	bookChangesMap map[string][]sbe.BookChangesList,
+	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
+) ([]Event, error) {
+	dec.events = dec.events[:0]
+	dec.instrumentIDs = dec.instrumentIDs[:0]
+	for {
+		err := dec.header.DecodeBytes(&dec.d)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return dec.events, nil
+			}
+			return nil, err
+		}
+
+		event, err := c.decodeEventBytes(dec, dec.header, bookChangesMap, snapshotLevelsMap)
+		if err != nil {
+			if errors.Is(err, ErrEventWithoutIsLast) {
+				continue
+			}
+			if errors.Is(err, ErrUnsupportedTemplateID) {
+				c.stats.unsupportedTemplate(dec.header.TemplateId)
+				c.log.Debugw("Ignore unsupported event", "error", err)
+				continue
+			}
+			return nil, err
+		}
+		dec.events = append(dec.events, event)
+		dec.instrumentIDs = append(dec.instrumentIDs, dec.instrumentID(dec.header.TemplateId))
+		if ts := dec.timestampMs(dec.header.TemplateId); ts != 0 && !dec.receivedAt.IsZero() {
+			c.stats.observeLatency(event.Type, c.Latency(ts, dec.receivedAt))
+		}
+	}
+}
+
+// timestampMs returns the exchange timestamp of the message just decoded, zero if it has none.
+// The timestamp of trades is the one of the last trade.
+func (dec *decoder) timestampMs(templateID uint16) uint64 {
+	switch templateID {
+	case 1001:
+		return dec.book.TimestampMs
+	case 1002:
+		if n := len(dec.trades.TradesList); n > 0 {
+			return dec.trades.TradesList[n-1].TimestampMs
+		}
+	case 1003:
+		return dec.ticker.TimestampMs
+	case 1004:
+		return dec.snapshot.TimestampMs
+	case 1005:
+		return dec.start.TimestampMs
+	case 1006:
+		return dec.end.TimestampMs
+	case 1008:
+		return dec.priceIndex.TimestampMs
+	case 1009:
+		return dec.rfq.TimestampMs
+	}
+	return 0
+}
+
+// instrumentID returns the instrument of the message just decoded, zero if it has none.
+// The instrument events are not included, they are what updates the registry.
+func (dec *decoder) instrumentID(templateID uint16) uint32 {
+	switch templateID {
+	case 1001:
+		return dec.book.InstrumentId
+	case 1002:
+		return dec.trades.InstrumentId
+	case 1003:
+		return dec.ticker.InstrumentId
+	case 1004:
+		return dec.snapshot.InstrumentId
+	case 1007:
+		return dec.comboLegs.InstrumentId
+	case 1009:
+		return dec.rfq.InstrumentId
+	}
+	return 0
+}
+
+//nolint:cyclop
+func (c *Client) decodeEventBytes(
+	dec *decoder,
+	header sbe.MessageHeader,
+	bookChangesMap map[string][]sbe.BookChangesList,
+	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
+) (Event, error) {
+	d, version, blockLength := &dec.d, header.Version, header.BlockLength
+
+	var err error
+	switch header.TemplateId {
+	case 1000:
+		if err = dec.instrument.DecodeBytes(d, version, blockLength, true); err == nil {
+			return instrumentEvent(&dec.instrument), nil
+		}
+	case 1001:
+		if err = dec.book.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.orderBookEvent(&dec.book, bookChangesMap)
+		}
+	case 1002:
+		if err = dec.trades.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.tradesEvent(&dec.trades), nil
+		}
+	case 1003:
+		if err = dec.ticker.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.tickerEvent(&dec.ticker), nil
+		}
+	case 1004:
+		if err = dec.snapshot.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.snapshotEvent(&dec.snapshot, snapshotLevelsMap)
+		}
+	case 1005:
+		if err = dec.start.DecodeBytes(d, version, blockLength, true); err == nil {
+			return snapshotFrameEvent(EventTypeSnapshotStart, dec.start.SnapshotId, dec.start.TimestampMs), nil
+		}
+	case 1006:
+		if err = dec.end.DecodeBytes(d, version, blockLength, true); err == nil {
+			return snapshotFrameEvent(EventTypeSnapshotEnd, dec.end.SnapshotId, dec.end.TimestampMs), nil
+		}
+	case 1007:
+		if err = dec.comboLegs.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.comboLegsEvent(&dec.comboLegs), nil
+		}
+	case 1008:
+		if err = dec.priceIndex.DecodeBytes(d, version, blockLength, true); err == nil {
+			return priceIndexEvent(&dec.priceIndex), nil
+		}
+	case 1009:
+		if err = dec.rfq.DecodeBytes(d, version, blockLength, true); err == nil {
+			return c.rfqEvent(&dec.rfq), nil
+		}
+	case 1010:
+		if err = dec.instrumentV2.DecodeBytes(d, version, blockLength, true); err == nil {
+			return instrumentV2Event(&dec.instrumentV2), nil
+		}
+	default:
+		skipUnsupportedEvent(d, header)
+		if err = d.Err(); err == nil {
+			return Event{}, fmt.Errorf("%w, templateId: %d", ErrUnsupportedTemplateID, header.TemplateId)
+		}
+	}
+
+	c.log.Errorw("failed to decode event", "templateId", header.TemplateId, "err", err)
+	return Event{}, err
+}
+
+// skipUnsupportedEvent is decodeUnsupportedEvent for a byte slice.
+func skipUnsupportedEvent(d *sbe.SbeGoDecoder, header sbe.MessageHeader) {
+	d.Skip(int(header.BlockLength))
+	skipGroups(d, header.NumGroups)
+	skipVars(d, header.NumVarDataFields)
+}
+
+func skipGroups(d *sbe.SbeGoDecoder, numGroups uint16) {
+	for i := uint16(0); i < numGroups && d.Err() == nil; i++ {
+		blockLength := d.Uint16()
+		numInGroup := d.Uint16()
+		numSubGroups := d.Uint16()
+		numVars := d.Uint16()
+
+		d.Skip(int(numInGroup) * int(blockLength))
+		skipGroups(d, numSubGroups)
+		skipVars(d, numVars)
+	}
+}
+
+func skipVars(d *sbe.SbeGoDecoder, numVars uint16) {
+	for i := uint16(0); i < numVars && d.Err() == nil; i++ {
+		d.Skip(int(d.Uint8()))
+	}
+}
@@ -0,0 +1,150 @@
+package multicast
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/multicast/sbe"
+)
+
+// A package with a book event of BTC-PERPETUAL and a trades event of ETH-PERPETUAL.
+var testPackage = []byte{
+	0x00, 0x00, 0xe9, 0x03, 0x01, 0x00, 0x00, 0x00,
+	// book
+	0x1d, 0x00, 0xe9, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+	0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00, 0x3c, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00,
+	0x3d, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x12, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x60, 0x4e, 0xd3, 0x40, 0x00, 0x00, 0x00, 0x00, 0xc0,
+	0x4f, 0xed, 0x40,
+	// trades
+	0x04, 0x00, 0xea, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x48, 0x37, 0x03, 0x00,
+	0x53, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x9a, 0x99, 0x99, 0x99, 0x99, 0xe3, 0x99,
+	0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xa6, 0x40, 0xc6, 0x17, 0xfd, 0x11, 0x83, 0x01, 0x00,
+	0x00, 0x1f, 0x85, 0xeb, 0x51, 0xb8, 0xe2, 0x99, 0x40, 0x1f, 0x85, 0xeb, 0x51, 0xb8, 0xe7, 0x99,
+	0x40, 0xf6, 0xbe, 0x4d, 0x06, 0x00, 0x00, 0x00, 0x00, 0x5b, 0xa2, 0x84, 0x08, 0x00, 0x00, 0x00,
+	0x00, 0x01, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+}
+
+func (ts *MulticastTestSuite) TestDecodeEventsBytesSkipsUnsupportedEvent() {
+	require := ts.Require()
+
+	event := append([]byte{
+		// templateId 2000 with a 4 bytes block, a group of 2 entries and a var data field.
+		0x04, 0x00, 0xd0, 0x07, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x02, 0x03, 0x04,
+		0x02, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x06, 0x07, 0x08, 0x03, 0x61, 0x62, 0x63,
+	}, testPackage[8:]...)
+
+	bookChangesMap := make(map[string][]sbe.BookChangesList)
+	snapshotLevelMap := make(map[string][]sbe.SnapshotLevelsList)
+	expected, err := ts.c.decodeEvents(ts.m, bytes.NewBuffer(event), bookChangesMap, snapshotLevelMap)
+	require.NoError(err)
+	require.Len(expected, 2)
+
+	dec := newDecoder()
+	dec.d.Reset(event)
+	events, err := ts.c.decodeEventsBytes(dec, bookChangesMap, snapshotLevelMap)
+	require.NoError(err)
+	require.Equal(expected[0], events[0])
+	require.Len(events, 2)
+	require.Equal(EventTypeTrades, events[1].Type)
+
+	// A truncated unsupported event is an error.
+	dec.d.Reset(event[:20])
+	_, err = ts.c.decodeEventsBytes(dec, bookChangesMap, snapshotLevelMap)
+	require.Error(err)
+}
+
+func (ts *MulticastTestSuite) TestHandleBytesUnknownInstrument() {
+	require := ts.Require()
+
+	// BTC-PERPETUAL is missing from the registry, as if it was just listed.
+	c, err := NewClient("", nil, &MockInstrumentsGetter{}, []string{"BTC"})
+	require.NoError(err)
+	for _, ins := range ts.insMap {
+		if ins.InstrumentName != "BTC-PERPETUAL" {
+			c.setInstrument(ins)
+		}
+	}
+
+	var mu sync.Mutex
+	var received []string
+	c.On(newOrderBookNotificationChannel("BTC-PERPETUAL"), func(b *models.OrderBookRawNotification) {
+		mu.Lock()
+		received = append(received, "book."+b.InstrumentName)
+		mu.Unlock()
+	})
+	c.On(newTradesNotificationChannel("future", "ETH"), func(t *models.TradesNotification) {
+		mu.Lock()
+		received = append(received, "trades."+(*t)[0].InstrumentName)
+		mu.Unlock()
+	})
+
+	dec := newDecoder()
+	bookChangesMap := make(map[string][]sbe.BookChangesList)
+	snapshotLevelMap := make(map[string][]sbe.SnapshotLevelsList)
+	require.NoError(c.handleBytes(dec, testPackage, make(map[uint16]uint32), bookChangesMap, snapshotLevelMap))
+
+	// The book waits for BTC-PERPETUAL to be fetched, the trades are not delayed.
+	mu.Lock()
+	require.Contains(received, "trades.ETH-PERPETUAL")
+	mu.Unlock()
+	require.Eventually(func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, time.Second, time.Millisecond)
+	require.ElementsMatch([]string{"trades.ETH-PERPETUAL", "book.BTC-PERPETUAL"}, received)
+
+	ins, ok := c.Instruments().GetByName("BTC-PERPETUAL")
+	require.True(ok)
+	require.Equal(ts.insMap[ins.InstrumentID], ins)
+}
+
+func newBenchmarkClient(b *testing.B) *Client {
+	b.Helper()
+
+	c, err := NewClient("", nil, &MockInstrumentsGetter{}, []string{"BTC", "ETH"})
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := c.buildInstrumentsMapping(); err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+func BenchmarkDecodeEvents(b *testing.B) {
+	c := newBenchmarkClient(b)
+	m := sbe.NewSbeGoMarshaller()
+	bookChangesMap := make(map[string][]sbe.BookChangesList)
+	snapshotLevelsMap := make(map[string][]sbe.SnapshotLevelsList)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(testPackage)))
+	for i := 0; i < b.N; i++ {
+		buf := bytes.NewBuffer(testPackage[8:])
+		if _, err := c.decodeEvents(m, buf, bookChangesMap, snapshotLevelsMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeEventsBytes(b *testing.B) {
+	c := newBenchmarkClient(b)
+	dec := newDecoder()
+	bookChangesMap := make(map[string][]sbe.BookChangesList)
+	snapshotLevelsMap := make(map[string][]sbe.SnapshotLevelsList)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(testPackage)))
+	for i := 0; i < b.N; i++ {
+		dec.d.Reset(testPackage[8:])
+		if _, err := c.decodeEventsBytes(dec, bookChangesMap, snapshotLevelsMap); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package multicast
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"errors"
@@ -13,9 +12,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/KyberNetwork/deribit-api/pkg/common"
+	"github.com/KyberNetwork/deribit-api/pkg/instruments"
 	"github.com/KyberNetwork/deribit-api/pkg/models"
 	"github.com/KyberNetwork/deribit-api/pkg/multicast/sbe"
+	"github.com/KyberNetwork/deribit-api/pkg/router"
 	"github.com/chuckpreslar/emission"
 	"go.uber.org/zap"
 	"golang.org/x/net/ipv4"
@@ -26,6 +30,10 @@ const (
 	defaultDataChSize = 1000
 
 	RestartEventChannel = "multicast.restart"
+
+	// SnapshotStartChannel and SnapshotEndChannel receive the *SnapshotFrame bounding a snapshot cycle.
+	SnapshotStartChannel = "snapshot_start"
+	SnapshotEndChannel   = "snapshot_end"
 )
 
 type EventType int
@@ -37,6 +45,11 @@ const (
 	EventTypeTrades
 	EventTypeTicker
 	EventTypeSnapshot
+	EventTypeSnapshotStart
+	EventTypeSnapshotEnd
+	EventTypeComboLegs
+	EventTypePriceIndex
+	EventTypeRfq
 )
 
 // Event represents a Deribit multicast events.
@@ -45,21 +58,58 @@ type Event struct {
 	Data interface{}
 }
 
+// SnapshotFrame is the start or the end of a snapshot cycle, the snapshots of all the instruments
+// are sent between them.
+type SnapshotFrame struct {
+	SnapshotID uint64
+	Timestamp  uint64
+}
+
+// InstrumentsGetter fetches instruments, e.g. websocket.Client, rest.Client or fix.Client.
 type InstrumentsGetter interface {
 	GetInstruments(ctx context.Context, params *models.GetInstrumentsParams) ([]models.Instrument, error)
 }
 
+// Clock converts local times to Deribit server time, e.g. clocksync.Clock.
+type Clock interface {
+	ToServerTime(t time.Time) time.Time
+}
+
+// clockValue wraps a Clock so that atomic.Value always stores the same type.
+type clockValue struct {
+	Clock
+}
+
 // Client represents a client for Deribit multicast.
 type Client struct {
-	log               *zap.SugaredLogger
-	inf               *net.Interface
-	addrs             []string
-	connMap           map[int]*ipv4.PacketConn // port: packetConnection
-	instrumentsGetter InstrumentsGetter
-
-	supportCurrencies []string
-	instrumentsMap    map[uint32]models.Instrument
-	emitter           *emission.Emitter
+	receivedAt int64 // UnixNano of the package being handled, first for the alignment of atomic operations
+
+	log     *zap.SugaredLogger
+	inf     *net.Interface
+	addrs   []string
+	connMap map[int]*ipv4.PacketConn // port: packetConnection
+
+	instruments *instruments.Registry
+	emitter     *emission.Emitter
+	router      *router.Router
+	clock       atomic.Value // clockValue, loaded by the decode workers
+
+	readBatchSize     int
+	receiveBufferSize int
+	decodeWorkers     int
+	watchdog          WatchdogConfig
+	stats             *stats
+
+	mu           sync.RWMutex          // guards receiveStats, dispatcher and the session below
+	receiveStats map[int]*receiveStats // by port
+	dispatcher   *dispatcher
+	// generation counts the sessions started by ListenToEvents, restartCh is read by the supervisor
+	// of the current one and workersDone is closed once its decode workers have returned.
+	generation  uint64
+	restartCh   chan struct{}
+	workersDone chan struct{}
+
+	restartMu sync.Mutex
 }
 
 // NewClient creates a new Client instance.
@@ -81,52 +131,110 @@ func NewClient(
 	}
 
 	client = &Client{
-		log:               log,
-		inf:               inf,
-		addrs:             addrs,
-		instrumentsGetter: instrumentsGetter,
+		log:   log,
+		inf:   inf,
+		addrs: addrs,
+
+		instruments: instruments.New(instrumentsGetter, instruments.Config{Currencies: currencies}),
+		emitter:     emission.NewEmitter(),
+		router:      router.New(),
 
-		supportCurrencies: currencies,
-		instrumentsMap:    make(map[uint32]models.Instrument),
-		emitter:           emission.NewEmitter(),
+		readBatchSize: defaultReadBatchSize,
+		decodeWorkers: defaultDecodeWorkers,
+		stats:         newStats(),
+		receiveStats:  make(map[int]*receiveStats),
 	}
 
 	return client, nil
 }
 
+// Instruments returns the registry of the instruments of the supported currencies. It is loaded by
+// Start and updated from the instrument events, the events of unknown instruments wait for them to
+// be fetched.
+func (c *Client) Instruments() *instruments.Registry {
+	return c.instruments
+}
+
+// SetClock sets the clock used to convert receive times to server time, it can be called
+// while the client is running.
+func (c *Client) SetClock(clock Clock) {
+	c.clock.Store(clockValue{clock})
+}
+
+// SetReadBatchSize sets the maximum number of datagrams read from a socket at once with recvmmsg,
+// it applies to the connections opened by the next Start.
+func (c *Client) SetReadBatchSize(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.readBatchSize = n
+}
+
+// SetReceiveBufferSize sets the receive buffer size of the sockets in bytes, it applies to
+// the connections opened by the next Start. The system default is kept if size is not positive.
+func (c *Client) SetReceiveBufferSize(size int) {
+	c.receiveBufferSize = size
+}
+
+// SetDecodeWorkers sets the number of goroutines decoding the packages, it applies to the next Start.
+// The packages are sharded over the workers by channel ID, so the events of a channel are emitted
+// in order by a single worker while the channels are decoded in parallel.
+func (c *Client) SetDecodeWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	c.decodeWorkers = n
+}
+
+// WorkerStats returns the queue depths and counters of the decode workers, nil before Start.
+func (c *Client) WorkerStats() []WorkerStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.dispatcher == nil {
+		return nil
+	}
+	return c.dispatcher.stats()
+}
+
+// ReceiveStats returns the receive counters of the sockets by port.
+func (c *Client) ReceiveStats() map[int]ReceiveStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make(map[int]ReceiveStats, len(c.receiveStats))
+	for port, stats := range c.receiveStats {
+		result[port] = stats.load()
+	}
+	return result
+}
+
+// ReceivedAt returns the kernel receive time of the UDP package being handled. Listeners can call it
+// to tell the network latency, Latency(timestamp, ReceivedAt()), from the processing latency,
+// time.Since(ReceivedAt()). With several decode workers it is the package handled last by any of them.
+func (c *Client) ReceivedAt() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.receivedAt))
+}
+
+// Latency returns the time between the exchange timestamp of an event and
+// the local time it was received, using the server clock offset if a Clock is set.
+func (c *Client) Latency(exchangeTimestampMs uint64, receivedAt time.Time) time.Duration {
+	if clock, ok := c.clock.Load().(clockValue); ok && clock.Clock != nil {
+		receivedAt = clock.ToServerTime(receivedAt)
+	}
+	return receivedAt.Sub(time.UnixMilli(int64(exchangeTimestampMs)))
+}
+
 // buildInstrumentsMapping builds a mapping to map instrument id to instrument.
 func (c *Client) buildInstrumentsMapping() error {
-	// need to update this field via instruments_update event
-	instruments, err := getAllInstrument(c.instrumentsGetter, c.supportCurrencies)
+	err := c.instruments.Load(context.Background())
 	if err != nil {
 		c.log.Errorw("failed to get all instruments", "err", err)
 		return err
 	}
-	for _, instrument := range instruments {
-		c.instrumentsMap[instrument.InstrumentID] = instrument
-	}
 	return nil
 }
 
-// getAllInstrument returns a list of all instruments by currencies
-func getAllInstrument(
-	instrumentsGetter InstrumentsGetter, currencies []string,
-) ([]models.Instrument, error) {
-	result := make([]models.Instrument, 0)
-	for _, currency := range currencies {
-		ins, err := instrumentsGetter.GetInstruments(
-			context.Background(),
-			&models.GetInstrumentsParams{
-				Currency: currency,
-			})
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, ins...)
-	}
-	return result, nil
-}
-
 // decodeEvents decodes a UDP package into a list of events.
 func (c *Client) decodeEvents(
 	marshaller *sbe.SbeGoMarshaller, reader io.Reader,
@@ -151,6 +259,7 @@ func (c *Client) decodeEvents(
 				continue
 			}
 			if errors.Is(err, ErrUnsupportedTemplateID) {
+				c.stats.unsupportedTemplate(header.TemplateId)
 				c.log.Debugw("Ignore unsupported event", "error", err)
 				continue
 			}
@@ -178,6 +287,16 @@ func (c *Client) decodeEvent(
 		return c.decodeTickerEvent(marshaller, reader, header)
 	case 1004:
 		return c.decodeSnapshotEvent(marshaller, reader, header, snapshotLevelsMap)
+	case 1005:
+		return c.decodeSnapshotStartEvent(marshaller, reader, header)
+	case 1006:
+		return c.decodeSnapshotEndEvent(marshaller, reader, header)
+	case 1007:
+		return c.decodeComboLegsEvent(marshaller, reader, header)
+	case 1008:
+		return c.decodePriceIndexEvent(marshaller, reader, header)
+	case 1009:
+		return c.decodeRfqEvent(marshaller, reader, header)
 	case 1010:
 		return c.decodeInstrumentV2Event(marshaller, reader, header)
 	default:
@@ -195,12 +314,16 @@ func (c *Client) decodeInstrumentEvent(
 	header sbe.MessageHeader,
 ) (Event, error) {
 	var ins sbe.Instrument
-	err := ins.Decode(marshaller, reader, header.BlockLength, true)
+	err := ins.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode instrument event", "err", err)
 		return Event{}, err
 	}
 
+	return instrumentEvent(&ins), nil
+}
+
+func instrumentEvent(ins *sbe.Instrument) Event {
 	instrument := models.Instrument{
 		TickSize:             ins.TickSize,
 		TakerCommission:      ins.TakerCommission,
@@ -225,7 +348,7 @@ func (c *Client) decodeInstrumentEvent(
 	return Event{
 		Type: EventTypeInstrument,
 		Data: instrument,
-	}, nil
+	}
 }
 
 func (c *Client) decodeInstrumentV2Event(
@@ -234,12 +357,16 @@ func (c *Client) decodeInstrumentV2Event(
 	header sbe.MessageHeader,
 ) (Event, error) {
 	var ins sbe.InstrumentV2
-	err := ins.Decode(marshaller, reader, header.BlockLength, true)
+	err := ins.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode instrument event", "err", err)
 		return Event{}, err
 	}
 
+	return instrumentV2Event(&ins), nil
+}
+
+func instrumentV2Event(ins *sbe.InstrumentV2) Event {
 	tickSizeSteps := make([]models.TickSizeStep, 0, len(ins.TickStepsList))
 	for _, step := range ins.TickStepsList {
 		tickSizeSteps = append(tickSizeSteps, models.TickSizeStep{
@@ -273,7 +400,7 @@ func (c *Client) decodeInstrumentV2Event(
 	return Event{
 		Type: EventTypeInstrument,
 		Data: instrument,
-	}, nil
+	}
 }
 
 //nolint:dupl
@@ -284,30 +411,38 @@ func (c *Client) decodeOrderBookEvent(
 	bookChangesMap map[string][]sbe.BookChangesList,
 ) (Event, error) {
 	var book sbe.Book
-	err := book.Decode(marshaller, reader, header.BlockLength, true)
+	err := book.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode orderbook event", "err", err)
 		return Event{}, err
 	}
 
+	return c.orderBookEvent(&book, bookChangesMap)
+}
+
+// orderBookEvent converts a book message, the changes of a book split into several messages
+// are buffered in bookChangesMap until the one with isLast=Yes.
+func (c *Client) orderBookEvent(book *sbe.Book, bookChangesMap map[string][]sbe.BookChangesList) (Event, error) {
 	instrumentName := c.getInstrument(book.InstrumentId).InstrumentName
-	key := instrumentName + strconv.FormatUint(book.ChangeId, 10)
+	key := splitKey(book.InstrumentId, book.ChangeId)
 
 	if book.IsLast == sbe.YesNo.No {
 		c.log.Infow("Received multicast orderbook with isLast=No",
 			"instrument", instrumentName,
-			"multicast_orderbook", book,
+			"multicast_orderbook", *book,
 		)
-		changeList, ok := bookChangesMap[key]
-		if ok {
-			bookChangesMap[key] = append(changeList, book.ChangesList...)
-		} else {
-			bookChangesMap[key] = book.ChangesList
-		}
+		// Copy the changes, the book may be reused by the next message.
+		bookChangesMap[key] = append(bookChangesMap[key], book.ChangesList...)
 		return Event{}, fmt.Errorf("orderbook: %w", ErrEventWithoutIsLast)
 	}
 
-	return parseSbeBookToEvent(instrumentName, book, bookChangesMap, key), nil
+	return parseSbeBookToEvent(instrumentName, *book, bookChangesMap, key), nil
+}
+
+// splitKey is the key of the parts of a book or snapshot split into several messages. It uses
+// the instrument ID, the name is not known yet when the instrument was just listed.
+func splitKey(instrumentID uint32, changeID uint64) string {
+	return strconv.FormatUint(uint64(instrumentID), 10) + "." + strconv.FormatUint(changeID, 10)
 }
 
 func parseSbeBookToEvent(
@@ -353,12 +488,16 @@ func (c *Client) decodeTradesEvent(
 	header sbe.MessageHeader,
 ) (Event, error) {
 	var trades sbe.Trades
-	err := trades.Decode(marshaller, reader, header.BlockLength, true)
+	err := trades.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode trades event", "err", err)
 		return Event{}, err
 	}
 
+	return c.tradesEvent(&trades), nil
+}
+
+func (c *Client) tradesEvent(trades *sbe.Trades) Event {
 	ins := c.getInstrument(trades.InstrumentId)
 
 	tradesEvent := make(models.TradesNotification, len(trades.TradesList))
@@ -384,7 +523,7 @@ func (c *Client) decodeTradesEvent(
 	return Event{
 		Type: EventTypeTrades,
 		Data: tradesEvent,
-	}, nil
+	}
 }
 
 func (c *Client) decodeTickerEvent(
@@ -393,38 +532,46 @@ func (c *Client) decodeTickerEvent(
 	header sbe.MessageHeader,
 ) (Event, error) {
 	var ticker sbe.Ticker
-	err := ticker.Decode(marshaller, reader, header.BlockLength, true)
+	err := ticker.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode ticker event", "err", err)
 		return Event{}, err
 	}
 
+	return c.tickerEvent(&ticker), nil
+}
+
+// tickerEvent converts a ticker message. The optional fields are NaN when absent, they are zeroed,
+// or left nil for the nullable ones. The multicast ticker carries neither the 24h stats nor the
+// greeks and implied volatilities of the options, they are left empty.
+func (c *Client) tickerEvent(ticker *sbe.Ticker) Event {
 	instrumentName := c.getInstrument(ticker.InstrumentId).InstrumentName
 
 	event := models.TickerNotification{
-		Timestamp:       ticker.TimestampMs,
-		Stats:           models.Stats{},
-		State:           ticker.InstrumentState.String(),
-		SettlementPrice: ticker.SettlementPrice,
-		OpenInterest:    ticker.OpenInterest,
-		MinPrice:        ticker.MinSellPrice,
-		MaxPrice:        ticker.MaxBuyPrice,
-		MarkPrice:       ticker.MarkPrice,
-		LastPrice:       ticker.LastPrice,
-		InstrumentName:  instrumentName,
-		IndexPrice:      ticker.IndexPrice,
-		Funding8H:       ticker.Funding8h,
-		CurrentFunding:  ticker.CurrentFunding,
-		BestBidPrice:    &ticker.BestBidPrice,
-		BestBidAmount:   ticker.BestBidAmount,
-		BestAskPrice:    &ticker.BestAskPrice,
-		BestAskAmount:   ticker.BestAskAmount,
+		Timestamp:              ticker.TimestampMs,
+		State:                  ticker.InstrumentState.String(),
+		SettlementPrice:        common.NaNToZero(ticker.SettlementPrice),
+		OpenInterest:           ticker.OpenInterest,
+		MinPrice:               ticker.MinSellPrice,
+		MaxPrice:               ticker.MaxBuyPrice,
+		MarkPrice:              ticker.MarkPrice,
+		LastPrice:              common.NaNToZero(ticker.LastPrice),
+		InstrumentName:         instrumentName,
+		IndexPrice:             ticker.IndexPrice,
+		Funding8H:              common.NaNToZero(ticker.Funding8h),
+		CurrentFunding:         common.NaNToZero(ticker.CurrentFunding),
+		BestBidPrice:           common.Nullable(ticker.BestBidPrice),
+		BestBidAmount:          ticker.BestBidAmount,
+		BestAskPrice:           common.Nullable(ticker.BestAskPrice),
+		BestAskAmount:          ticker.BestAskAmount,
+		EstimatedDeliveryPrice: common.NaNToZero(ticker.EstimatedDeliveryPrice),
+		DeliveryPrice:          common.Nullable(ticker.DeliveryPrice),
 	}
 
 	return Event{
 		Type: EventTypeTicker,
 		Data: event,
-	}, nil
+	}
 }
 
 //nolint:dupl
@@ -435,30 +582,161 @@ func (c *Client) decodeSnapshotEvent(
 	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
 ) (Event, error) {
 	var snapshot sbe.Snapshot
-	err := snapshot.Decode(marshaller, reader, header.BlockLength, true)
+	err := snapshot.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
 	if err != nil {
 		c.log.Errorw("failed to decode snapshot event", "err", err)
 		return Event{}, err
 	}
 
+	return c.snapshotEvent(&snapshot, snapshotLevelsMap)
+}
+
+// snapshotEvent converts a snapshot message, the levels of a book split into several messages
+// are buffered in snapshotLevelsMap until the one with isLastInBook=Yes.
+func (c *Client) snapshotEvent(
+	snapshot *sbe.Snapshot, snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
+) (Event, error) {
 	instrumentName := c.getInstrument(snapshot.InstrumentId).InstrumentName
-	key := instrumentName + strconv.FormatUint(snapshot.ChangeId, 10)
+	key := splitKey(snapshot.InstrumentId, snapshot.ChangeId)
 
 	if snapshot.IsLastInBook == sbe.YesNo.No {
 		c.log.Infow("Received multicast snapshot with IsLastInBook=No",
 			"instrument", instrumentName,
-			"snapshot", snapshot,
+			"snapshot", *snapshot,
 		)
-		levelsList, ok := snapshotLevelsMap[key]
-		if ok {
-			snapshotLevelsMap[key] = append(levelsList, snapshot.LevelsList...)
-		} else {
-			snapshotLevelsMap[key] = snapshot.LevelsList
-		}
+		// Copy the levels, the snapshot may be reused by the next message.
+		snapshotLevelsMap[key] = append(snapshotLevelsMap[key], snapshot.LevelsList...)
 		return Event{}, fmt.Errorf("snapshot: %w", ErrEventWithoutIsLast)
 	}
 
-	return parseSbeSnapshotToEvent(instrumentName, snapshot, snapshotLevelsMap, key), nil
+	return parseSbeSnapshotToEvent(instrumentName, *snapshot, snapshotLevelsMap, key), nil
+}
+
+func (c *Client) decodeSnapshotStartEvent(
+	marshaller *sbe.SbeGoMarshaller,
+	reader io.Reader,
+	header sbe.MessageHeader,
+) (Event, error) {
+	var start sbe.SnapshotStart
+	err := start.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
+	if err != nil {
+		c.log.Errorw("failed to decode snapshot start event", "err", err)
+		return Event{}, err
+	}
+
+	return snapshotFrameEvent(EventTypeSnapshotStart, start.SnapshotId, start.TimestampMs), nil
+}
+
+func (c *Client) decodeSnapshotEndEvent(
+	marshaller *sbe.SbeGoMarshaller,
+	reader io.Reader,
+	header sbe.MessageHeader,
+) (Event, error) {
+	var end sbe.SnapshotEnd
+	err := end.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
+	if err != nil {
+		c.log.Errorw("failed to decode snapshot end event", "err", err)
+		return Event{}, err
+	}
+
+	return snapshotFrameEvent(EventTypeSnapshotEnd, end.SnapshotId, end.TimestampMs), nil
+}
+
+func snapshotFrameEvent(eventType EventType, snapshotID, timestamp uint64) Event {
+	return Event{
+		Type: eventType,
+		Data: SnapshotFrame{
+			SnapshotID: snapshotID,
+			Timestamp:  timestamp,
+		},
+	}
+}
+
+func (c *Client) decodeComboLegsEvent(
+	marshaller *sbe.SbeGoMarshaller,
+	reader io.Reader,
+	header sbe.MessageHeader,
+) (Event, error) {
+	var comboLegs sbe.ComboLegs
+	err := comboLegs.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
+	if err != nil {
+		c.log.Errorw("failed to decode combo legs event", "err", err)
+		return Event{}, err
+	}
+
+	return c.comboLegsEvent(&comboLegs), nil
+}
+
+func (c *Client) comboLegsEvent(comboLegs *sbe.ComboLegs) Event {
+	legs := make([]models.ComboLeg, len(comboLegs.LegsList))
+	for i, leg := range comboLegs.LegsList {
+		legs[i] = models.ComboLeg{
+			InstrumentName: c.getInstrument(leg.LegInstrumentId).InstrumentName,
+			Amount:         int(leg.LegSize),
+		}
+	}
+
+	return Event{
+		Type: EventTypeComboLegs,
+		Data: models.ComboLegsNotification{
+			InstrumentName: c.getInstrument(comboLegs.InstrumentId).InstrumentName,
+			Legs:           legs,
+		},
+	}
+}
+
+func (c *Client) decodePriceIndexEvent(
+	marshaller *sbe.SbeGoMarshaller,
+	reader io.Reader,
+	header sbe.MessageHeader,
+) (Event, error) {
+	var priceIndex sbe.PriceIndex
+	err := priceIndex.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
+	if err != nil {
+		c.log.Errorw("failed to decode price index event", "err", err)
+		return Event{}, err
+	}
+
+	return priceIndexEvent(&priceIndex), nil
+}
+
+func priceIndexEvent(priceIndex *sbe.PriceIndex) Event {
+	return Event{
+		Type: EventTypePriceIndex,
+		Data: models.DeribitPriceIndexNotification{
+			Timestamp: int64(priceIndex.TimestampMs),
+			Price:     priceIndex.Price,
+			IndexName: getStringFromBytes(priceIndex.IndexName[:]),
+		},
+	}
+}
+
+func (c *Client) decodeRfqEvent(
+	marshaller *sbe.SbeGoMarshaller,
+	reader io.Reader,
+	header sbe.MessageHeader,
+) (Event, error) {
+	var rfq sbe.Rfq
+	err := rfq.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true)
+	if err != nil {
+		c.log.Errorw("failed to decode rfq event", "err", err)
+		return Event{}, err
+	}
+
+	return c.rfqEvent(&rfq), nil
+}
+
+func (c *Client) rfqEvent(rfq *sbe.Rfq) Event {
+	return Event{
+		Type: EventTypeRfq,
+		Data: models.RfqNotification{
+			State:          rfq.State == sbe.YesNo.Yes,
+			Side:           rfq.Side.String(),
+			LastRfqTstamp:  rfq.TimestampMs,
+			InstrumentName: c.getInstrument(rfq.InstrumentId).InstrumentName,
+			Amount:         rfq.Amount,
+		},
+	}
 }
 
 func discardVars(_m *sbe.SbeGoMarshaller, r io.Reader, numVars uint16) error {
@@ -592,37 +870,61 @@ func parseSbeSnapshotToEvent(
 
 func (c *Client) emitEvents(events []Event) {
 	for _, event := range events {
-		switch event.Type {
-		case EventTypeInstrument:
-			// update instrumentsMap
-			ins := event.Data.(models.Instrument)
-			c.setInstrument(ins.InstrumentID, ins)
-
-			// emit event
-			c.Emit(newInstrumentNotificationChannel(ins.Kind, ins.BaseCurrency), &ins)
-			c.Emit(newInstrumentNotificationChannel(KindAny, ins.BaseCurrency), &ins)
-
-		case EventTypeOrderBook:
-			books := event.Data.(models.OrderBookRawNotification)
-			c.Emit(newOrderBookNotificationChannel(books.InstrumentName), &books)
-
-		case EventTypeTrades:
-			trades := event.Data.(models.TradesNotification)
-			if len(trades) > 0 {
-				tradeIns := trades[0].InstrumentName
-				tradeKind := trades[0].InstrumentKind
-				currency := getCurrencyFromInstrument(tradeIns)
-				c.Emit(newTradesNotificationChannel(tradeKind, currency), &trades)
-			}
-
-		case EventTypeTicker:
-			ticker := event.Data.(models.TickerNotification)
-			c.Emit(newTickerNotificationChannel(ticker.InstrumentName), &ticker)
+		c.emitEvent(event)
+	}
+}
 
-		case EventTypeSnapshot:
-			snapshot := event.Data.(models.OrderBookRawNotification)
-			c.Emit(newSnapshotNotificationChannel(snapshot.InstrumentName), &snapshot)
+func (c *Client) emitEvent(event Event) {
+	switch event.Type {
+	case EventTypeInstrument:
+		// update the instruments registry
+		ins := event.Data.(models.Instrument)
+		c.setInstrument(ins)
+
+		// emit event
+		c.Emit(newInstrumentNotificationChannel(ins.Kind, ins.BaseCurrency), &ins)
+		c.Emit(newInstrumentNotificationChannel(KindAny, ins.BaseCurrency), &ins)
+
+	case EventTypeOrderBook:
+		books := event.Data.(models.OrderBookRawNotification)
+		c.Emit(newOrderBookNotificationChannel(books.InstrumentName), &books)
+
+	case EventTypeTrades:
+		trades := event.Data.(models.TradesNotification)
+		if len(trades) > 0 {
+			tradeIns := trades[0].InstrumentName
+			tradeKind := trades[0].InstrumentKind
+			currency := getCurrencyFromInstrument(tradeIns)
+			c.Emit(newTradesNotificationChannel(tradeKind, currency), &trades)
 		}
+
+	case EventTypeTicker:
+		ticker := event.Data.(models.TickerNotification)
+		c.Emit(newTickerNotificationChannel(ticker.InstrumentName), &ticker)
+
+	case EventTypeSnapshot:
+		snapshot := event.Data.(models.OrderBookRawNotification)
+		c.Emit(newSnapshotNotificationChannel(snapshot.InstrumentName), &snapshot)
+
+	case EventTypeSnapshotStart:
+		frame := event.Data.(SnapshotFrame)
+		c.Emit(SnapshotStartChannel, &frame)
+
+	case EventTypeSnapshotEnd:
+		frame := event.Data.(SnapshotFrame)
+		c.Emit(SnapshotEndChannel, &frame)
+
+	case EventTypeComboLegs:
+		comboLegs := event.Data.(models.ComboLegsNotification)
+		c.Emit(newComboLegsNotificationChannel(comboLegs.InstrumentName), &comboLegs)
+
+	case EventTypePriceIndex:
+		priceIndex := event.Data.(models.DeribitPriceIndexNotification)
+		c.Emit(newPriceIndexNotificationChannel(priceIndex.IndexName), &priceIndex)
+
+	case EventTypeRfq:
+		rfq := event.Data.(models.RfqNotification)
+		c.Emit(newRfqNotificationChannel(rfq.InstrumentName), &rfq)
 	}
 }
 
@@ -638,12 +940,28 @@ func (c *Client) Start(ctx context.Context) error {
 }
 
 // restartConnection should re-build instrumentMap.
+// The new workers start once the old ones have decoded their queued packages, so the events of
+// a channel are still emitted in order.
 func (c *Client) restartConnections(ctx context.Context) error {
+	c.restartMu.Lock()
+	defer c.restartMu.Unlock()
+
 	err := c.Stop()
 	if err != nil {
 		return err
 	}
 
+	c.mu.RLock()
+	workersDone := c.workersDone
+	c.mu.RUnlock()
+	if workersDone != nil {
+		select {
+		case <-workersDone:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	err = c.Start(ctx)
 	if err != nil {
 		return err
@@ -653,6 +971,35 @@ func (c *Client) restartConnections(ctx context.Context) error {
 	return nil
 }
 
+// requestRestart asks the supervisor of the session generation to restart the connections. The
+// requests of an older session, or made while a restart is already requested, are dropped.
+func (c *Client) requestRestart(generation uint64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if generation != c.generation || c.restartCh == nil {
+		return
+	}
+	select {
+	case c.restartCh <- struct{}{}:
+	default:
+	}
+}
+
+// supervise restarts the connections of a session when its workers or its watchdog request it,
+// until done is closed or ctx is done. A single goroutine restarts, whichever worker detects
+// a reset.
+func (c *Client) supervise(ctx context.Context, restart <-chan struct{}, done <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+	case <-done:
+	case <-restart:
+		if err := c.restartConnections(ctx); err != nil {
+			c.log.Errorw("failed to restart connections", "err", err)
+		}
+	}
+}
+
 // Stop stops listening for events.
 func (c *Client) Stop() error {
 	for _, conn := range c.connMap {
@@ -665,11 +1012,12 @@ func (c *Client) Stop() error {
 }
 
 func (c *Client) getInstrument(id uint32) models.Instrument {
-	return c.instrumentsMap[id]
+	ins, _ := c.instruments.Get(id)
+	return ins
 }
 
-func (c *Client) setInstrument(id uint32, ins models.Instrument) {
-	c.instrumentsMap[id] = ins
+func (c *Client) setInstrument(ins models.Instrument) {
+	c.instruments.Set(ins)
 }
 
 func readPackageHeader(reader io.Reader) (uint16, uint16, uint32, error) {
@@ -691,23 +1039,34 @@ func (c *Client) handlePackageHeader(reader io.Reader, chanelIDSeq map[uint16]ui
 		return err
 	}
 
+	return c.checkPackageSeq(channelID, seq, chanelIDSeq)
+}
+
+// checkPackageSeq records the sequence number of a package and checks it against the last one of its channel.
+func (c *Client) checkPackageSeq(channelID uint16, seq uint32, chanelIDSeq map[uint16]uint32) error {
+	stats := c.stats.channel(channelID)
+	atomic.AddUint64(&stats.packets, 1)
 	lastSeq, ok := chanelIDSeq[channelID]
 
 	if ok {
 		log := c.log.With("channelID", channelID, "current_seq", seq, "last_seq", lastSeq)
 		if seq == 0 && math.MaxUint32-lastSeq >= 2 {
+			atomic.AddUint64(&stats.resets, 1)
 			return ErrConnectionReset
 		}
 		if seq == lastSeq {
+			atomic.AddUint64(&stats.duplicates, 1)
 			log.Debugw("package duplicated")
 			return ErrDuplicatedPackage
 		}
 		if seq != lastSeq+1 {
+			atomic.AddUint64(&stats.outOfOrder, 1)
 			log.Warnw("package out of order")
 		}
 	}
 
 	chanelIDSeq[channelID] = seq
+	atomic.StoreUint32(&stats.lastSeq, seq)
 	return nil
 }
 
@@ -782,7 +1141,7 @@ func (p *Pool) Put(bs Bytes) {
 
 func (c *Client) setupConnection(port int, ips []string) ([]net.IP, error) {
 	lc := net.ListenConfig{
-		Control: Control,
+		Control: ReceiveControl(c.receiveBufferSize),
 	}
 	conn, err := lc.ListenPacket(context.Background(), "udp4", "0.0.0.0:"+strconv.Itoa(port))
 	if err != nil {
@@ -792,6 +1151,15 @@ func (c *Client) setupConnection(port int, ips []string) ([]net.IP, error) {
 
 	c.connMap[port] = ipv4.NewPacketConn(conn)
 
+	stats := &receiveStats{groups: make([]*groupStats, len(ips))}
+	for i, ip := range ips {
+		stats.groups[i] = newGroupStats()
+		c.stats.setGroup(ip+":"+strconv.Itoa(port), stats.groups[i])
+	}
+	c.mu.Lock()
+	c.receiveStats[port] = stats
+	c.mu.Unlock()
+
 	ipGroups := make([]net.IP, len(ips))
 
 	err = c.connMap[port].SetControlMessage(ipv4.FlagDst, true)
@@ -861,8 +1229,6 @@ func (c *Client) setupConnections() (map[int][]net.IP, error) {
 }
 
 // ListenToEvents listens to a list of udp addresses on given network interface.
-//
-//nolint:cyclop,gocognit
 func (c *Client) ListenToEvents(ctx context.Context) error {
 	portIPsMap, err := c.setupConnections()
 	if err != nil {
@@ -870,55 +1236,78 @@ func (c *Client) ListenToEvents(ctx context.Context) error {
 		return err
 	}
 
-	dataCh := make(chan []byte, defaultDataChSize)
+	d := newDispatcher(c.decodeWorkers, defaultDataChSize)
 	pool := NewPool(maxPacketSize)
-
-	// handle data from dataCh
+	restartCh := make(chan struct{}, 1)
+	workersDone := make(chan struct{})
+
+	c.mu.Lock()
+	c.generation++
+	generation := c.generation
+	c.restartCh = restartCh
+	c.workersDone = workersDone
+	c.mu.Unlock()
+
+	// decode the packages of each worker
+	var workers sync.WaitGroup
+	workers.Add(len(d.workers))
+	for _, w := range d.workers {
+		w.generation = generation
+		go func(w *worker) {
+			defer workers.Done()
+			c.runWorker(ctx, w, pool)
+		}(w)
+	}
 	go func() {
-		m := sbe.NewSbeGoMarshaller()
-		channelIDSeq := make(map[uint16]uint32)
-		bookChangesMap := make(map[string][]sbe.BookChangesList)
-		snapshotLevelsMap := make(map[string][]sbe.SnapshotLevelsList)
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			case data, ok := <-dataCh:
-				if !ok {
-					return
-				}
-				err := c.handleUDPPackage(ctx, m, channelIDSeq, data, bookChangesMap, snapshotLevelsMap)
-				if err != nil {
-					c.log.Errorw("Fail to handle UDP package", "error", err)
-				}
-				pool.Put(data)
-			}
-		}
+		workers.Wait()
+		close(workersDone)
 	}()
 
 	// listen to event using ipv4 package
+	c.mu.Lock()
+	c.dispatcher = d
+	readers := make([]*batchReader, 0, len(c.connMap))
+	groups := make(map[string]*groupStats)
 	for port, conn := range c.connMap {
-		go func(conn *ipv4.PacketConn, ips []net.IP) {
-			for {
-				data := pool.Get()
+		stats := c.receiveStats[port]
+		readers = append(readers, newBatchReader(conn, portIPsMap[port], pool, c.readBatchSize, stats))
+		for i, ip := range portIPsMap[port] {
+			groups[ip.String()+":"+strconv.Itoa(port)] = stats.groups[i]
+		}
+	}
+	c.mu.Unlock()
 
-				res, err := readUDPMulticastPackage(conn, ips, data)
-				if res == nil {
-					pool.Put(data)
+	// the queues are closed and the watchdog stops once every reader is done
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(len(readers))
+	go func() {
+		wg.Wait()
+		d.close()
+		close(done)
+	}()
+	go c.watch(ctx, generation, groups, done)
+	go c.supervise(ctx, restartCh, done)
+
+	for _, r := range readers {
+		go func(r *batchReader) {
+			defer wg.Done()
+			handle := func(pkt packet) {
+				if !d.dispatch(ctx, pkt) {
+					pool.Put(pkt.data)
 				}
-
+			}
+			for ctx.Err() == nil {
+				err := r.read(handle)
 				if err != nil {
 					if isNetConnClosedErr(err) {
 						c.log.Infow("Connection closed", "error", err)
-						closeChannel(dataCh)
-						break
+						return
 					}
 					c.log.Errorw("Fail to read UDP multicast package", "error", err)
-				} else if res != nil {
-					dataCh <- res
 				}
 			}
-		}(conn, portIPsMap[port])
+		}(r)
 	}
 
 	return nil
@@ -926,61 +1315,22 @@ func (c *Client) ListenToEvents(ctx context.Context) error {
 
 func (c *Client) handleUDPPackage(
 	ctx context.Context,
-	m *sbe.SbeGoMarshaller,
+	dec *decoder,
 	channelIDSeq map[uint16]uint32,
-	data []byte,
+	pkt packet,
 	bookChangesMap map[string][]sbe.BookChangesList,
 	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList,
 ) error {
-	buf := bytes.NewBuffer(data)
-	err := c.Handle(m, buf, channelIDSeq, bookChangesMap, snapshotLevelsMap)
-	if err != nil {
-		if errors.Is(err, ErrConnectionReset) {
-			if err = c.restartConnections(ctx); err != nil {
-				c.log.Error("failed to restart connections", "err", err)
-				return err
-			}
-		} else {
-			c.log.Errorw(
-				"Fail to handle UDP package",
-				"data", base64.StdEncoding.EncodeToString(data),
-				"error", err,
-			)
-			return err
-		}
-	}
-
-	return nil
-}
-
-func readUDPMulticastPackage(conn *ipv4.PacketConn, ipGroups []net.IP, data []byte) ([]byte, error) {
-	n, cm, _, err := conn.ReadFrom(data)
-	if err != nil {
-		return nil, err
-	}
-
-	if cm.Dst.IsMulticast() {
-		if checkValidDstAddress(cm.Dst, ipGroups) {
-			return data[:n], nil
-		}
-	}
-
-	return nil, nil
-}
-
-func checkValidDstAddress(dest net.IP, groups []net.IP) bool {
-	for _, ipGroup := range groups {
-		if dest.Equal(ipGroup) {
-			return true
-		}
+	atomic.StoreInt64(&c.receivedAt, pkt.receivedAt.UnixNano())
+	dec.receivedAt = pkt.receivedAt
+	err := c.handleBytes(dec, pkt.data, channelIDSeq, bookChangesMap, snapshotLevelsMap)
+	if err != nil && !errors.Is(err, ErrConnectionReset) {
+		c.log.Errorw(
+			"Fail to handle UDP package",
+			"data", base64.StdEncoding.EncodeToString(pkt.data),
+			"error", err,
+		)
 	}
-	return false
-}
-
-func closeChannel(ch chan []byte) {
-	defer func() {
-		_ = recover()
-	}()
 
-	close(ch)
+	return err
 }
@@ -145,32 +145,18 @@ func testSetupConnections(c *Client, require *require.Assertions) {
 	mu.Unlock()
 }
 
-func (ts *MulticastTestSuite) TestGetAllInstruments() {
-	require := ts.Require()
-
-	// success case
-	ins, err := getAllInstrument(ts.c.instrumentsGetter, ts.c.supportCurrencies)
-	require.NoError(err)
-
-	// sort for comparing
-	sort.Slice(ins, func(i, j int) bool {
-		return ins[i].InstrumentID < ins[j].InstrumentID
-	})
-	require.Equal(ins, ts.ins)
-
-	// error case
-	ins, err = getAllInstrument(ts.c.instrumentsGetter, []string{"SHIB"})
-	require.ErrorIs(err, errInvalidParam)
-	require.Nil(ins)
-}
-
 func (ts *MulticastTestSuite) TestBuildInstrumentsMapping() {
 	require := ts.Require()
 
 	// success case
 	err := ts.c.buildInstrumentsMapping()
 	require.NoError(err)
-	require.Equal(ts.c.instrumentsMap, ts.insMap)
+	require.Equal(len(ts.insMap), ts.c.Instruments().Len())
+	for id, expected := range ts.insMap {
+		ins, ok := ts.c.Instruments().Get(id)
+		require.True(ok)
+		require.Equal(expected, ins)
+	}
 
 	// error case
 	err = ts.wrongClient.buildInstrumentsMapping()
@@ -195,6 +181,23 @@ func (ts *MulticastTestSuite) TestEventEmitter() {
 	require.Equal(1, receiveTimes)
 }
 
+func (ts *MulticastTestSuite) TestPatternEventEmitter() {
+	require := ts.Require()
+	received := make(map[string]int)
+	consumer := func(b *models.OrderBookRawNotification) {
+		received[b.InstrumentName]++
+	}
+
+	ts.c.On("book.*-PERPETUAL", consumer)
+	for _, name := range []string{"BTC-PERPETUAL", "ETH-PERPETUAL", "BTC-30DEC22"} {
+		ts.c.Emit(newOrderBookNotificationChannel(name), &models.OrderBookRawNotification{InstrumentName: name})
+	}
+	ts.c.Off("book.*-PERPETUAL", consumer)
+	ts.c.Emit(newOrderBookNotificationChannel("BTC-PERPETUAL"), &models.OrderBookRawNotification{})
+
+	require.Equal(map[string]int{"BTC-PERPETUAL": 1, "ETH-PERPETUAL": 1}, received)
+}
+
 func (ts *MulticastTestSuite) TestDecodeInstrumentEvent() {
 	require := ts.Require()
 
@@ -471,12 +474,14 @@ func (ts *MulticastTestSuite) TestDecodeTickerEvent() {
 			LastPrice:       10.8155,
 			InstrumentName:  "ETH-30SEP22-40000-P",
 			IndexPrice:      1497.93,
-			Funding8H:       math.NaN(),
-			CurrentFunding:  math.NaN(),
+			Funding8H:       0,
+			CurrentFunding:  0,
 			BestBidPrice:    &zero,
 			BestBidAmount:   0,
 			BestAskPrice:    &zero,
 			BestAskAmount:   0,
+
+			EstimatedDeliveryPrice: 1497.93,
 		},
 	}
 
@@ -490,20 +495,13 @@ func (ts *MulticastTestSuite) TestDecodeTickerEvent() {
 	eventDecoded, err := ts.c.decodeTickerEvent(ts.m, bufferData, header)
 	require.NoError(err)
 
-	// replace NaN value to `0` and pointer to 'nil'
-	expectedData := expectOutPut.Data.(models.TickerNotification)
-	outputData := eventDecoded.Data.(models.TickerNotification)
-
-	tickerPtr := reflect.TypeOf(&models.TickerNotification{})
-	common.ReplaceNaNValueOfStruct(&expectedData, tickerPtr)
-	common.ReplaceNaNValueOfStruct(&outputData, tickerPtr)
-	expectedData.BestBidPrice = nil
-	expectedData.BestAskPrice = nil
-	outputData.BestBidPrice = nil
-	outputData.BestAskPrice = nil
+	require.Equal(expectOutPut, eventDecoded)
 
-	require.Equal(expectOutPut.Type, eventDecoded.Type)
-	require.Equal(expectedData, outputData)
+	// The notification holds no NaN and no reference to the decoded message.
+	_, err = json.Marshal(eventDecoded.Data)
+	require.NoError(err)
+	ticker := eventDecoded.Data.(models.TickerNotification)
+	require.NotSame(ticker.BestBidPrice, ticker.BestAskPrice)
 }
 
 // nolint:funlen
@@ -614,6 +612,185 @@ func (ts *MulticastTestSuite) TestDecodeSnapshotEvent() {
 	}
 }
 
+func (ts *MulticastTestSuite) TestDecodeSnapshotFrameEvents() {
+	require := ts.Require()
+
+	tests := []struct {
+		event          []byte
+		expectedOutput Event
+	}{
+		{
+			[]byte{
+				0x10, 0x00, 0xed, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0xcd, 0x5b, 0x07,
+				0x00, 0x00, 0x00, 0x00, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			Event{
+				Type: EventTypeSnapshotStart,
+				Data: SnapshotFrame{
+					SnapshotID: 123456789,
+					Timestamp:  1662371873911,
+				},
+			},
+		},
+		{
+			[]byte{
+				0x10, 0x00, 0xee, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0xcd, 0x5b, 0x07,
+				0x00, 0x00, 0x00, 0x00, 0xdc, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			Event{
+				Type: EventTypeSnapshotEnd,
+				Data: SnapshotFrame{
+					SnapshotID: 123456789,
+					Timestamp:  1662371874012,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header sbe.MessageHeader
+		err := header.Decode(ts.m, bufferData)
+		require.NoError(err)
+
+		eventDecoded, err := ts.c.decodeEvent(ts.m, bufferData, header, nil, nil)
+		require.NoError(err)
+		require.Equal(test.expectedOutput, eventDecoded)
+	}
+}
+
+func (ts *MulticastTestSuite) TestDecodeComboLegsEvent() {
+	require := ts.Require()
+
+	event := []byte{
+		0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xa7, 0x81, 0x03, 0x00,
+		0x08, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x43, 0x7f, 0x03, 0x00, 0xff, 0xff, 0xff, 0xff,
+		0x40, 0x7f, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00,
+	}
+
+	expectedHeader := sbe.MessageHeader{
+		BlockLength:      4,
+		TemplateId:       1007,
+		SchemaId:         1,
+		Version:          1,
+		NumGroups:        1,
+		NumVarDataFields: 0,
+	}
+
+	expectOutPut := Event{
+		Type: EventTypeComboLegs,
+		Data: models.ComboLegsNotification{
+			InstrumentName: "BTC-FS-28OCT22_9SEP22",
+			Legs: []models.ComboLeg{
+				{
+					InstrumentName: "BTC-9SEP22",
+					Amount:         -1,
+				},
+				{
+					InstrumentName: "BTC-28OCT22",
+					Amount:         1,
+				},
+			},
+		},
+	}
+
+	bufferData := bytes.NewBuffer(event)
+
+	var header sbe.MessageHeader
+	err := header.Decode(ts.m, bufferData)
+	require.NoError(err)
+	require.Equal(header, expectedHeader)
+
+	eventDecoded, err := ts.c.decodeComboLegsEvent(ts.m, bufferData, header)
+	require.NoError(err)
+	require.Equal(expectOutPut, eventDecoded)
+}
+
+func (ts *MulticastTestSuite) TestDecodePriceIndexEvent() {
+	require := ts.Require()
+
+	event := []byte{
+		0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x62, 0x74, 0x63, 0x5f,
+		0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7b, 0x14, 0xae, 0x47,
+		0x61, 0x4e, 0xd3, 0x40, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+	}
+
+	expectOutPut := Event{
+		Type: EventTypePriceIndex,
+		Data: models.DeribitPriceIndexNotification{
+			Timestamp: 1662371873911,
+			Price:     19769.52,
+			IndexName: "btc_usd",
+		},
+	}
+
+	bufferData := bytes.NewBuffer(event)
+
+	var header sbe.MessageHeader
+	err := header.Decode(ts.m, bufferData)
+	require.NoError(err)
+	require.Equal(uint16(1008), header.TemplateId)
+
+	eventDecoded, err := ts.c.decodePriceIndexEvent(ts.m, bufferData, header)
+	require.NoError(err)
+	require.Equal(expectOutPut, eventDecoded)
+}
+
+func (ts *MulticastTestSuite) TestDecodeRfqEvent() {
+	require := ts.Require()
+
+	tests := []struct {
+		event          []byte
+		expectedOutput Event
+	}{
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x80, 0x84, 0x0e, 0x41, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			Event{
+				Type: EventTypeRfq,
+				Data: models.RfqNotification{
+					State:          true,
+					Side:           "sell",
+					LastRfqTstamp:  1662371873911,
+					InstrumentName: "BTC-PERPETUAL",
+					Amount:         250000,
+				},
+			},
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			Event{
+				Type: EventTypeRfq,
+				Data: models.RfqNotification{
+					State:          false,
+					LastRfqTstamp:  1662371873911,
+					InstrumentName: "BTC-PERPETUAL",
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header sbe.MessageHeader
+		err := header.Decode(ts.m, bufferData)
+		require.NoError(err)
+
+		eventDecoded, err := ts.c.decodeRfqEvent(ts.m, bufferData, header)
+		require.NoError(err)
+		require.Equal(test.expectedOutput, eventDecoded)
+	}
+}
+
 func (ts *MulticastTestSuite) TestDecodeEvent() {
 	assert := ts.Assert()
 
@@ -645,6 +822,36 @@ func (ts *MulticastTestSuite) TestDecodeEvent() {
 			},
 			io.EOF, // decodeTicker
 		},
+		{
+			[]byte{
+				0x10, 0x00, 0xed, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			io.EOF, // decodeSnapshotStart
+		},
+		{
+			[]byte{
+				0x10, 0x00, 0xee, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			io.EOF, // decodeSnapshotEnd
+		},
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00,
+			},
+			io.EOF, // decodeComboLegs
+		},
+		{
+			[]byte{
+				0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			io.EOF, // decodePriceIndex
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+			io.EOF, // decodeRfq
+		},
 	}
 
 	for _, test := range tests {
@@ -660,6 +867,14 @@ func (ts *MulticastTestSuite) TestDecodeEvent() {
 		decodedEvent, err := ts.c.decodeEvent(ts.m, bufferData, header, bookChangesMap, snapshotLevelMap)
 		assert.ErrorIs(err, test.expectError)
 		assert.Nil(decodedEvent.Data)
+
+		dec := newDecoder()
+		dec.d.Reset(test.event)
+		assert.NoError(dec.header.DecodeBytes(&dec.d))
+
+		decodedEvent, err = ts.c.decodeEventBytes(dec, dec.header, bookChangesMap, snapshotLevelMap)
+		assert.ErrorIs(err, test.expectError)
+		assert.Nil(decodedEvent.Data)
 	}
 }
 
@@ -860,6 +1075,20 @@ func (ts *MulticastTestSuite) TestDecodeEvents() {
 			assert.Equal(test.expectedOutput, decodedEvent)
 		}
 	}
+
+	// The byte slice path decodes the same events while reusing one decoder for all the packages.
+	dec := newDecoder()
+	bookChangesMap = make(map[string][]sbe.BookChangesList)
+	snapshotLevelMap = make(map[string][]sbe.SnapshotLevelsList)
+	for _, test := range tests {
+		dec.d.Reset(test.event)
+
+		decodedEvent, err := ts.c.decodeEventsBytes(dec, bookChangesMap, snapshotLevelMap)
+		assert.ErrorIs(err, test.expectError)
+		if err == nil {
+			assert.Equal(test.expectedOutput, decodedEvent)
+		}
+	}
 }
 
 func (ts *MulticastTestSuite) TestReadPackageHeader() {
@@ -1010,6 +1239,76 @@ func (ts *MulticastTestSuite) TestEmitEvents() {
 	ts.c.emitEvents(events)
 }
 
+func (ts *MulticastTestSuite) TestEmitSnapshotFrameAndReferenceEvents() {
+	require := ts.Require()
+
+	received := make(map[string]interface{})
+	var mu sync.Mutex
+	listener := func(channel string) func(interface{}) {
+		return func(data interface{}) {
+			mu.Lock()
+			received[channel] = data
+			mu.Unlock()
+		}
+	}
+
+	channels := []string{
+		SnapshotStartChannel,
+		SnapshotEndChannel,
+		"combo_legs.BTC-FS-28OCT22_9SEP22",
+		"price_index.btc_usd",
+		"rfq.BTC-PERPETUAL",
+	}
+	listeners := make([]func(interface{}), len(channels))
+	for i, channel := range channels {
+		listeners[i] = listener(channel)
+		ts.c.On(channel, listeners[i])
+	}
+	defer func() {
+		for i, channel := range channels {
+			ts.c.Off(channel, listeners[i])
+		}
+	}()
+
+	start := SnapshotFrame{SnapshotID: 1, Timestamp: 1662371873911}
+	end := SnapshotFrame{SnapshotID: 1, Timestamp: 1662371874012}
+	comboLegs := models.ComboLegsNotification{
+		InstrumentName: "BTC-FS-28OCT22_9SEP22",
+		Legs: []models.ComboLeg{
+			{InstrumentName: "BTC-9SEP22", Amount: -1},
+			{InstrumentName: "BTC-28OCT22", Amount: 1},
+		},
+	}
+	priceIndex := models.DeribitPriceIndexNotification{
+		Timestamp: 1662371873911,
+		Price:     19769.52,
+		IndexName: "btc_usd",
+	}
+	rfq := models.RfqNotification{
+		State:          true,
+		Side:           "buy",
+		LastRfqTstamp:  1662371873911,
+		InstrumentName: "BTC-PERPETUAL",
+		Amount:         250000,
+	}
+
+	ts.c.emitEvents([]Event{
+		{Type: EventTypeSnapshotStart, Data: start},
+		{Type: EventTypeComboLegs, Data: comboLegs},
+		{Type: EventTypePriceIndex, Data: priceIndex},
+		{Type: EventTypeRfq, Data: rfq},
+		{Type: EventTypeSnapshotEnd, Data: end},
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(&start, received[SnapshotStartChannel])
+	require.Equal(&end, received[SnapshotEndChannel])
+	require.Equal(&comboLegs, received["combo_legs.BTC-FS-28OCT22_9SEP22"])
+	require.Equal(&priceIndex, received["price_index.btc_usd"])
+	require.Equal(&rfq, received["rfq.BTC-PERPETUAL"])
+}
+
 // nolint:lll
 func (ts *MulticastTestSuite) TestHandleUDPPackage() {
 	tests := []struct {
@@ -1029,7 +1328,8 @@ func (ts *MulticastTestSuite) TestHandleUDPPackage() {
 				0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xa0, 0xf2, 0xd2, 0x40, 0x00, 0x00, 0x00,
 				0x00, 0x00, 0xce, 0xd3, 0x40,
 			},
-			nil,
+			// the worker requests the restart
+			ErrConnectionReset,
 		},
 		{
 			[]byte{
@@ -1047,7 +1347,7 @@ func (ts *MulticastTestSuite) TestHandleUDPPackage() {
 	bookChangesMap := make(map[string][]sbe.BookChangesList)
 	snapshotLevelMap := make(map[string][]sbe.SnapshotLevelsList)
 	for _, test := range tests {
-		err := ts.c.handleUDPPackage(context.Background(), ts.m, map[uint16]uint32{1001: 65537}, test.data, bookChangesMap, snapshotLevelMap)
+		err := ts.c.handleUDPPackage(context.Background(), newDecoder(), map[uint16]uint32{1001: 65537}, packet{data: test.data}, bookChangesMap, snapshotLevelMap)
 		ts.Require().ErrorIs(err, test.expectedError)
 	}
 }
@@ -1085,38 +1385,61 @@ func (ts *MulticastTestSuite) TestHandle() {
 	}
 }
 
-func setupIpv4Conn() (*ipv4.PacketConn, error) {
-	lc := net.ListenConfig{}
-	baseConn, err := lc.ListenPacket(context.Background(), "udp4", "0.0.0.0:3033")
-	if err != nil {
-		return nil, err
-	}
+func (ts *MulticastTestSuite) TestBatchReader() {
+	require := ts.Require()
+
+	lc := net.ListenConfig{Control: ReceiveControl(1 << 20)}
+	baseConn, err := lc.ListenPacket(context.Background(), "udp4", "127.0.0.1:0")
+	require.NoError(err)
+	defer baseConn.Close()
 
 	conn := ipv4.NewPacketConn(baseConn)
-	err = conn.SetControlMessage(ipv4.FlagDst, true)
-	if err != nil {
-		return nil, err
-	}
+	require.NoError(conn.SetControlMessage(ipv4.FlagDst, true))
 
-	return conn, nil
-}
+	stats := &receiveStats{groups: []*groupStats{newGroupStats()}}
+	pool := NewPool(maxPacketSize)
+	r := newBatchReader(conn, []net.IP{net.ParseIP("239.111.111.1")}, pool, 4, stats)
 
-func (ts *MulticastTestSuite) TestReadUDPMulticastPackage() {
-	require := ts.Require()
+	// Datagrams which are not sent to the multicast groups are counted but not handled.
+	before := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err = conn.WriteTo([]byte("Hello World!"), nil, conn.LocalAddr())
+		require.NoError(err)
+	}
 
-	conn, err := setupIpv4Conn()
-	require.NoError(err)
+	var pkts []packet
+	handle := func(pkt packet) {
+		pkts = append(pkts, pkt)
+	}
+	for stats.load().Packets < 3 {
+		require.NoError(r.read(handle))
+	}
+	require.Empty(pkts)
 
-	testData := []byte("Hello World!")
+	got := stats.load()
+	require.EqualValues(3, got.Packets)
+	require.LessOrEqual(got.Batches, uint64(3))
+	require.Zero(got.Drops)
+	require.False(got.LastReceivedAt.Before(before.Add(-time.Second)))
+	require.False(got.LastReceivedAt.After(time.Now()))
 
-	n, err := conn.WriteTo(testData, nil, conn.LocalAddr())
+	// The destination is the local address, it passes once it is one of the groups.
+	r.groups = append(r.groups, [4]byte{127, 0, 0, 1})
+	stats.groups = append(stats.groups, newGroupStats())
+	_, err = conn.WriteTo([]byte("Hello World!"), nil, conn.LocalAddr())
 	require.NoError(err)
+	require.NoError(r.read(handle))
+	require.Len(pkts, 1)
+	pkt := pkts[0]
+	require.Equal("Hello World!", string(pkt.data))
+	require.False(pkt.receivedAt.IsZero())
+	pool.Put(pkt.data)
 
-	data := make([]byte, 1500)
-	res, err := readUDPMulticastPackage(conn, nil, data)
-	require.Nil(res)
-	require.Equal(data[:n], testData)
-	require.NoError(err)
+	group := stats.groups[1].load(time.Now())
+	require.EqualValues(1, group.Packets)
+	require.EqualValues(len("Hello World!"), group.Bytes)
+	require.Equal(pkt.receivedAt.UnixNano(), group.LastReceivedAt.UnixNano())
+	require.Zero(stats.groups[0].load(time.Now()).Packets)
 }
 
 // nolint:lll,funlen,maintidx
@@ -1124,6 +1447,7 @@ func (ts *MulticastTestSuite) TestListenToEvents() {
 	require := ts.Require()
 	mu := &sync.RWMutex{}
 
+	require.NoError(ts.c.Start(context.Background()))
 	group := net.ParseIP("239.111.111.1")
 	dst := &net.UDPAddr{IP: group, Port: 6100}
 	_ = ts.c.connMap[6100].SetMulticastInterface(ts.c.inf)
@@ -9410,6 +9734,8 @@ func (ts *MulticastTestSuite) TestListenToEvents() {
 				BestBidAmount:   281910,
 				BestAskPrice:    &bestAskPrice,
 				BestAskAmount:   63660,
+
+				EstimatedDeliveryPrice: 16967.48,
 			})
 	})
 
@@ -9506,3 +9832,97 @@ func (ts *MulticastTestSuite) TestRestartConnection() {
 	err = ts.wrongClient.restartConnections(context.Background())
 	ts.Require().ErrorIs(err, errInvalidParam)
 }
+
+func (ts *MulticastTestSuite) TestRequestRestart() {
+	require := ts.Require()
+
+	restarted := make(chan bool, 2)
+	listener := func(ok bool) { restarted <- ok }
+	ts.c.On(RestartEventChannel, listener)
+	defer ts.c.Off(RestartEventChannel, listener)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(ts.c.Start(ctx))
+	defer ts.c.Stop()
+	ts.c.mu.RLock()
+	generation, workersDone := ts.c.generation, ts.c.workersDone
+	ts.c.mu.RUnlock()
+
+	// the workers detecting a reset at the same time restart the connections once
+	ts.c.requestRestart(generation)
+	ts.c.requestRestart(generation)
+	select {
+	case ok := <-restarted:
+		require.True(ok)
+	case <-time.After(time.Second):
+		require.Fail("no restart")
+	}
+
+	// the new workers started after the old ones returned
+	select {
+	case <-workersDone:
+	default:
+		require.Fail("workers of the previous session are running")
+	}
+	ts.c.mu.RLock()
+	require.Equal(generation+1, ts.c.generation)
+	ts.c.mu.RUnlock()
+
+	// the requests of the previous session are dropped
+	ts.c.requestRestart(generation)
+	require.Never(func() bool { return len(restarted) > 0 }, 50*time.Millisecond, 5*time.Millisecond)
+}
+
+type offsetClock time.Duration
+
+func (c offsetClock) ToServerTime(t time.Time) time.Time {
+	return t.Add(time.Duration(c))
+}
+
+func (ts *MulticastTestSuite) TestLatency() {
+	require := ts.Require()
+
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(err)
+
+	receivedAt := time.UnixMilli(1660000000100)
+	require.Equal(100*time.Millisecond, c.Latency(1660000000000, receivedAt))
+
+	// local clock is 30ms behind the server clock
+	c.SetClock(offsetClock(30 * time.Millisecond))
+	require.Equal(130*time.Millisecond, c.Latency(1660000000000, receivedAt))
+
+	// the clock can be replaced while the workers compute latencies
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			c.Latency(1660000000000, receivedAt)
+		}
+	}()
+	c.SetClock(offsetClock(50 * time.Millisecond))
+	<-done
+	require.Equal(150*time.Millisecond, c.Latency(1660000000000, receivedAt))
+}
+
+func (ts *MulticastTestSuite) TestReceiveSettings() {
+	require := ts.Require()
+
+	c, err := NewClient("", []string{"239.111.111.1:6101"}, nil, nil)
+	require.NoError(err)
+	require.Equal(defaultReadBatchSize, c.readBatchSize)
+	require.Empty(c.ReceiveStats())
+
+	c.SetReadBatchSize(0)
+	require.Equal(1, c.readBatchSize)
+	c.SetReadBatchSize(64)
+	require.Equal(64, c.readBatchSize)
+
+	c.SetReceiveBufferSize(1 << 20)
+	_, err = c.setupConnections()
+	require.NoError(err)
+	defer c.Stop()
+
+	require.Equal(map[int]ReceiveStats{6101: {}}, c.ReceiveStats())
+}
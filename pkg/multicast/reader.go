@@ -0,0 +1,141 @@
+package multicast
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+const defaultReadBatchSize = 32
+
+// packet is a datagram received from one of the multicast groups.
+type packet struct {
+	data       []byte
+	receivedAt time.Time
+}
+
+// receiveInfo is what the control messages of a datagram carry.
+type receiveInfo struct {
+	dst        [4]byte
+	hasDst     bool
+	receivedAt time.Time
+	drops      uint32
+	hasDrops   bool
+}
+
+// ReceiveStats are the counters of the datagrams received on a socket since it was opened.
+type ReceiveStats struct {
+	// Packets is the number of datagrams read, including those sent to other groups.
+	Packets uint64
+	// Batches is the number of read calls, each of them reads up to the read batch size datagrams.
+	Batches uint64
+	// Drops is the number of datagrams the kernel dropped as the receive buffer was full, Linux only.
+	Drops uint64
+	// LastReceivedAt is the kernel receive time of the last datagram.
+	LastReceivedAt time.Time
+}
+
+type receiveStats struct {
+	packets        uint64
+	batches        uint64
+	drops          uint64
+	lastReceivedAt int64
+
+	groups []*groupStats // in the order of the groups of the socket
+}
+
+func (s *receiveStats) load() ReceiveStats {
+	stats := ReceiveStats{
+		Packets: atomic.LoadUint64(&s.packets),
+		Batches: atomic.LoadUint64(&s.batches),
+		Drops:   atomic.LoadUint64(&s.drops),
+	}
+	if ns := atomic.LoadInt64(&s.lastReceivedAt); ns != 0 {
+		stats.LastReceivedAt = time.Unix(0, ns)
+	}
+	return stats
+}
+
+// batchReader reads datagrams from a socket, many of them per recvmmsg call on Linux.
+type batchReader struct {
+	conn   *ipv4.PacketConn
+	groups [][4]byte
+	pool   *Pool
+	msgs   []ipv4.Message
+	stats  *receiveStats
+}
+
+func newBatchReader(
+	conn *ipv4.PacketConn, groups []net.IP, pool *Pool, batchSize int, stats *receiveStats,
+) *batchReader {
+	r := &batchReader{
+		conn:  conn,
+		pool:  pool,
+		msgs:  make([]ipv4.Message, batchSize),
+		stats: stats,
+	}
+	for _, group := range groups {
+		var ip [4]byte
+		copy(ip[:], group.To4())
+		r.groups = append(r.groups, ip)
+	}
+	for i := range r.msgs {
+		r.msgs[i].Buffers = [][]byte{pool.Get()}
+		r.msgs[i].OOB = make([]byte, controlMessageSize)
+	}
+	return r
+}
+
+// read reads a batch of datagrams and passes those of the multicast groups to handle.
+// The buffers passed to handle are taken from the pool and must be put back.
+func (r *batchReader) read(handle func(packet)) error {
+	for i := range r.msgs {
+		r.msgs[i].OOB = r.msgs[i].OOB[:cap(r.msgs[i].OOB)]
+	}
+
+	n, err := r.conn.ReadBatch(r.msgs, 0)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+
+	atomic.AddUint64(&r.stats.batches, 1)
+	atomic.AddUint64(&r.stats.packets, uint64(n))
+	for i := 0; i < n; i++ {
+		msg := &r.msgs[i]
+		info := parseControlMessage(msg.OOB[:msg.NN])
+		if info.hasDrops {
+			atomic.StoreUint64(&r.stats.drops, uint64(info.drops))
+		}
+		if info.receivedAt.IsZero() {
+			info.receivedAt = now
+		}
+		receivedAt := info.receivedAt.UnixNano()
+		atomic.StoreInt64(&r.stats.lastReceivedAt, receivedAt)
+
+		group := r.group(info)
+		if group < 0 {
+			continue
+		}
+		r.stats.groups[group].received(msg.N, receivedAt)
+
+		handle(packet{data: msg.Buffers[0][:msg.N], receivedAt: info.receivedAt})
+		msg.Buffers[0] = r.pool.Get()
+	}
+	return nil
+}
+
+// group returns the index of the destination group of a datagram, -1 if it is not one of the groups.
+func (r *batchReader) group(info receiveInfo) int {
+	if !info.hasDst {
+		return -1
+	}
+	for i, group := range r.groups {
+		if info.dst == group {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,15 @@
+package sbe
+
+import "time"
+
+func (i InstrumentStateEnum) IsActive() bool {
+	return i == InstrumentState.Created || i == InstrumentState.Open || i == InstrumentState.Settled
+}
+
+func (i *Instrument) IsActive() bool {
+	return i.InstrumentState.IsActive() && i.ExpirationTimestampMs > uint64(time.Now().UnixMilli())
+}
+
+func (i *InstrumentV2) IsActive() bool {
+	return i.InstrumentState.IsActive() && i.ExpirationTimestampMs > uint64(time.Now().UnixMilli())
+}
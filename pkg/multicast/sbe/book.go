@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -23,7 +25,70 @@ type BookChangesList struct {
 	Amount float64
 }
 
+func (b *Book) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := b.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, b.InstrumentId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, b.TimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, b.PrevChangeId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, b.ChangeId); err != nil {
+		return err
+	}
+
+	if err := b.IsLast.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if len(b.ChangesList) > math.MaxUint16 {
+		return fmt.Errorf("%w on b.ChangesList length (%v > %v)", ErrRangeCheck, len(b.ChangesList), math.MaxUint16)
+	}
+
+	if err := _m.WriteUint16(_w, 18); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, uint16(len(b.ChangesList))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	for i := range b.ChangesList {
+		if err := b.ChangesList[i].Encode(_m, _w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (b *Book) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return b.DecodeVersion(_m, _r, b.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (b *Book) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
 	if err := _m.ReadUint32(_r, &b.InstrumentId); err != nil {
 		return err
 	}
@@ -66,11 +131,54 @@ func (b *Book) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doR
 	}
 	b.ChangesList = b.ChangesList[:ChangesListNumInGroup]
 	for i := range b.ChangesList {
-		if err := b.ChangesList[i].Decode(_m, _r, uint(ChangesListBlockLength)); err != nil {
+		if err := b.ChangesList[i].DecodeVersion(_m, _r, actingVersion, uint(ChangesListBlockLength)); err != nil {
+			return err
+		}
+	}
+
+	if doRangeCheck {
+		if err := b.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of b are reused, decoding into the same b does not allocate once
+// they are large enough.
+func (b *Book) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	b.InstrumentId = _d.Uint32()
+	b.TimestampMs = _d.Uint64()
+	b.PrevChangeId = _d.Uint64()
+	b.ChangeId = _d.Uint64()
+	b.IsLast = YesNoEnum(_d.Uint8())
+
+	if blockLength > b.SbeBlockLength() {
+		_d.Skip(int(blockLength - b.SbeBlockLength()))
+	}
+
+	ChangesListBlockLength := _d.Uint16()
+	ChangesListNumInGroup := _d.Uint16()
+	_d.Skip(4) // numGroups and numVars
+	if err := _d.Need(int(ChangesListNumInGroup) * int(ChangesListBlockLength)); err != nil {
+		return err
+	}
+	if cap(b.ChangesList) < int(ChangesListNumInGroup) {
+		b.ChangesList = make([]BookChangesList, ChangesListNumInGroup)
+	}
+	b.ChangesList = b.ChangesList[:ChangesListNumInGroup]
+	for i := range b.ChangesList {
+		if err := b.ChangesList[i].DecodeBytes(_d, actingVersion, uint(ChangesListBlockLength)); err != nil {
 			return err
 		}
 	}
 
+	if err := _d.Err(); err != nil {
+		return err
+	}
 	if doRangeCheck {
 		if err := b.RangeCheck(); err != nil {
 			return err
@@ -88,7 +196,7 @@ func (b *Book) RangeCheck() error {
 		return fmt.Errorf("%w on b.TimestampMs (%v < %v > %v)", ErrRangeCheck, b.TimestampMsMinValue(), b.TimestampMs, b.TimestampMsMaxValue())
 	}
 
-	if b.PrevChangeId < b.PrevChangeIdMinValue() || b.PrevChangeId > b.PrevChangeIdMaxValue() {
+	if b.PrevChangeId != b.PrevChangeIdNullValue() && (b.PrevChangeId < b.PrevChangeIdMinValue() || b.PrevChangeId > b.PrevChangeIdMaxValue()) {
 		return fmt.Errorf("%w on b.PrevChangeId (%v < %v > %v)", ErrRangeCheck, b.PrevChangeIdMinValue(), b.PrevChangeId, b.PrevChangeIdMaxValue())
 	}
 
@@ -99,15 +207,41 @@ func (b *Book) RangeCheck() error {
 	if err := b.IsLast.RangeCheck(); err != nil {
 		return err
 	}
+
 	for _, prop := range b.ChangesList {
 		if err := prop.RangeCheck(); err != nil {
 			return err
 		}
 	}
+
+	return nil
+}
+
+func (b *BookChangesList) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := b.Side.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := b.Change.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, b.Price); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, b.Amount); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (b *BookChangesList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint) error {
+	return b.DecodeVersion(_m, _r, SchemaVersion, blockLength)
+}
+
+func (b *BookChangesList) DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint) error {
 	if err := b.Side.Decode(_m, _r); err != nil {
 		return err
 	}
@@ -131,6 +265,19 @@ func (b *BookChangesList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength
 	return nil
 }
 
+func (b *BookChangesList) DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint) error {
+	b.Side = BookSideEnum(_d.Uint8())
+	b.Change = BookChangeEnum(_d.Uint8())
+	b.Price = _d.Float64()
+	b.Amount = _d.Float64()
+
+	if blockLength > b.SbeBlockLength() {
+		_d.Skip(int(blockLength - b.SbeBlockLength()))
+	}
+
+	return _d.Err()
+}
+
 func (b *BookChangesList) RangeCheck() error {
 	if err := b.Side.RangeCheck(); err != nil {
 		return err
@@ -155,6 +302,18 @@ func (*Book) SbeBlockLength() (blockLength uint16) {
 	return 29
 }
 
+func (*Book) SbeTemplateId() (templateId uint16) {
+	return 1001
+}
+
+func (*Book) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*Book) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*Book) InstrumentIdMinValue() uint32 {
 	return 0
 }
@@ -0,0 +1,87 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeComboLegs(t *testing.T) {
+	tests := []struct {
+		event          []byte
+		expectedOutput ComboLegs
+		expectedError  error
+	}{
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xd4, 0x37, 0x03, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x48, 0x37, 0x03, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+			ComboLegs{
+				InstrumentId: 210900,
+				LegsList: []ComboLegsLegsList{
+					{
+						LegInstrumentId: 210838,
+						LegSize:         1,
+					},
+					{
+						LegInstrumentId: 210760,
+						LegSize:         -1,
+					},
+				},
+			},
+			nil,
+		},
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xd4, 0x37, 0x03, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x48, 0x37,
+			},
+			ComboLegs{},
+			io.ErrUnexpectedEOF,
+		},
+		// Some range check error cases
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xd4, 0x37, 0x03, 0x00,
+				0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0x01, 0x00, 0x00, 0x00,
+			},
+			ComboLegs{},
+			ErrRangeCheck, // LegsList - LegInstrumentId
+		},
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xd4, 0x37, 0x03, 0x00,
+				0x08, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00, 0x00, 0x00, 0x00, 0x80,
+			},
+			ComboLegs{},
+			ErrRangeCheck, // LegsList - LegSize
+		},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header MessageHeader
+		err := header.Decode(marshaller, bufferData)
+		require.NoError(t, err)
+
+		err = header.RangeCheck()
+		require.NoError(t, err)
+
+		var comboLegs ComboLegs
+		err = comboLegs.Decode(marshaller, bufferData, header.BlockLength, true)
+		require.ErrorIs(t, err, test.expectedError)
+
+		if err == nil {
+			assert.Equal(t, comboLegs, test.expectedOutput)
+		}
+	}
+}
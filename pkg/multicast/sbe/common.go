@@ -1,5 +1,7 @@
 package sbe
 
+//go:generate go run ../../../cmd/sbegen -schema deribit_multicast.xml -out .
+
 import "errors"
 
 var ErrRangeCheck = errors.New("range check failed")
@@ -0,0 +1,116 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sbeMessage interface {
+	Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error
+	Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error
+}
+
+func TestEncodeDecodedEvent(t *testing.T) {
+	tests := []struct {
+		event   []byte
+		message sbeMessage
+	}{
+		{
+			[]byte{
+				0x1d, 0x00, 0xe9, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00, 0x3c, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00,
+				0x3d, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x12, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x60, 0x4e, 0xd3, 0x40, 0x00, 0x00, 0x00, 0x00, 0xc0,
+				0x4f, 0xed, 0x40,
+			},
+			&Book{},
+		},
+		{
+			[]byte{
+				0x04, 0x00, 0xef, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0xd4, 0x37, 0x03, 0x00,
+				0x08, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x48, 0x37, 0x03, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+			&ComboLegs{},
+		},
+		{
+			[]byte{
+				0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x62, 0x74, 0x63, 0x5f,
+				0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7b, 0x14, 0xae, 0x47,
+				0x61, 0x4e, 0xd3, 0x40, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			&PriceIndex{},
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			&Rfq{},
+		},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		var header MessageHeader
+		reader := bytes.NewReader(test.event)
+		require.NoError(t, header.Decode(marshaller, reader))
+		require.NoError(t, test.message.Decode(marshaller, reader, header.BlockLength, true))
+
+		var encoded bytes.Buffer
+		require.NoError(t, header.Encode(marshaller, &encoded))
+		require.NoError(t, test.message.Encode(marshaller, &encoded, true))
+		assert.Equal(t, test.event, encoded.Bytes())
+	}
+}
+
+func TestEncodeInstrumentV2(t *testing.T) {
+	ins := InstrumentV2{
+		InstrumentId:          210838,
+		InstrumentState:       InstrumentState.Open,
+		Kind:                  InstrumentKind.Future,
+		InstrumentType:        InstrumentType.Reversed,
+		OptionType:            OptionType.NotApplicable,
+		SettlementPeriod:      Period.Perpetual,
+		SettlementPeriodCount: 1,
+		ExpirationTimestampMs: 32503708800000,
+		StrikePrice:           1,
+		ContractSize:          10,
+		MinTradeAmount:        10,
+		TickSize:              0.5,
+		TickStepsList: []InstrumentV2TickStepsList{
+			{AbovePrice: 10000, TickSize: 1},
+			{AbovePrice: 100000, TickSize: 5},
+		},
+		InstrumentName: []uint8("BTC-PERPETUAL"),
+	}
+	copy(ins.BaseCurrency[:], "BTC")
+	copy(ins.QuoteCurrency[:], "USD")
+
+	marshaller := NewSbeGoMarshaller()
+
+	var encoded bytes.Buffer
+	require.NoError(t, ins.Encode(marshaller, &encoded, true))
+	require.Equal(t, int(ins.SbeBlockLength())+8+2*16+1+len("BTC-PERPETUAL"), encoded.Len())
+
+	var decoded InstrumentV2
+	require.NoError(t, decoded.Decode(marshaller, &encoded, ins.SbeBlockLength(), true))
+	assert.Equal(t, ins, decoded)
+}
+
+func TestEncodeRangeCheck(t *testing.T) {
+	marshaller := NewSbeGoMarshaller()
+	rfq := Rfq{Side: DirectionEnum(3)}
+
+	var encoded bytes.Buffer
+	require.ErrorIs(t, rfq.Encode(marshaller, &encoded, true), ErrRangeCheck)
+	require.Zero(t, encoded.Len())
+	require.NoError(t, rfq.Encode(marshaller, &encoded, false))
+	require.Equal(t, int(rfq.SbeBlockLength()), encoded.Len())
+}
@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -5,7 +7,6 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
-	"time"
 )
 
 type Instrument struct {
@@ -36,7 +37,132 @@ type Instrument struct {
 	InstrumentName           []uint8
 }
 
+func (i *Instrument) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := i.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, i.InstrumentId); err != nil {
+		return err
+	}
+
+	if err := i.InstrumentState.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.Kind.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.FutureType.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.OptionType.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.Rfq.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.SettlementPeriod.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, i.SettlementPeriodCount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.BaseCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.QuoteCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.CounterCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.SettlementCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.SizeCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, i.CreationTimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, i.ExpirationTimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.StrikePrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.ContractSize); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MinTradeAmount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.TickSize); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MakerCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.TakerCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.BlockTradeCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MaxLiquidationCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MaxLeverage); err != nil {
+		return err
+	}
+
+	if len(i.InstrumentName) > math.MaxUint8 {
+		return fmt.Errorf("%w on i.InstrumentName length (%v > %v)", ErrRangeCheck, len(i.InstrumentName), math.MaxUint8)
+	}
+
+	if err := _m.WriteUint8(_w, uint8(len(i.InstrumentName))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.InstrumentName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (i *Instrument) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return i.DecodeVersion(_m, _r, i.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (i *Instrument) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
 	if err := _m.ReadUint32(_r, &i.InstrumentId); err != nil {
 		return err
 	}
@@ -157,6 +283,62 @@ func (i *Instrument) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint1
 	return nil
 }
 
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of i are reused, decoding into the same i does not allocate once
+// they are large enough.
+func (i *Instrument) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	i.InstrumentId = _d.Uint32()
+	i.InstrumentState = InstrumentStateEnum(_d.Uint8())
+	i.Kind = InstrumentKindEnum(_d.Uint8())
+	i.FutureType = FutureTypeEnum(_d.Uint8())
+	i.OptionType = OptionTypeEnum(_d.Uint8())
+	i.Rfq = YesNoEnum(_d.Uint8())
+	i.SettlementPeriod = PeriodEnum(_d.Uint8())
+	i.SettlementPeriodCount = _d.Uint16()
+	_d.Bytes(i.BaseCurrency[:])
+	_d.Bytes(i.QuoteCurrency[:])
+	_d.Bytes(i.CounterCurrency[:])
+	_d.Bytes(i.SettlementCurrency[:])
+	_d.Bytes(i.SizeCurrency[:])
+	i.CreationTimestampMs = _d.Uint64()
+	i.ExpirationTimestampMs = _d.Uint64()
+	i.StrikePrice = _d.Float64()
+	i.ContractSize = _d.Float64()
+	i.MinTradeAmount = _d.Float64()
+	i.TickSize = _d.Float64()
+	i.MakerCommission = _d.Float64()
+	i.TakerCommission = _d.Float64()
+	i.BlockTradeCommission = _d.Float64()
+	i.MaxLiquidationCommission = _d.Float64()
+	i.MaxLeverage = _d.Float64()
+
+	if blockLength > i.SbeBlockLength() {
+		_d.Skip(int(blockLength - i.SbeBlockLength()))
+	}
+
+	InstrumentNameLength := _d.Uint8()
+	if err := _d.Need(int(InstrumentNameLength)); err != nil {
+		return err
+	}
+	if cap(i.InstrumentName) < int(InstrumentNameLength) {
+		i.InstrumentName = make([]uint8, InstrumentNameLength)
+	}
+	i.InstrumentName = i.InstrumentName[:InstrumentNameLength]
+	_d.Bytes(i.InstrumentName)
+
+	if err := _d.Err(); err != nil {
+		return err
+	}
+	if doRangeCheck {
+		if err := i.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (i *Instrument) RangeCheck() error {
 	if i.InstrumentId < i.InstrumentIdMinValue() || i.InstrumentId > i.InstrumentIdMaxValue() {
 		return fmt.Errorf("%w on i.InstrumentId (%v < %v > %v)", ErrRangeCheck, i.InstrumentIdMinValue(), i.InstrumentId, i.InstrumentIdMaxValue())
@@ -165,18 +347,23 @@ func (i *Instrument) RangeCheck() error {
 	if err := i.InstrumentState.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.Kind.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.FutureType.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.OptionType.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.Rfq.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.SettlementPeriod.RangeCheck(); err != nil {
 		return err
 	}
@@ -281,6 +468,18 @@ func (*Instrument) SbeBlockLength() (blockLength uint16) {
 	return 140
 }
 
+func (*Instrument) SbeTemplateId() (templateId uint16) {
+	return 1000
+}
+
+func (*Instrument) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*Instrument) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*Instrument) InstrumentIdMinValue() uint32 {
 	return 0
 }
@@ -424,7 +623,3 @@ func (*Instrument) MaxLeverageMinValue() float64 {
 func (*Instrument) MaxLeverageMaxValue() float64 {
 	return math.MaxFloat64
 }
-
-func (i *Instrument) IsActive() bool {
-	return i.InstrumentState.IsActive() && i.ExpirationTimestampMs > uint64(time.Now().UnixMilli())
-}
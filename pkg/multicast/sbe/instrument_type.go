@@ -1,9 +1,10 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
 	"fmt"
 	"io"
-	"reflect"
 )
 
 type InstrumentTypeEnum uint8
@@ -16,6 +17,13 @@ type InstrumentTypeValues struct {
 
 var InstrumentType = InstrumentTypeValues{0, 1, 2, 255}
 
+func (i InstrumentTypeEnum) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := _m.WriteUint8(_w, uint8(i)); err != nil {
+		return err
+	}
+	return nil
+}
+
 func (i *InstrumentTypeEnum) Decode(_m *SbeGoMarshaller, _r io.Reader) error {
 	if err := _m.ReadUint8(_r, (*uint8)(i)); err != nil {
 		return err
@@ -24,11 +32,9 @@ func (i *InstrumentTypeEnum) Decode(_m *SbeGoMarshaller, _r io.Reader) error {
 }
 
 func (i InstrumentTypeEnum) RangeCheck() error {
-	value := reflect.ValueOf(InstrumentType)
-	for idx := 0; idx < value.NumField(); idx++ {
-		if i == value.Field(idx).Interface() {
-			return nil
-		}
+	switch i {
+	case InstrumentType.NotApplicable, InstrumentType.Reversed, InstrumentType.Linear, InstrumentType.NullValue:
+		return nil
 	}
-	return fmt.Errorf("Range check failed on InstrumentType, unknown enumeration value %d", i)
+	return fmt.Errorf("%w on InstrumentType, unknown enumeration value %d", ErrRangeCheck, i)
 }
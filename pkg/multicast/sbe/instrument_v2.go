@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -5,7 +7,6 @@ import (
 	"io"
 	"io/ioutil"
 	"math"
-	"time"
 )
 
 type InstrumentV2 struct {
@@ -35,91 +36,261 @@ type InstrumentV2 struct {
 	TickStepsList            []InstrumentV2TickStepsList
 	InstrumentName           []uint8
 }
+
 type InstrumentV2TickStepsList struct {
 	AbovePrice float64
 	TickSize   float64
 }
 
-func (i *InstrumentV2) Decode(
-	_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool,
+func (i *InstrumentV2) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := i.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, i.InstrumentId); err != nil {
+		return err
+	}
+
+	if err := i.InstrumentState.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.Kind.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.InstrumentType.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.OptionType.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := i.SettlementPeriod.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, i.SettlementPeriodCount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.BaseCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.QuoteCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.CounterCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.SettlementCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.SizeCurrency[:]); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, i.CreationTimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, i.ExpirationTimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.StrikePrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.ContractSize); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MinTradeAmount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.TickSize); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MakerCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.TakerCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.BlockTradeCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MaxLiquidationCommission); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.MaxLeverage); err != nil {
+		return err
+	}
+
+	if len(i.TickStepsList) > math.MaxUint16 {
+		return fmt.Errorf("%w on i.TickStepsList length (%v > %v)", ErrRangeCheck, len(i.TickStepsList), math.MaxUint16)
+	}
+
+	if err := _m.WriteUint16(_w, 16); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, uint16(len(i.TickStepsList))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	for j := range i.TickStepsList {
+		if err := i.TickStepsList[j].Encode(_m, _w); err != nil {
+			return err
+		}
+	}
+
+	if len(i.InstrumentName) > math.MaxUint8 {
+		return fmt.Errorf("%w on i.InstrumentName length (%v > %v)", ErrRangeCheck, len(i.InstrumentName), math.MaxUint8)
+	}
+
+	if err := _m.WriteUint8(_w, uint8(len(i.InstrumentName))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteBytes(_w, i.InstrumentName); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (i *InstrumentV2) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return i.DecodeVersion(_m, _r, i.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (i *InstrumentV2) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
 ) error {
 	if err := _m.ReadUint32(_r, &i.InstrumentId); err != nil {
 		return err
 	}
+
 	if err := i.InstrumentState.Decode(_m, _r); err != nil {
 		return err
 	}
+
 	if err := i.Kind.Decode(_m, _r); err != nil {
 		return err
 	}
+
 	if err := i.InstrumentType.Decode(_m, _r); err != nil {
 		return err
 	}
+
 	if err := i.OptionType.Decode(_m, _r); err != nil {
 		return err
 	}
+
 	if err := i.SettlementPeriod.Decode(_m, _r); err != nil {
 		return err
 	}
+
 	if err := _m.ReadUint16(_r, &i.SettlementPeriodCount); err != nil {
 		return err
 	}
+
 	if err := _m.ReadBytes(_r, i.BaseCurrency[:]); err != nil {
 		return err
 	}
+
 	if err := _m.ReadBytes(_r, i.QuoteCurrency[:]); err != nil {
 		return err
 	}
+
 	if err := _m.ReadBytes(_r, i.CounterCurrency[:]); err != nil {
 		return err
 	}
+
 	if err := _m.ReadBytes(_r, i.SettlementCurrency[:]); err != nil {
 		return err
 	}
+
 	if err := _m.ReadBytes(_r, i.SizeCurrency[:]); err != nil {
 		return err
 	}
+
 	if err := _m.ReadUint64(_r, &i.CreationTimestampMs); err != nil {
 		return err
 	}
+
 	if err := _m.ReadUint64(_r, &i.ExpirationTimestampMs); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.StrikePrice); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.ContractSize); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.MinTradeAmount); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.TickSize); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.MakerCommission); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.TakerCommission); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.BlockTradeCommission); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.MaxLiquidationCommission); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.MaxLeverage); err != nil {
 		return err
 	}
+
 	if blockLength > i.SbeBlockLength() {
-		io.CopyN(ioutil.Discard, _r, int64(blockLength-i.SbeBlockLength()))
+		_, _ = io.CopyN(ioutil.Discard, _r, int64(blockLength-i.SbeBlockLength()))
 	}
 
 	var TickStepsListBlockLength uint16
 	if err := _m.ReadUint16(_r, &TickStepsListBlockLength); err != nil {
 		return err
 	}
+
 	var TickStepsListNumInGroup uint16
 	if err := _m.ReadUint16(_r, &TickStepsListNumInGroup); err != nil {
 		return err
@@ -133,7 +304,7 @@ func (i *InstrumentV2) Decode(
 	}
 	i.TickStepsList = i.TickStepsList[:TickStepsListNumInGroup]
 	for j := range i.TickStepsList {
-		if err := i.TickStepsList[j].Decode(_m, _r, uint(TickStepsListBlockLength)); err != nil {
+		if err := i.TickStepsList[j].DecodeVersion(_m, _r, actingVersion, uint(TickStepsListBlockLength)); err != nil {
 			return err
 		}
 	}
@@ -149,6 +320,78 @@ func (i *InstrumentV2) Decode(
 	if err := _m.ReadBytes(_r, i.InstrumentName); err != nil {
 		return err
 	}
+
+	if doRangeCheck {
+		if err := i.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of i are reused, decoding into the same i does not allocate once
+// they are large enough.
+func (i *InstrumentV2) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	i.InstrumentId = _d.Uint32()
+	i.InstrumentState = InstrumentStateEnum(_d.Uint8())
+	i.Kind = InstrumentKindEnum(_d.Uint8())
+	i.InstrumentType = InstrumentTypeEnum(_d.Uint8())
+	i.OptionType = OptionTypeEnum(_d.Uint8())
+	i.SettlementPeriod = PeriodEnum(_d.Uint8())
+	i.SettlementPeriodCount = _d.Uint16()
+	_d.Bytes(i.BaseCurrency[:])
+	_d.Bytes(i.QuoteCurrency[:])
+	_d.Bytes(i.CounterCurrency[:])
+	_d.Bytes(i.SettlementCurrency[:])
+	_d.Bytes(i.SizeCurrency[:])
+	i.CreationTimestampMs = _d.Uint64()
+	i.ExpirationTimestampMs = _d.Uint64()
+	i.StrikePrice = _d.Float64()
+	i.ContractSize = _d.Float64()
+	i.MinTradeAmount = _d.Float64()
+	i.TickSize = _d.Float64()
+	i.MakerCommission = _d.Float64()
+	i.TakerCommission = _d.Float64()
+	i.BlockTradeCommission = _d.Float64()
+	i.MaxLiquidationCommission = _d.Float64()
+	i.MaxLeverage = _d.Float64()
+
+	if blockLength > i.SbeBlockLength() {
+		_d.Skip(int(blockLength - i.SbeBlockLength()))
+	}
+
+	TickStepsListBlockLength := _d.Uint16()
+	TickStepsListNumInGroup := _d.Uint16()
+	_d.Skip(4) // numGroups and numVars
+	if err := _d.Need(int(TickStepsListNumInGroup) * int(TickStepsListBlockLength)); err != nil {
+		return err
+	}
+	if cap(i.TickStepsList) < int(TickStepsListNumInGroup) {
+		i.TickStepsList = make([]InstrumentV2TickStepsList, TickStepsListNumInGroup)
+	}
+	i.TickStepsList = i.TickStepsList[:TickStepsListNumInGroup]
+	for j := range i.TickStepsList {
+		if err := i.TickStepsList[j].DecodeBytes(_d, actingVersion, uint(TickStepsListBlockLength)); err != nil {
+			return err
+		}
+	}
+
+	InstrumentNameLength := _d.Uint8()
+	if err := _d.Need(int(InstrumentNameLength)); err != nil {
+		return err
+	}
+	if cap(i.InstrumentName) < int(InstrumentNameLength) {
+		i.InstrumentName = make([]uint8, InstrumentNameLength)
+	}
+	i.InstrumentName = i.InstrumentName[:InstrumentNameLength]
+	_d.Bytes(i.InstrumentName)
+
+	if err := _d.Err(); err != nil {
+		return err
+	}
 	if doRangeCheck {
 		if err := i.RangeCheck(); err != nil {
 			return err
@@ -159,152 +402,183 @@ func (i *InstrumentV2) Decode(
 
 func (i *InstrumentV2) RangeCheck() error {
 	if i.InstrumentId < i.InstrumentIdMinValue() || i.InstrumentId > i.InstrumentIdMaxValue() {
-		return fmt.Errorf("Range check failed on i.InstrumentId (%v < %v > %v)", i.InstrumentIdMinValue(), i.InstrumentId, i.InstrumentIdMaxValue())
+		return fmt.Errorf("%w on i.InstrumentId (%v < %v > %v)", ErrRangeCheck, i.InstrumentIdMinValue(), i.InstrumentId, i.InstrumentIdMaxValue())
 	}
+
 	if err := i.InstrumentState.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.Kind.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.InstrumentType.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.OptionType.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := i.SettlementPeriod.RangeCheck(); err != nil {
 		return err
 	}
+
 	if i.SettlementPeriodCount < i.SettlementPeriodCountMinValue() || i.SettlementPeriodCount > i.SettlementPeriodCountMaxValue() {
-		return fmt.Errorf("Range check failed on i.SettlementPeriodCount (%v < %v > %v)", i.SettlementPeriodCountMinValue(), i.SettlementPeriodCount, i.SettlementPeriodCountMaxValue())
+		return fmt.Errorf("%w on i.SettlementPeriodCount (%v < %v > %v)", ErrRangeCheck, i.SettlementPeriodCountMinValue(), i.SettlementPeriodCount, i.SettlementPeriodCountMaxValue())
 	}
+
 	for idx := 0; idx < 8; idx++ {
 		if i.BaseCurrency[idx] == byte(0) {
 			break
 		}
 		if i.BaseCurrency[idx] < i.BaseCurrencyMinValue() || i.BaseCurrency[idx] > i.BaseCurrencyMaxValue() {
-			return fmt.Errorf("Range check failed on i.BaseCurrency[%d] (%v < %v > %v)", idx, i.BaseCurrencyMinValue(), i.BaseCurrency[idx], i.BaseCurrencyMaxValue())
-		}
-	}
-	for idx, ch := range i.BaseCurrency {
-		if ch > 127 {
-			return fmt.Errorf("i.BaseCurrency[%d]=%d failed ASCII validation", idx, ch)
+			return fmt.Errorf("%w on i.BaseCurrency[%d] (%v < %v > %v)", ErrRangeCheck, idx, i.BaseCurrencyMinValue(), i.BaseCurrency[idx], i.BaseCurrencyMaxValue())
 		}
 	}
+
 	for idx := 0; idx < 8; idx++ {
 		if i.QuoteCurrency[idx] == byte(0) {
 			break
 		}
 		if i.QuoteCurrency[idx] < i.QuoteCurrencyMinValue() || i.QuoteCurrency[idx] > i.QuoteCurrencyMaxValue() {
-			return fmt.Errorf("Range check failed on i.QuoteCurrency[%d] (%v < %v > %v)", idx, i.QuoteCurrencyMinValue(), i.QuoteCurrency[idx], i.QuoteCurrencyMaxValue())
-		}
-	}
-	for idx, ch := range i.QuoteCurrency {
-		if ch > 127 {
-			return fmt.Errorf("i.QuoteCurrency[%d]=%d failed ASCII validation", idx, ch)
+			return fmt.Errorf("%w on i.QuoteCurrency[%d] (%v < %v > %v)", ErrRangeCheck, idx, i.QuoteCurrencyMinValue(), i.QuoteCurrency[idx], i.QuoteCurrencyMaxValue())
 		}
 	}
+
 	for idx := 0; idx < 8; idx++ {
 		if i.CounterCurrency[idx] == byte(0) {
 			break
 		}
 		if i.CounterCurrency[idx] < i.CounterCurrencyMinValue() || i.CounterCurrency[idx] > i.CounterCurrencyMaxValue() {
-			return fmt.Errorf("Range check failed on i.CounterCurrency[%d] (%v < %v > %v)", idx, i.CounterCurrencyMinValue(), i.CounterCurrency[idx], i.CounterCurrencyMaxValue())
-		}
-	}
-	for idx, ch := range i.CounterCurrency {
-		if ch > 127 {
-			return fmt.Errorf("i.CounterCurrency[%d]=%d failed ASCII validation", idx, ch)
+			return fmt.Errorf("%w on i.CounterCurrency[%d] (%v < %v > %v)", ErrRangeCheck, idx, i.CounterCurrencyMinValue(), i.CounterCurrency[idx], i.CounterCurrencyMaxValue())
 		}
 	}
+
 	for idx := 0; idx < 8; idx++ {
 		if i.SettlementCurrency[idx] == byte(0) {
 			break
 		}
 		if i.SettlementCurrency[idx] < i.SettlementCurrencyMinValue() || i.SettlementCurrency[idx] > i.SettlementCurrencyMaxValue() {
-			return fmt.Errorf("Range check failed on i.SettlementCurrency[%d] (%v < %v > %v)", idx, i.SettlementCurrencyMinValue(), i.SettlementCurrency[idx], i.SettlementCurrencyMaxValue())
-		}
-	}
-	for idx, ch := range i.SettlementCurrency {
-		if ch > 127 {
-			return fmt.Errorf("i.SettlementCurrency[%d]=%d failed ASCII validation", idx, ch)
+			return fmt.Errorf("%w on i.SettlementCurrency[%d] (%v < %v > %v)", ErrRangeCheck, idx, i.SettlementCurrencyMinValue(), i.SettlementCurrency[idx], i.SettlementCurrencyMaxValue())
 		}
 	}
+
 	for idx := 0; idx < 8; idx++ {
 		if i.SizeCurrency[idx] == byte(0) {
 			break
 		}
 		if i.SizeCurrency[idx] < i.SizeCurrencyMinValue() || i.SizeCurrency[idx] > i.SizeCurrencyMaxValue() {
-			return fmt.Errorf("Range check failed on i.SizeCurrency[%d] (%v < %v > %v)", idx, i.SizeCurrencyMinValue(), i.SizeCurrency[idx], i.SizeCurrencyMaxValue())
-		}
-	}
-	for idx, ch := range i.SizeCurrency {
-		if ch > 127 {
-			return fmt.Errorf("i.SizeCurrency[%d]=%d failed ASCII validation", idx, ch)
+			return fmt.Errorf("%w on i.SizeCurrency[%d] (%v < %v > %v)", ErrRangeCheck, idx, i.SizeCurrencyMinValue(), i.SizeCurrency[idx], i.SizeCurrencyMaxValue())
 		}
 	}
+
 	if i.CreationTimestampMs < i.CreationTimestampMsMinValue() || i.CreationTimestampMs > i.CreationTimestampMsMaxValue() {
-		return fmt.Errorf("Range check failed on i.CreationTimestampMs (%v < %v > %v)", i.CreationTimestampMsMinValue(), i.CreationTimestampMs, i.CreationTimestampMsMaxValue())
+		return fmt.Errorf("%w on i.CreationTimestampMs (%v < %v > %v)", ErrRangeCheck, i.CreationTimestampMsMinValue(), i.CreationTimestampMs, i.CreationTimestampMsMaxValue())
 	}
+
 	if i.ExpirationTimestampMs < i.ExpirationTimestampMsMinValue() || i.ExpirationTimestampMs > i.ExpirationTimestampMsMaxValue() {
-		return fmt.Errorf("Range check failed on i.ExpirationTimestampMs (%v < %v > %v)", i.ExpirationTimestampMsMinValue(), i.ExpirationTimestampMs, i.ExpirationTimestampMsMaxValue())
+		return fmt.Errorf("%w on i.ExpirationTimestampMs (%v < %v > %v)", ErrRangeCheck, i.ExpirationTimestampMsMinValue(), i.ExpirationTimestampMs, i.ExpirationTimestampMsMaxValue())
 	}
+
 	if i.StrikePrice != i.StrikePriceNullValue() && (i.StrikePrice < i.StrikePriceMinValue() || i.StrikePrice > i.StrikePriceMaxValue()) {
-		return fmt.Errorf("Range check failed on i.StrikePrice (%v < %v > %v)", i.StrikePriceMinValue(), i.StrikePrice, i.StrikePriceMaxValue())
+		return fmt.Errorf("%w on i.StrikePrice (%v < %v > %v)", ErrRangeCheck, i.StrikePriceMinValue(), i.StrikePrice, i.StrikePriceMaxValue())
 	}
+
 	if i.ContractSize < i.ContractSizeMinValue() || i.ContractSize > i.ContractSizeMaxValue() {
-		return fmt.Errorf("Range check failed on i.ContractSize (%v < %v > %v)", i.ContractSizeMinValue(), i.ContractSize, i.ContractSizeMaxValue())
+		return fmt.Errorf("%w on i.ContractSize (%v < %v > %v)", ErrRangeCheck, i.ContractSizeMinValue(), i.ContractSize, i.ContractSizeMaxValue())
 	}
+
 	if i.MinTradeAmount < i.MinTradeAmountMinValue() || i.MinTradeAmount > i.MinTradeAmountMaxValue() {
-		return fmt.Errorf("Range check failed on i.MinTradeAmount (%v < %v > %v)", i.MinTradeAmountMinValue(), i.MinTradeAmount, i.MinTradeAmountMaxValue())
+		return fmt.Errorf("%w on i.MinTradeAmount (%v < %v > %v)", ErrRangeCheck, i.MinTradeAmountMinValue(), i.MinTradeAmount, i.MinTradeAmountMaxValue())
 	}
+
 	if i.TickSize < i.TickSizeMinValue() || i.TickSize > i.TickSizeMaxValue() {
-		return fmt.Errorf("Range check failed on i.TickSize (%v < %v > %v)", i.TickSizeMinValue(), i.TickSize, i.TickSizeMaxValue())
+		return fmt.Errorf("%w on i.TickSize (%v < %v > %v)", ErrRangeCheck, i.TickSizeMinValue(), i.TickSize, i.TickSizeMaxValue())
 	}
+
 	if i.MakerCommission < i.MakerCommissionMinValue() || i.MakerCommission > i.MakerCommissionMaxValue() {
-		return fmt.Errorf("Range check failed on i.MakerCommission (%v < %v > %v)", i.MakerCommissionMinValue(), i.MakerCommission, i.MakerCommissionMaxValue())
+		return fmt.Errorf("%w on i.MakerCommission (%v < %v > %v)", ErrRangeCheck, i.MakerCommissionMinValue(), i.MakerCommission, i.MakerCommissionMaxValue())
 	}
+
 	if i.TakerCommission < i.TakerCommissionMinValue() || i.TakerCommission > i.TakerCommissionMaxValue() {
-		return fmt.Errorf("Range check failed on i.TakerCommission (%v < %v > %v)", i.TakerCommissionMinValue(), i.TakerCommission, i.TakerCommissionMaxValue())
+		return fmt.Errorf("%w on i.TakerCommission (%v < %v > %v)", ErrRangeCheck, i.TakerCommissionMinValue(), i.TakerCommission, i.TakerCommissionMaxValue())
 	}
+
 	if i.BlockTradeCommission != i.BlockTradeCommissionNullValue() && (i.BlockTradeCommission < i.BlockTradeCommissionMinValue() || i.BlockTradeCommission > i.BlockTradeCommissionMaxValue()) {
-		return fmt.Errorf("Range check failed on i.BlockTradeCommission (%v < %v > %v)", i.BlockTradeCommissionMinValue(), i.BlockTradeCommission, i.BlockTradeCommissionMaxValue())
+		return fmt.Errorf("%w on i.BlockTradeCommission (%v < %v > %v)", ErrRangeCheck, i.BlockTradeCommissionMinValue(), i.BlockTradeCommission, i.BlockTradeCommissionMaxValue())
 	}
+
 	if i.MaxLiquidationCommission != i.MaxLiquidationCommissionNullValue() && (i.MaxLiquidationCommission < i.MaxLiquidationCommissionMinValue() || i.MaxLiquidationCommission > i.MaxLiquidationCommissionMaxValue()) {
-		return fmt.Errorf("Range check failed on i.MaxLiquidationCommission (%v < %v > %v)", i.MaxLiquidationCommissionMinValue(), i.MaxLiquidationCommission, i.MaxLiquidationCommissionMaxValue())
+		return fmt.Errorf("%w on i.MaxLiquidationCommission (%v < %v > %v)", ErrRangeCheck, i.MaxLiquidationCommissionMinValue(), i.MaxLiquidationCommission, i.MaxLiquidationCommissionMaxValue())
 	}
+
 	if i.MaxLeverage != i.MaxLeverageNullValue() && (i.MaxLeverage < i.MaxLeverageMinValue() || i.MaxLeverage > i.MaxLeverageMaxValue()) {
-		return fmt.Errorf("Range check failed on i.MaxLeverage (%v < %v > %v)", i.MaxLeverageMinValue(), i.MaxLeverage, i.MaxLeverageMaxValue())
+		return fmt.Errorf("%w on i.MaxLeverage (%v < %v > %v)", ErrRangeCheck, i.MaxLeverageMinValue(), i.MaxLeverage, i.MaxLeverageMaxValue())
 	}
+
 	for _, prop := range i.TickStepsList {
 		if err := prop.RangeCheck(); err != nil {
 			return err
 		}
 	}
+
+	return nil
+}
+
+func (i *InstrumentV2TickStepsList) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := _m.WriteFloat64(_w, i.AbovePrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, i.TickSize); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (i *InstrumentV2TickStepsList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint) error {
+	return i.DecodeVersion(_m, _r, SchemaVersion, blockLength)
+}
+
+func (i *InstrumentV2TickStepsList) DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint) error {
 	if err := _m.ReadFloat64(_r, &i.AbovePrice); err != nil {
 		return err
 	}
+
 	if err := _m.ReadFloat64(_r, &i.TickSize); err != nil {
 		return err
 	}
+
 	if blockLength > i.SbeBlockLength() {
-		io.CopyN(ioutil.Discard, _r, int64(blockLength-i.SbeBlockLength()))
+		_, _ = io.CopyN(ioutil.Discard, _r, int64(blockLength-i.SbeBlockLength()))
 	}
+
 	return nil
 }
 
+func (i *InstrumentV2TickStepsList) DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint) error {
+	i.AbovePrice = _d.Float64()
+	i.TickSize = _d.Float64()
+
+	if blockLength > i.SbeBlockLength() {
+		_d.Skip(int(blockLength - i.SbeBlockLength()))
+	}
+
+	return _d.Err()
+}
+
 func (i *InstrumentV2TickStepsList) RangeCheck() error {
 	if i.AbovePrice < i.AbovePriceMinValue() || i.AbovePrice > i.AbovePriceMaxValue() {
-		return fmt.Errorf("Range check failed on i.AbovePrice (%v < %v > %v)", i.AbovePriceMinValue(), i.AbovePrice, i.AbovePriceMaxValue())
+		return fmt.Errorf("%w on i.AbovePrice (%v < %v > %v)", ErrRangeCheck, i.AbovePriceMinValue(), i.AbovePrice, i.AbovePriceMaxValue())
 	}
+
 	if i.TickSize < i.TickSizeMinValue() || i.TickSize > i.TickSizeMaxValue() {
-		return fmt.Errorf("Range check failed on i.TickSize (%v < %v > %v)", i.TickSizeMinValue(), i.TickSize, i.TickSizeMaxValue())
+		return fmt.Errorf("%w on i.TickSize (%v < %v > %v)", ErrRangeCheck, i.TickSizeMinValue(), i.TickSize, i.TickSizeMaxValue())
 	}
+
 	return nil
 }
 
@@ -312,6 +586,18 @@ func (*InstrumentV2) SbeBlockLength() (blockLength uint16) {
 	return 139
 }
 
+func (*InstrumentV2) SbeTemplateId() (templateId uint16) {
+	return 1010
+}
+
+func (*InstrumentV2) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*InstrumentV2) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*InstrumentV2) InstrumentIdMinValue() uint32 {
 	return 0
 }
@@ -472,10 +758,6 @@ func (*InstrumentV2) MaxLeverageNullValue() float64 {
 	return math.NaN()
 }
 
-func (i *InstrumentV2) IsActive() bool {
-	return i.InstrumentState.IsActive() && i.ExpirationTimestampMs > uint64(time.Now().UnixMilli())
-}
-
 func (*InstrumentV2TickStepsList) AbovePriceMinValue() float64 {
 	return -math.MaxFloat64
 }
@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -15,6 +17,34 @@ type MessageHeader struct {
 	NumVarDataFields uint16
 }
 
+func (m *MessageHeader) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := _m.WriteUint16(_w, m.BlockLength); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, m.TemplateId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, m.SchemaId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, m.Version); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, m.NumGroups); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, m.NumVarDataFields); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (m *MessageHeader) Decode(_m *SbeGoMarshaller, _r io.Reader) error {
 	if err := _m.ReadUint16(_r, &m.BlockLength); err != nil {
 		return err
@@ -43,6 +73,16 @@ func (m *MessageHeader) Decode(_m *SbeGoMarshaller, _r io.Reader) error {
 	return nil
 }
 
+func (m *MessageHeader) DecodeBytes(_d *SbeGoDecoder) error {
+	m.BlockLength = _d.Uint16()
+	m.TemplateId = _d.Uint16()
+	m.SchemaId = _d.Uint16()
+	m.Version = _d.Uint16()
+	m.NumGroups = _d.Uint16()
+	m.NumVarDataFields = _d.Uint16()
+	return _d.Err()
+}
+
 func (m *MessageHeader) RangeCheck() error {
 	if m.BlockLength < m.BlockLengthMinValue() || m.BlockLength > m.BlockLengthMaxValue() {
 		return fmt.Errorf("%w on m.BlockLength (%v < %v > %v)", ErrRangeCheck, m.BlockLengthMinValue(), m.BlockLength, m.BlockLengthMaxValue())
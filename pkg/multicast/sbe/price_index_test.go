@@ -0,0 +1,72 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePriceIndex(t *testing.T) {
+	tests := []struct {
+		event          []byte
+		expectedOutput PriceIndex
+		expectedError  error
+	}{
+		{
+			[]byte{
+				0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x62, 0x74, 0x63, 0x5f,
+				0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7b, 0x14, 0xae, 0x47,
+				0x61, 0x4e, 0xd3, 0x40, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			PriceIndex{
+				IndexName: [16]byte{
+					0x62, 0x74, 0x63, 0x5f, 0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				},
+				Price:       19769.52,
+				TimestampMs: 1662371873911,
+			},
+			nil,
+		},
+		{
+			[]byte{
+				0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x62, 0x74, 0x63, 0x5f,
+				0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7b, 0x14, 0xae, 0x47,
+			},
+			PriceIndex{},
+			io.ErrUnexpectedEOF,
+		},
+		{
+			[]byte{
+				0x20, 0x00, 0xf0, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x62, 0x74, 0x63, 0x1f,
+				0x75, 0x73, 0x64, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x7b, 0x14, 0xae, 0x47,
+				0x61, 0x4e, 0xd3, 0x40, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			PriceIndex{},
+			ErrRangeCheck, // IndexName
+		},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header MessageHeader
+		err := header.Decode(marshaller, bufferData)
+		require.NoError(t, err)
+
+		err = header.RangeCheck()
+		require.NoError(t, err)
+
+		var priceIndex PriceIndex
+		err = priceIndex.Decode(marshaller, bufferData, header.BlockLength, true)
+		require.ErrorIs(t, err, test.expectedError)
+
+		if err == nil {
+			assert.Equal(t, priceIndex, test.expectedOutput)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRfq(t *testing.T) {
+	tests := []struct {
+		event          []byte
+		expectedOutput Rfq
+		expectedError  error
+	}{
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x84, 0x0e, 0x41, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			Rfq{
+				InstrumentId: 210838,
+				State:        YesNo.Yes,
+				Side:         Direction.Buy,
+				Amount:       250000,
+				TimestampMs:  1662371873911,
+			},
+			nil,
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x00, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			Rfq{
+				InstrumentId: 210838,
+				State:        YesNo.No,
+				Side:         Direction.NullValue,
+				Amount:       0,
+				TimestampMs:  1662371873911,
+			},
+			nil,
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+			},
+			Rfq{},
+			io.ErrUnexpectedEOF,
+		},
+		{
+			[]byte{
+				0x16, 0x00, 0xf1, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+				0x01, 0x03, 0x00, 0x00, 0x00, 0x00, 0x80, 0x84, 0x0e, 0x41, 0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01,
+				0x00, 0x00,
+			},
+			Rfq{},
+			ErrRangeCheck, // Side
+		},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header MessageHeader
+		err := header.Decode(marshaller, bufferData)
+		require.NoError(t, err)
+
+		err = header.RangeCheck()
+		require.NoError(t, err)
+
+		var rfq Rfq
+		err = rfq.Decode(marshaller, bufferData, header.BlockLength, true)
+		require.ErrorIs(t, err, test.expectedError)
+
+		if err == nil {
+			assert.Equal(t, rfq, test.expectedOutput)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package sbe
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// SbeGoDecoder reads little-endian values from a byte slice at an advancing offset.
+//
+// It is the allocation free counterpart of SbeGoMarshaller used by the DecodeBytes methods.
+// The first read past the end of the slice records io.EOF, or io.ErrUnexpectedEOF if only part of
+// the value is left, and every following read returns zero values, so callers check Err once
+// after a sequence of reads.
+type SbeGoDecoder struct {
+	buf []byte
+	pos int
+	err error
+}
+
+// NewSbeGoDecoder returns a decoder reading buf from the start.
+func NewSbeGoDecoder(buf []byte) *SbeGoDecoder {
+	var d SbeGoDecoder
+	d.Reset(buf)
+	return &d
+}
+
+// Reset makes the decoder read buf from the start and clears its error.
+func (d *SbeGoDecoder) Reset(buf []byte) {
+	d.buf = buf
+	d.pos = 0
+	d.err = nil
+}
+
+// Err returns the error of the first failed read.
+func (d *SbeGoDecoder) Err() error {
+	return d.err
+}
+
+// Offset returns the number of bytes read so far.
+func (d *SbeGoDecoder) Offset() int {
+	return d.pos
+}
+
+// Len returns the number of unread bytes.
+func (d *SbeGoDecoder) Len() int {
+	return len(d.buf) - d.pos
+}
+
+func (d *SbeGoDecoder) fail() {
+	if d.err == nil {
+		if d.pos == len(d.buf) {
+			d.err = io.EOF
+		} else {
+			d.err = io.ErrUnexpectedEOF
+		}
+	}
+	d.pos = len(d.buf)
+}
+
+// Need fails with io.ErrUnexpectedEOF when less than n bytes are left, it is used to validate
+// the size of a repeating group before allocating its entries.
+func (d *SbeGoDecoder) Need(n int) error {
+	if d.err == nil && n > len(d.buf)-d.pos {
+		d.err = io.ErrUnexpectedEOF
+		d.pos = len(d.buf)
+	}
+	return d.err
+}
+
+// Skip discards n bytes, or all the remaining bytes if there are less than n.
+func (d *SbeGoDecoder) Skip(n int) {
+	if n > len(d.buf)-d.pos {
+		n = len(d.buf) - d.pos
+	}
+	d.pos += n
+}
+
+func (d *SbeGoDecoder) Uint8() uint8 {
+	if d.pos >= len(d.buf) {
+		d.fail()
+		return 0
+	}
+	v := d.buf[d.pos]
+	d.pos++
+	return v
+}
+
+func (d *SbeGoDecoder) Uint16() uint16 {
+	if len(d.buf)-d.pos < 2 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint16(d.buf[d.pos:])
+	d.pos += 2
+	return v
+}
+
+func (d *SbeGoDecoder) Uint32() uint32 {
+	if len(d.buf)-d.pos < 4 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(d.buf[d.pos:])
+	d.pos += 4
+	return v
+}
+
+func (d *SbeGoDecoder) Uint64() uint64 {
+	if len(d.buf)-d.pos < 8 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.buf[d.pos:])
+	d.pos += 8
+	return v
+}
+
+func (d *SbeGoDecoder) Int8() int8 {
+	return int8(d.Uint8())
+}
+
+func (d *SbeGoDecoder) Int16() int16 {
+	return int16(d.Uint16())
+}
+
+func (d *SbeGoDecoder) Int32() int32 {
+	return int32(d.Uint32())
+}
+
+func (d *SbeGoDecoder) Int64() int64 {
+	return int64(d.Uint64())
+}
+
+func (d *SbeGoDecoder) Float32() float32 {
+	return math.Float32frombits(d.Uint32())
+}
+
+func (d *SbeGoDecoder) Float64() float64 {
+	return math.Float64frombits(d.Uint64())
+}
+
+// Bytes fills b with the next len(b) bytes.
+func (d *SbeGoDecoder) Bytes(b []byte) {
+	if len(d.buf)-d.pos < len(b) {
+		d.fail()
+		return
+	}
+	d.pos += copy(b, d.buf[d.pos:])
+}
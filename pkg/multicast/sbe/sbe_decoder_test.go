@@ -0,0 +1,193 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// A book event with one change and a trades event of ETH-PERPETUAL, both with their message header.
+var (
+	testBookEvent = []byte{
+		0x1d, 0x00, 0xe9, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x96, 0x37, 0x03, 0x00,
+		0x77, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00, 0x3c, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00,
+		0x3d, 0x25, 0x7a, 0x7f, 0x0b, 0x00, 0x00, 0x00, 0x01, 0x12, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x60, 0x4e, 0xd3, 0x40, 0x00, 0x00, 0x00, 0x00, 0xc0,
+		0x4f, 0xed, 0x40,
+	}
+	testTradesEvent = []byte{
+		0x04, 0x00, 0xea, 0x03, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x48, 0x37, 0x03, 0x00,
+		0x53, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x9a, 0x99, 0x99, 0x99, 0x99, 0xe3, 0x99,
+		0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xa6, 0x40, 0xc6, 0x17, 0xfd, 0x11, 0x83, 0x01, 0x00,
+		0x00, 0x1f, 0x85, 0xeb, 0x51, 0xb8, 0xe2, 0x99, 0x40, 0x1f, 0x85, 0xeb, 0x51, 0xb8, 0xe7, 0x99,
+		0x40, 0xf6, 0xbe, 0x4d, 0x06, 0x00, 0x00, 0x00, 0x00, 0x5b, 0xa2, 0x84, 0x08, 0x00, 0x00, 0x00,
+		0x00, 0x01, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	}
+)
+
+type bytesMessage interface {
+	sbeMessage
+	DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool) error
+	DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool) error
+}
+
+func TestSbeGoDecoder(t *testing.T) {
+	d := NewSbeGoDecoder([]byte{
+		0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xf8, 0x7f, 0x61,
+		0x62, 0x63, 0x64,
+	})
+
+	assert.Equal(t, uint8(0x01), d.Uint8())
+	assert.Equal(t, uint16(0x0302), d.Uint16())
+	assert.Equal(t, int32(0x07060504), d.Int32())
+	assert.True(t, math.IsNaN(d.Float64()))
+	assert.Equal(t, 15, d.Offset())
+	assert.Equal(t, 4, d.Len())
+	require.NoError(t, d.Err())
+
+	require.ErrorIs(t, d.Need(5), io.ErrUnexpectedEOF)
+	assert.Zero(t, d.Uint8())
+	assert.Zero(t, d.Len())
+
+	d.Reset([]byte{0x7f, 0x61, 0x62, 0x63})
+	d.Skip(1)
+	require.NoError(t, d.Need(3))
+	b := make([]byte, 3)
+	d.Bytes(b)
+	assert.Equal(t, "abc", string(b))
+	require.NoError(t, d.Err())
+
+	// Reading at the end fails with io.EOF, reading a part of a value with io.ErrUnexpectedEOF.
+	assert.Zero(t, d.Uint64())
+	require.ErrorIs(t, d.Err(), io.EOF)
+
+	d.Reset([]byte{0x01})
+	assert.Zero(t, d.Uint16())
+	require.ErrorIs(t, d.Err(), io.ErrUnexpectedEOF)
+	d.Skip(10)
+	assert.Zero(t, d.Uint8())
+	require.ErrorIs(t, d.Err(), io.ErrUnexpectedEOF)
+}
+
+func TestDecodeBytes(t *testing.T) {
+	tests := []struct {
+		event             []byte
+		newMessage        func() bytesMessage
+		decoded, expected bytesMessage
+	}{
+		{testBookEvent, func() bytesMessage { return &Book{} }, &Book{}, &Book{}},
+		{testTradesEvent, func() bytesMessage { return &Trades{} }, &Trades{}, &Trades{}},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		var header MessageHeader
+		reader := bytes.NewReader(test.event)
+		require.NoError(t, header.Decode(marshaller, reader))
+		require.NoError(t, test.expected.DecodeVersion(marshaller, reader, header.Version, header.BlockLength, true))
+
+		d := NewSbeGoDecoder(test.event)
+		var bytesHeader MessageHeader
+		require.NoError(t, bytesHeader.DecodeBytes(d))
+		require.Equal(t, header, bytesHeader)
+		require.NoError(t, test.decoded.DecodeBytes(d, header.Version, header.BlockLength, true))
+		require.Zero(t, d.Len())
+
+		// Compare the encoded messages, the decoded ones may hold NaN.
+		var expected, decoded bytes.Buffer
+		require.NoError(t, test.expected.Encode(marshaller, &expected, false))
+		require.NoError(t, test.decoded.Encode(marshaller, &decoded, false))
+		assert.Equal(t, expected.Bytes(), decoded.Bytes())
+
+		// Both paths fail on every truncated message.
+		for n := 8; n < len(test.event); n++ {
+			err := test.newMessage().Decode(marshaller, bytes.NewReader(test.event[8:n]), header.BlockLength, true)
+			require.Error(t, err)
+
+			d.Reset(test.event[8:n])
+			err = test.newMessage().DecodeBytes(d, header.Version, header.BlockLength, true)
+			require.Error(t, err)
+			require.True(t, err == io.EOF || err == io.ErrUnexpectedEOF, err)
+		}
+	}
+}
+
+func TestDecodeBytesAllocs(t *testing.T) {
+	var (
+		d      SbeGoDecoder
+		header MessageHeader
+		book   Book
+	)
+	decode := func() {
+		d.Reset(testBookEvent)
+		_ = header.DecodeBytes(&d)
+		if err := book.DecodeBytes(&d, header.Version, header.BlockLength, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	decode()
+	assert.Zero(t, testing.AllocsPerRun(100, decode))
+}
+
+// benchmarkDecode decodes like the multicast client did before DecodeBytes, into a new message
+// from a new reader for every event.
+func benchmarkDecode(b *testing.B, event []byte, newMessage func() sbeMessage) {
+	b.Helper()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(event)))
+
+	marshaller := NewSbeGoMarshaller()
+	for i := 0; i < b.N; i++ {
+		var header MessageHeader
+		reader := bytes.NewBuffer(event)
+		if err := header.Decode(marshaller, reader); err != nil {
+			b.Fatal(err)
+		}
+		if err := newMessage().Decode(marshaller, reader, header.BlockLength, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkDecodeBytes(b *testing.B, event []byte, message bytesMessage) {
+	b.Helper()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(event)))
+
+	var (
+		d      SbeGoDecoder
+		header MessageHeader
+	)
+	for i := 0; i < b.N; i++ {
+		d.Reset(event)
+		if err := header.DecodeBytes(&d); err != nil {
+			b.Fatal(err)
+		}
+		if err := message.DecodeBytes(&d, header.Version, header.BlockLength, true); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeBook(b *testing.B) {
+	benchmarkDecode(b, testBookEvent, func() sbeMessage { return &Book{} })
+}
+
+func BenchmarkDecodeBytesBook(b *testing.B) {
+	benchmarkDecodeBytes(b, testBookEvent, &Book{})
+}
+
+func BenchmarkDecodeTrades(b *testing.B) {
+	benchmarkDecode(b, testTradesEvent, func() sbeMessage { return &Trades{} })
+}
+
+func BenchmarkDecodeBytesTrades(b *testing.B) {
+	benchmarkDecodeBytes(b, testTradesEvent, &Trades{})
+}
@@ -58,6 +58,70 @@ type SbeGoMessageHeader struct {
 	Version     uint16
 }
 
+func (m *SbeGoMarshaller) WriteUint8(w io.Writer, v uint8) error {
+	m.b1[0] = byte(v)
+	_, err := w.Write(m.b1)
+	return err
+}
+
+func (m *SbeGoMarshaller) WriteUint16(w io.Writer, v uint16) error {
+	m.b2[0] = byte(v)
+	m.b2[1] = byte(v >> 8)
+	_, err := w.Write(m.b2)
+	return err
+}
+
+func (m *SbeGoMarshaller) WriteUint32(w io.Writer, v uint32) error {
+	m.b4[0] = byte(v)
+	m.b4[1] = byte(v >> 8)
+	m.b4[2] = byte(v >> 16)
+	m.b4[3] = byte(v >> 24)
+	_, err := w.Write(m.b4)
+	return err
+}
+
+func (m *SbeGoMarshaller) WriteUint64(w io.Writer, v uint64) error {
+	m.b8[0] = byte(v)
+	m.b8[1] = byte(v >> 8)
+	m.b8[2] = byte(v >> 16)
+	m.b8[3] = byte(v >> 24)
+	m.b8[4] = byte(v >> 32)
+	m.b8[5] = byte(v >> 40)
+	m.b8[6] = byte(v >> 48)
+	m.b8[7] = byte(v >> 56)
+	_, err := w.Write(m.b8)
+	return err
+}
+
+func (m *SbeGoMarshaller) WriteInt8(w io.Writer, v int8) error {
+	return m.WriteUint8(w, uint8(v))
+}
+
+func (m *SbeGoMarshaller) WriteInt16(w io.Writer, v int16) error {
+	return m.WriteUint16(w, uint16(v))
+}
+
+func (m *SbeGoMarshaller) WriteInt32(w io.Writer, v int32) error {
+	return m.WriteUint32(w, uint32(v))
+}
+
+func (m *SbeGoMarshaller) WriteInt64(w io.Writer, v int64) error {
+	return m.WriteUint64(w, uint64(v))
+}
+
+func (m *SbeGoMarshaller) WriteFloat32(w io.Writer, v float32) error {
+	return m.WriteUint32(w, math.Float32bits(v))
+}
+
+func (m *SbeGoMarshaller) WriteFloat64(w io.Writer, v float64) error {
+	return m.WriteUint64(w, math.Float64bits(v))
+}
+
+func (m *SbeGoMarshaller) WriteBytes(w io.Writer, v []byte) error {
+	_, err := w.Write(v)
+	return err
+}
+
 func (m *SbeGoMarshaller) ReadUint8(r io.Reader, v *uint8) error {
 	if _, err := io.ReadFull(r, m.b1); err != nil {
 		return err
@@ -111,3 +175,48 @@ func (m *SbeGoMarshaller) ReadBytes(r io.Reader, b []byte) error {
 	}
 	return nil
 }
+
+func (m *SbeGoMarshaller) ReadInt32(r io.Reader, v *int32) error {
+	if _, err := io.ReadFull(r, m.b4); err != nil {
+		return err
+	}
+	*v = int32(uint32(m.b4[0]) | uint32(m.b4[1])<<8 |
+		uint32(m.b4[2])<<16 | uint32(m.b4[3])<<24)
+	return nil
+}
+
+func (m *SbeGoMarshaller) ReadInt8(r io.Reader, v *int8) error {
+	var u uint8
+	if err := m.ReadUint8(r, &u); err != nil {
+		return err
+	}
+	*v = int8(u)
+	return nil
+}
+
+func (m *SbeGoMarshaller) ReadInt16(r io.Reader, v *int16) error {
+	var u uint16
+	if err := m.ReadUint16(r, &u); err != nil {
+		return err
+	}
+	*v = int16(u)
+	return nil
+}
+
+func (m *SbeGoMarshaller) ReadInt64(r io.Reader, v *int64) error {
+	var u uint64
+	if err := m.ReadUint64(r, &u); err != nil {
+		return err
+	}
+	*v = int64(u)
+	return nil
+}
+
+func (m *SbeGoMarshaller) ReadFloat32(r io.Reader, v *float32) error {
+	var u uint32
+	if err := m.ReadUint32(r, &u); err != nil {
+		return err
+	}
+	*v = math.Float32frombits(u)
+	return nil
+}
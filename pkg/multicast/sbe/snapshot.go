@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -15,13 +17,77 @@ type Snapshot struct {
 	IsLastInBook   YesNoEnum
 	LevelsList     []SnapshotLevelsList
 }
+
 type SnapshotLevelsList struct {
 	Side   BookSideEnum
 	Price  float64
 	Amount float64
 }
 
+func (s *Snapshot) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := s.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, s.InstrumentId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, s.TimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, s.ChangeId); err != nil {
+		return err
+	}
+
+	if err := s.IsBookComplete.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := s.IsLastInBook.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if len(s.LevelsList) > math.MaxUint16 {
+		return fmt.Errorf("%w on s.LevelsList length (%v > %v)", ErrRangeCheck, len(s.LevelsList), math.MaxUint16)
+	}
+
+	if err := _m.WriteUint16(_w, 17); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, uint16(len(s.LevelsList))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	for i := range s.LevelsList {
+		if err := s.LevelsList[i].Encode(_m, _w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *Snapshot) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return s.DecodeVersion(_m, _r, s.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (s *Snapshot) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
 	if err := _m.ReadUint32(_r, &s.InstrumentId); err != nil {
 		return err
 	}
@@ -50,6 +116,7 @@ func (s *Snapshot) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16,
 	if err := _m.ReadUint16(_r, &LevelsListBlockLength); err != nil {
 		return err
 	}
+
 	var LevelsListNumInGroup uint16
 	if err := _m.ReadUint16(_r, &LevelsListNumInGroup); err != nil {
 		return err
@@ -63,7 +130,7 @@ func (s *Snapshot) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16,
 	}
 	s.LevelsList = s.LevelsList[:LevelsListNumInGroup]
 	for i := range s.LevelsList {
-		if err := s.LevelsList[i].Decode(_m, _r, uint(LevelsListBlockLength)); err != nil {
+		if err := s.LevelsList[i].DecodeVersion(_m, _r, actingVersion, uint(LevelsListBlockLength)); err != nil {
 			return err
 		}
 	}
@@ -76,6 +143,49 @@ func (s *Snapshot) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16,
 	return nil
 }
 
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of s are reused, decoding into the same s does not allocate once
+// they are large enough.
+func (s *Snapshot) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	s.InstrumentId = _d.Uint32()
+	s.TimestampMs = _d.Uint64()
+	s.ChangeId = _d.Uint64()
+	s.IsBookComplete = YesNoEnum(_d.Uint8())
+	s.IsLastInBook = YesNoEnum(_d.Uint8())
+
+	if blockLength > s.SbeBlockLength() {
+		_d.Skip(int(blockLength - s.SbeBlockLength()))
+	}
+
+	LevelsListBlockLength := _d.Uint16()
+	LevelsListNumInGroup := _d.Uint16()
+	_d.Skip(4) // numGroups and numVars
+	if err := _d.Need(int(LevelsListNumInGroup) * int(LevelsListBlockLength)); err != nil {
+		return err
+	}
+	if cap(s.LevelsList) < int(LevelsListNumInGroup) {
+		s.LevelsList = make([]SnapshotLevelsList, LevelsListNumInGroup)
+	}
+	s.LevelsList = s.LevelsList[:LevelsListNumInGroup]
+	for i := range s.LevelsList {
+		if err := s.LevelsList[i].DecodeBytes(_d, actingVersion, uint(LevelsListBlockLength)); err != nil {
+			return err
+		}
+	}
+
+	if err := _d.Err(); err != nil {
+		return err
+	}
+	if doRangeCheck {
+		if err := s.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (s *Snapshot) RangeCheck() error {
 	if s.InstrumentId < s.InstrumentIdMinValue() || s.InstrumentId > s.InstrumentIdMaxValue() {
 		return fmt.Errorf("%w on s.InstrumentId (%v < %v > %v)", ErrRangeCheck, s.InstrumentIdMinValue(), s.InstrumentId, s.InstrumentIdMaxValue())
@@ -92,18 +202,41 @@ func (s *Snapshot) RangeCheck() error {
 	if err := s.IsBookComplete.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := s.IsLastInBook.RangeCheck(); err != nil {
 		return err
 	}
+
 	for _, prop := range s.LevelsList {
 		if err := prop.RangeCheck(); err != nil {
 			return err
 		}
 	}
+
+	return nil
+}
+
+func (s *SnapshotLevelsList) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := s.Side.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, s.Price); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, s.Amount); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func (s *SnapshotLevelsList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint) error {
+	return s.DecodeVersion(_m, _r, SchemaVersion, blockLength)
+}
+
+func (s *SnapshotLevelsList) DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint) error {
 	if err := s.Side.Decode(_m, _r); err != nil {
 		return err
 	}
@@ -123,10 +256,23 @@ func (s *SnapshotLevelsList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLeng
 	return nil
 }
 
+func (s *SnapshotLevelsList) DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint) error {
+	s.Side = BookSideEnum(_d.Uint8())
+	s.Price = _d.Float64()
+	s.Amount = _d.Float64()
+
+	if blockLength > s.SbeBlockLength() {
+		_d.Skip(int(blockLength - s.SbeBlockLength()))
+	}
+
+	return _d.Err()
+}
+
 func (s *SnapshotLevelsList) RangeCheck() error {
 	if err := s.Side.RangeCheck(); err != nil {
 		return err
 	}
+
 	if s.Price < s.PriceMinValue() || s.Price > s.PriceMaxValue() {
 		return fmt.Errorf("%w on s.Price (%v < %v > %v)", ErrRangeCheck, s.PriceMinValue(), s.Price, s.PriceMaxValue())
 	}
@@ -142,6 +288,18 @@ func (*Snapshot) SbeBlockLength() (blockLength uint16) {
 	return 22
 }
 
+func (*Snapshot) SbeTemplateId() (templateId uint16) {
+	return 1004
+}
+
+func (*Snapshot) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*Snapshot) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*Snapshot) InstrumentIdMinValue() uint32 {
 	return 0
 }
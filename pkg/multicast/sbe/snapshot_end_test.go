@@ -0,0 +1,67 @@
+package sbe
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeSnapshotEnd(t *testing.T) {
+	tests := []struct {
+		event          []byte
+		expectedOutput SnapshotEnd
+		expectedError  error
+	}{
+		{
+			[]byte{
+				0x10, 0x00, 0xee, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0xcd, 0x5b, 0x07,
+				0x00, 0x00, 0x00, 0x00, 0xdc, 0xc4, 0x15, 0x0d, 0x83, 0x01, 0x00, 0x00,
+			},
+			SnapshotEnd{
+				SnapshotId:  123456789,
+				TimestampMs: 1662371874012,
+			},
+			nil,
+		},
+		{
+			[]byte{
+				0x10, 0x00, 0xee, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0xcd, 0x5b, 0x07,
+				0x00, 0x00, 0x00, 0x00, 0xdc, 0xc4,
+			},
+			SnapshotEnd{},
+			io.ErrUnexpectedEOF,
+		},
+		{
+			[]byte{
+				0x10, 0x00, 0xee, 0x03, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x15, 0xcd, 0x5b, 0x07,
+				0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+			},
+			SnapshotEnd{},
+			ErrRangeCheck, // TimestampMs
+		},
+	}
+
+	marshaller := NewSbeGoMarshaller()
+
+	for _, test := range tests {
+		bufferData := bytes.NewBuffer(test.event)
+
+		var header MessageHeader
+		err := header.Decode(marshaller, bufferData)
+		require.NoError(t, err)
+
+		err = header.RangeCheck()
+		require.NoError(t, err)
+
+		var end SnapshotEnd
+		err = end.Decode(marshaller, bufferData, header.BlockLength, true)
+		require.ErrorIs(t, err, test.expectedError)
+
+		if err == nil {
+			assert.Equal(t, end, test.expectedOutput)
+		}
+	}
+}
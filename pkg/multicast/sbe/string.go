@@ -0,0 +1,102 @@
+package sbe
+
+// The String methods of the generated enums return the names used by the Deribit API.
+
+func (b BookChangeEnum) String() string {
+	switch b {
+	case BookChange.Created:
+		return "new"
+	case BookChange.Changed:
+		return "change"
+	case BookChange.Deleted:
+		return "delete"
+	default:
+		return ""
+	}
+}
+
+func (d DirectionEnum) String() string {
+	switch d {
+	case Direction.Buy:
+		return "buy"
+	case Direction.Sell:
+		return "sell"
+	default:
+		return ""
+	}
+}
+
+func (i InstrumentKindEnum) String() string {
+	switch i {
+	case InstrumentKind.Future:
+		return "future"
+	case InstrumentKind.Option:
+		return "option"
+	default:
+		return ""
+	}
+}
+
+func (i InstrumentStateEnum) String() string {
+	switch i {
+	case InstrumentState.Created:
+		return "created"
+	case InstrumentState.Open:
+		return "open"
+	case InstrumentState.Closed:
+		return "closed"
+	case InstrumentState.Settled:
+		return "settled"
+	default:
+		return ""
+	}
+}
+
+func (l LiquidationEnum) String() string {
+	switch l {
+	case Liquidation.None:
+		return "none"
+	case Liquidation.Maker:
+		return "maker"
+	case Liquidation.Taker:
+		return "taker"
+	case Liquidation.Both:
+		return "both"
+	default:
+		return "none"
+	}
+}
+
+func (o OptionTypeEnum) String() string {
+	switch o {
+	case OptionType.NotApplicable:
+		return "not_applicable"
+	case OptionType.Put:
+		return "put"
+	case OptionType.Call:
+		return "call"
+	default:
+		return ""
+	}
+}
+
+func (p PeriodEnum) String() string {
+	switch p {
+	case Period.Perpetual:
+		return "perpetual"
+	case Period.Minute:
+		return "minute"
+	case Period.Hour:
+		return "hour"
+	case Period.Day:
+		return "day"
+	case Period.Week:
+		return "week"
+	case Period.Month:
+		return "month"
+	case Period.Year:
+		return "year"
+	default:
+		return ""
+	}
+}
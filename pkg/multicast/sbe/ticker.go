@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -28,7 +30,96 @@ type Ticker struct {
 	SettlementPrice        float64
 }
 
+func (t *Ticker) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := t.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, t.InstrumentId); err != nil {
+		return err
+	}
+
+	if err := t.InstrumentState.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.TimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.OpenInterest); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.MinSellPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.MaxBuyPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.LastPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.IndexPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.MarkPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.BestBidPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.BestBidAmount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.BestAskPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.BestAskAmount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.CurrentFunding); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.Funding8h); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.EstimatedDeliveryPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.DeliveryPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.SettlementPrice); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (t *Ticker) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return t.DecodeVersion(_m, _r, t.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (t *Ticker) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
 	if err := _m.ReadUint32(_r, &t.InstrumentId); err != nil {
 		return err
 	}
@@ -104,6 +195,47 @@ func (t *Ticker) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, d
 	if blockLength > t.SbeBlockLength() {
 		_, _ = io.CopyN(ioutil.Discard, _r, int64(blockLength-t.SbeBlockLength()))
 	}
+
+	if doRangeCheck {
+		if err := t.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of t are reused, decoding into the same t does not allocate once
+// they are large enough.
+func (t *Ticker) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	t.InstrumentId = _d.Uint32()
+	t.InstrumentState = InstrumentStateEnum(_d.Uint8())
+	t.TimestampMs = _d.Uint64()
+	t.OpenInterest = _d.Float64()
+	t.MinSellPrice = _d.Float64()
+	t.MaxBuyPrice = _d.Float64()
+	t.LastPrice = _d.Float64()
+	t.IndexPrice = _d.Float64()
+	t.MarkPrice = _d.Float64()
+	t.BestBidPrice = _d.Float64()
+	t.BestBidAmount = _d.Float64()
+	t.BestAskPrice = _d.Float64()
+	t.BestAskAmount = _d.Float64()
+	t.CurrentFunding = _d.Float64()
+	t.Funding8h = _d.Float64()
+	t.EstimatedDeliveryPrice = _d.Float64()
+	t.DeliveryPrice = _d.Float64()
+	t.SettlementPrice = _d.Float64()
+
+	if blockLength > t.SbeBlockLength() {
+		_d.Skip(int(blockLength - t.SbeBlockLength()))
+	}
+
+	if err := _d.Err(); err != nil {
+		return err
+	}
 	if doRangeCheck {
 		if err := t.RangeCheck(); err != nil {
 			return err
@@ -192,6 +324,18 @@ func (*Ticker) SbeBlockLength() (blockLength uint16) {
 	return 133
 }
 
+func (*Ticker) SbeTemplateId() (templateId uint16) {
+	return 1003
+}
+
+func (*Ticker) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*Ticker) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*Ticker) InstrumentIdMinValue() uint32 {
 	return 0
 }
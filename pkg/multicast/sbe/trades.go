@@ -1,3 +1,5 @@
+// Code generated by sbegen from deribit_multicast.xml. DO NOT EDIT.
+
 package sbe
 
 import (
@@ -28,7 +30,54 @@ type TradesTradesList struct {
 	ComboTradeId  uint64
 }
 
+func (t *Trades) Encode(_m *SbeGoMarshaller, _w io.Writer, doRangeCheck bool) error {
+	if doRangeCheck {
+		if err := t.RangeCheck(); err != nil {
+			return err
+		}
+	}
+
+	if err := _m.WriteUint32(_w, t.InstrumentId); err != nil {
+		return err
+	}
+
+	if len(t.TradesList) > math.MaxUint16 {
+		return fmt.Errorf("%w on t.TradesList length (%v > %v)", ErrRangeCheck, len(t.TradesList), math.MaxUint16)
+	}
+
+	if err := _m.WriteUint16(_w, 83); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, uint16(len(t.TradesList))); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint16(_w, 0); err != nil {
+		return err
+	}
+
+	for i := range t.TradesList {
+		if err := t.TradesList[i].Encode(_m, _w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t *Trades) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, doRangeCheck bool) error {
+	return t.DecodeVersion(_m, _r, t.SbeSchemaVersion(), blockLength, doRangeCheck)
+}
+
+// DecodeVersion decodes a message encoded with the actingVersion of the schema, i.e. MessageHeader.Version.
+func (t *Trades) DecodeVersion(
+	_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
 	if err := _m.ReadUint32(_r, &t.InstrumentId); err != nil {
 		return err
 	}
@@ -41,6 +90,7 @@ func (t *Trades) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, d
 	if err := _m.ReadUint16(_r, &TradesListBlockLength); err != nil {
 		return err
 	}
+
 	var TradesListNumInGroup uint16
 	if err := _m.ReadUint16(_r, &TradesListNumInGroup); err != nil {
 		return err
@@ -54,7 +104,7 @@ func (t *Trades) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, d
 	}
 	t.TradesList = t.TradesList[:TradesListNumInGroup]
 	for i := range t.TradesList {
-		if err := t.TradesList[i].Decode(_m, _r, uint(TradesListBlockLength)); err != nil {
+		if err := t.TradesList[i].DecodeVersion(_m, _r, actingVersion, uint(TradesListBlockLength)); err != nil {
 			return err
 		}
 	}
@@ -67,6 +117,45 @@ func (t *Trades) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint16, d
 	return nil
 }
 
+// DecodeBytes decodes a message encoded with the actingVersion of the schema from the offset of _d.
+// The group and var data slices of t are reused, decoding into the same t does not allocate once
+// they are large enough.
+func (t *Trades) DecodeBytes(
+	_d *SbeGoDecoder, actingVersion uint16, blockLength uint16, doRangeCheck bool,
+) error {
+	t.InstrumentId = _d.Uint32()
+
+	if blockLength > t.SbeBlockLength() {
+		_d.Skip(int(blockLength - t.SbeBlockLength()))
+	}
+
+	TradesListBlockLength := _d.Uint16()
+	TradesListNumInGroup := _d.Uint16()
+	_d.Skip(4) // numGroups and numVars
+	if err := _d.Need(int(TradesListNumInGroup) * int(TradesListBlockLength)); err != nil {
+		return err
+	}
+	if cap(t.TradesList) < int(TradesListNumInGroup) {
+		t.TradesList = make([]TradesTradesList, TradesListNumInGroup)
+	}
+	t.TradesList = t.TradesList[:TradesListNumInGroup]
+	for i := range t.TradesList {
+		if err := t.TradesList[i].DecodeBytes(_d, actingVersion, uint(TradesListBlockLength)); err != nil {
+			return err
+		}
+	}
+
+	if err := _d.Err(); err != nil {
+		return err
+	}
+	if doRangeCheck {
+		if err := t.RangeCheck(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (t *Trades) RangeCheck() error {
 	if t.InstrumentId < t.InstrumentIdMinValue() || t.InstrumentId > t.InstrumentIdMaxValue() {
 		return fmt.Errorf("%w on t.InstrumentId (%v < %v > %v)", ErrRangeCheck, t.InstrumentIdMinValue(), t.InstrumentId, t.InstrumentIdMaxValue())
@@ -81,7 +170,67 @@ func (t *Trades) RangeCheck() error {
 	return nil
 }
 
+func (t *TradesTradesList) Encode(_m *SbeGoMarshaller, _w io.Writer) error {
+	if err := t.Direction.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.Price); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.Amount); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.TimestampMs); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.MarkPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.IndexPrice); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.TradeSeq); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.TradeId); err != nil {
+		return err
+	}
+
+	if err := t.TickDirection.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := t.Liquidation.Encode(_m, _w); err != nil {
+		return err
+	}
+
+	if err := _m.WriteFloat64(_w, t.Iv); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.BlockTradeId); err != nil {
+		return err
+	}
+
+	if err := _m.WriteUint64(_w, t.ComboTradeId); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (t *TradesTradesList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength uint) error {
+	return t.DecodeVersion(_m, _r, SchemaVersion, blockLength)
+}
+
+func (t *TradesTradesList) DecodeVersion(_m *SbeGoMarshaller, _r io.Reader, actingVersion uint16, blockLength uint) error {
 	if err := t.Direction.Decode(_m, _r); err != nil {
 		return err
 	}
@@ -141,6 +290,28 @@ func (t *TradesTradesList) Decode(_m *SbeGoMarshaller, _r io.Reader, blockLength
 	return nil
 }
 
+func (t *TradesTradesList) DecodeBytes(_d *SbeGoDecoder, actingVersion uint16, blockLength uint) error {
+	t.Direction = DirectionEnum(_d.Uint8())
+	t.Price = _d.Float64()
+	t.Amount = _d.Float64()
+	t.TimestampMs = _d.Uint64()
+	t.MarkPrice = _d.Float64()
+	t.IndexPrice = _d.Float64()
+	t.TradeSeq = _d.Uint64()
+	t.TradeId = _d.Uint64()
+	t.TickDirection = TickDirectionEnum(_d.Uint8())
+	t.Liquidation = LiquidationEnum(_d.Uint8())
+	t.Iv = _d.Float64()
+	t.BlockTradeId = _d.Uint64()
+	t.ComboTradeId = _d.Uint64()
+
+	if blockLength > t.SbeBlockLength() {
+		_d.Skip(int(blockLength - t.SbeBlockLength()))
+	}
+
+	return _d.Err()
+}
+
 func (t *TradesTradesList) RangeCheck() error {
 	if err := t.Direction.RangeCheck(); err != nil {
 		return err
@@ -177,6 +348,7 @@ func (t *TradesTradesList) RangeCheck() error {
 	if err := t.TickDirection.RangeCheck(); err != nil {
 		return err
 	}
+
 	if err := t.Liquidation.RangeCheck(); err != nil {
 		return err
 	}
@@ -200,6 +372,18 @@ func (*Trades) SbeBlockLength() (blockLength uint16) {
 	return 4
 }
 
+func (*Trades) SbeTemplateId() (templateId uint16) {
+	return 1002
+}
+
+func (*Trades) SbeSchemaId() (schemaId uint16) {
+	return SchemaId
+}
+
+func (*Trades) SbeSchemaVersion() (schemaVersion uint16) {
+	return SchemaVersion
+}
+
 func (*Trades) InstrumentIdMinValue() uint32 {
 	return 0
 }
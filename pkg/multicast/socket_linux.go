@@ -0,0 +1,63 @@
+package multicast
+
+import (
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// controlMessageSize fits the IP_PKTINFO, SCM_TIMESTAMPNS and SO_RXQ_OVFL control messages.
+const (
+	controlMessageSize = 128
+	sizeofTimespec     = int(unsafe.Sizeof(unix.Timespec{}))
+)
+
+// setReceiveBuffer sets SO_RCVBUFFORCE, which is not capped by net.core.rmem_max but needs
+// CAP_NET_ADMIN, and falls back to SO_RCVBUF.
+func setReceiveBuffer(fd, size int) error {
+	if err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUFFORCE, size); err == nil {
+		return nil
+	}
+	return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF, size)
+}
+
+// enableReceiveInfo makes the kernel attach its receive time to every datagram, and the number of
+// datagrams dropped by the socket once some were.
+func enableReceiveInfo(fd int) error {
+	if err := unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_TIMESTAMPNS, 1); err != nil {
+		return err
+	}
+	return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RXQ_OVFL, 1)
+}
+
+// parseControlMessage parses the control messages of a datagram without allocating.
+func parseControlMessage(b []byte) (info receiveInfo) {
+	for len(b) >= unix.SizeofCmsghdr {
+		h := (*unix.Cmsghdr)(unsafe.Pointer(&b[0])) // nolint:gosec
+		l := int(h.Len)
+		if l < unix.SizeofCmsghdr || l > len(b) {
+			return info
+		}
+		data := b[unix.SizeofCmsghdr:l]
+
+		switch {
+		case h.Level == unix.IPPROTO_IP && h.Type == unix.IP_PKTINFO && len(data) >= unix.SizeofInet4Pktinfo:
+			info.dst = (*unix.Inet4Pktinfo)(unsafe.Pointer(&data[0])).Addr // nolint:gosec
+			info.hasDst = true
+		case h.Level == unix.SOL_SOCKET && h.Type == unix.SCM_TIMESTAMPNS && len(data) >= sizeofTimespec:
+			ts := (*unix.Timespec)(unsafe.Pointer(&data[0])) // nolint:gosec
+			info.receivedAt = time.Unix(ts.Unix())
+		case h.Level == unix.SOL_SOCKET && h.Type == unix.SO_RXQ_OVFL && len(data) >= 4:
+			info.drops = *(*uint32)(unsafe.Pointer(&data[0])) // nolint:gosec
+			info.hasDrops = true
+		}
+
+		next := unix.CmsgSpace(l - unix.SizeofCmsghdr)
+		if next > len(b) {
+			return info
+		}
+		b = b[next:]
+	}
+	return info
+}
@@ -0,0 +1,44 @@
+package multicast
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/ipv4"
+)
+
+func TestParseControlMessage(t *testing.T) {
+	lc := net.ListenConfig{Control: ReceiveControl(0)}
+	baseConn, err := lc.ListenPacket(context.Background(), "udp4", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer baseConn.Close()
+
+	conn := ipv4.NewPacketConn(baseConn)
+	require.NoError(t, conn.SetControlMessage(ipv4.FlagDst, true))
+
+	before := time.Now()
+	_, err = conn.WriteTo([]byte("Hello World!"), nil, conn.LocalAddr())
+	require.NoError(t, err)
+
+	msgs := []ipv4.Message{{
+		Buffers: [][]byte{make([]byte, maxPacketSize)},
+		OOB:     make([]byte, controlMessageSize),
+	}}
+	n, err := conn.ReadBatch(msgs, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	info := parseControlMessage(msgs[0].OOB[:msgs[0].NN])
+	require.True(t, info.hasDst)
+	require.Equal(t, [4]byte{127, 0, 0, 1}, info.dst)
+	// The kernel only attaches the drop counter once it is not zero.
+	require.Zero(t, info.drops)
+	require.False(t, info.receivedAt.Before(before.Add(-time.Second)))
+	require.False(t, info.receivedAt.After(time.Now()))
+
+	// Truncated control messages are ignored.
+	require.Equal(t, receiveInfo{}, parseControlMessage(msgs[0].OOB[:8]))
+}
@@ -0,0 +1,32 @@
+//go:build !linux
+// +build !linux
+
+package multicast
+
+import (
+	"golang.org/x/net/ipv4"
+	"golang.org/x/sys/unix"
+)
+
+const controlMessageSize = 64
+
+func setReceiveBuffer(fd, size int) error {
+	return unix.SetsockoptInt(fd, unix.SOL_SOCKET, unix.SO_RCVBUF, size)
+}
+
+// enableReceiveInfo does nothing, the receive timestamps and drop counters are Linux only.
+func enableReceiveInfo(fd int) error {
+	return nil
+}
+
+func parseControlMessage(b []byte) (info receiveInfo) {
+	var cm ipv4.ControlMessage
+	if err := cm.Parse(b); err != nil {
+		return info
+	}
+	if dst := cm.Dst.To4(); dst != nil {
+		copy(info.dst[:], dst)
+		info.hasDst = true
+	}
+	return info
+}
@@ -0,0 +1,389 @@
+package multicast
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// StaleEventChannel receives a *StaleEvent when a multicast group goes quiet.
+	StaleEventChannel = "multicast.stale"
+
+	defaultWatchdogInterval = time.Second
+)
+
+// latencyBounds are the upper bounds of the latency histogram buckets, the last bucket has no bound.
+var latencyBounds = []time.Duration{
+	250 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// WatchdogConfig configures the monitoring of the multicast groups.
+type WatchdogConfig struct {
+	// Interval between two checks of the groups, it is also the window of the packet rates.
+	// Defaults to one second.
+	Interval time.Duration
+	// Threshold is the silence after which a group is stale, zero disables the stale detection.
+	Threshold time.Duration
+	// Restart restarts the connections when a group goes stale.
+	Restart bool
+}
+
+// StaleEvent is a multicast group which received nothing for longer than the watchdog threshold.
+type StaleEvent struct {
+	Address        string
+	LastReceivedAt time.Time // zero if nothing was received since the connection was opened
+	Silence        time.Duration
+}
+
+// Stats are the counters of the multicast feed.
+type Stats struct {
+	// Groups are the counters of the multicast groups by address, reset when the connections restart.
+	Groups map[string]GroupStats
+	// Channels are the counters of the channels by channel ID.
+	Channels map[uint16]ChannelStats
+	// UnsupportedTemplates counts the messages skipped by template ID.
+	UnsupportedTemplates map[uint16]uint64
+	// MalformedPackages counts the packages too short for a package header.
+	MalformedPackages uint64
+	// Latency is the time from the exchange timestamp of the events to their receive time by event type.
+	Latency map[EventType]LatencyHistogram
+}
+
+// GroupStats are the counters of a multicast group.
+type GroupStats struct {
+	Packets uint64
+	Bytes   uint64
+	// PacketsPerSecond is the rate over the last watchdog interval.
+	PacketsPerSecond float64
+	// LastReceivedAt is the receive time of the last package, zero if none.
+	LastReceivedAt time.Time
+	// SinceLastPacket is the time since the last package, or since the connection was opened.
+	SinceLastPacket time.Duration
+	// Stale is set while the group is quiet for longer than the watchdog threshold.
+	Stale bool
+}
+
+// ChannelStats are the counters of a channel, computed from the package sequence numbers.
+type ChannelStats struct {
+	Packets      uint64
+	OutOfOrder   uint64
+	Duplicates   uint64
+	Resets       uint64
+	DecodeErrors uint64
+	LastSeq      uint32
+}
+
+// LatencyHistogram counts latencies in the buckets bounded by Bounds.
+type LatencyHistogram struct {
+	// Bounds are the upper bounds of the buckets, the last bucket has no bound.
+	Bounds []time.Duration
+	// Counts are the number of latencies by bucket, it has one more item than Bounds.
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+	Max    time.Duration
+}
+
+// Mean returns the average latency.
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns the upper bound of the bucket of the q quantile, or Max for the last bucket.
+func (h LatencyHistogram) Quantile(q float64) time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	rank := uint64(math.Ceil(q * float64(h.Count)))
+	var n uint64
+	for i, count := range h.Counts {
+		n += count
+		if n >= rank && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+	return h.Max
+}
+
+type groupStats struct {
+	packets        uint64
+	bytes          uint64
+	rate           uint64 // float64 bits
+	lastReceivedAt int64
+	openedAt       int64
+	stale          int32
+}
+
+func newGroupStats() *groupStats {
+	return &groupStats{openedAt: time.Now().UnixNano()}
+}
+
+func (s *groupStats) received(n int, receivedAt int64) {
+	atomic.AddUint64(&s.packets, 1)
+	atomic.AddUint64(&s.bytes, uint64(n))
+	atomic.StoreInt64(&s.lastReceivedAt, receivedAt)
+}
+
+// silence returns the time since the last package, or since the group was joined.
+func (s *groupStats) silence(now time.Time) time.Duration {
+	last := atomic.LoadInt64(&s.lastReceivedAt)
+	if last == 0 {
+		last = atomic.LoadInt64(&s.openedAt)
+	}
+	return now.Sub(time.Unix(0, last))
+}
+
+func (s *groupStats) load(now time.Time) GroupStats {
+	stats := GroupStats{
+		Packets:          atomic.LoadUint64(&s.packets),
+		Bytes:            atomic.LoadUint64(&s.bytes),
+		PacketsPerSecond: math.Float64frombits(atomic.LoadUint64(&s.rate)),
+		SinceLastPacket:  s.silence(now),
+		Stale:            atomic.LoadInt32(&s.stale) == 1,
+	}
+	if ns := atomic.LoadInt64(&s.lastReceivedAt); ns != 0 {
+		stats.LastReceivedAt = time.Unix(0, ns)
+	}
+	return stats
+}
+
+type channelStats struct {
+	packets      uint64
+	outOfOrder   uint64
+	duplicates   uint64
+	resets       uint64
+	decodeErrors uint64
+	lastSeq      uint32
+}
+
+func (s *channelStats) load() ChannelStats {
+	return ChannelStats{
+		Packets:      atomic.LoadUint64(&s.packets),
+		OutOfOrder:   atomic.LoadUint64(&s.outOfOrder),
+		Duplicates:   atomic.LoadUint64(&s.duplicates),
+		Resets:       atomic.LoadUint64(&s.resets),
+		DecodeErrors: atomic.LoadUint64(&s.decodeErrors),
+		LastSeq:      atomic.LoadUint32(&s.lastSeq),
+	}
+}
+
+type latencyHistogram struct {
+	count  uint64
+	sum    int64
+	max    int64
+	counts []uint64
+}
+
+func newLatencyHistogram() *latencyHistogram {
+	return &latencyHistogram{counts: make([]uint64, len(latencyBounds)+1)}
+}
+
+func (h *latencyHistogram) observe(d time.Duration) {
+	i := 0
+	for i < len(latencyBounds) && d > latencyBounds[i] {
+		i++
+	}
+	atomic.AddUint64(&h.counts[i], 1)
+	atomic.AddUint64(&h.count, 1)
+	atomic.AddInt64(&h.sum, int64(d))
+	for {
+		prev := atomic.LoadInt64(&h.max)
+		if int64(d) <= prev || atomic.CompareAndSwapInt64(&h.max, prev, int64(d)) {
+			return
+		}
+	}
+}
+
+func (h *latencyHistogram) load() LatencyHistogram {
+	result := LatencyHistogram{
+		Bounds: latencyBounds,
+		Counts: make([]uint64, len(h.counts)),
+		Count:  atomic.LoadUint64(&h.count),
+		Sum:    time.Duration(atomic.LoadInt64(&h.sum)),
+		Max:    time.Duration(atomic.LoadInt64(&h.max)),
+	}
+	for i := range h.counts {
+		result.Counts[i] = atomic.LoadUint64(&h.counts[i])
+	}
+	return result
+}
+
+// stats holds the counters of a client, they are updated with atomic operations by the readers
+// and the decode workers, the maps are guarded by mu.
+type stats struct {
+	malformed uint64
+
+	mu          sync.RWMutex
+	groups      map[string]*groupStats // by address
+	channels    map[uint16]*channelStats
+	unsupported map[uint16]uint64 // by template ID
+	latency     map[EventType]*latencyHistogram
+}
+
+func newStats() *stats {
+	s := &stats{
+		groups:      make(map[string]*groupStats),
+		channels:    make(map[uint16]*channelStats),
+		unsupported: make(map[uint16]uint64),
+		latency:     make(map[EventType]*latencyHistogram),
+	}
+	for eventType := EventTypeInstrument; eventType <= EventTypeRfq; eventType++ {
+		s.latency[eventType] = newLatencyHistogram()
+	}
+	return s
+}
+
+func (s *stats) setGroup(addr string, group *groupStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.groups[addr] = group
+}
+
+func (s *stats) channel(channelID uint16) *channelStats {
+	s.mu.RLock()
+	ch, ok := s.channels[channelID]
+	s.mu.RUnlock()
+	if ok {
+		return ch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ch, ok = s.channels[channelID]; !ok {
+		ch = &channelStats{}
+		s.channels[channelID] = ch
+	}
+	return ch
+}
+
+func (s *stats) unsupportedTemplate(templateID uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsupported[templateID]++
+}
+
+// observeLatency records the latency of an event, the latency map is never written after newStats.
+func (s *stats) observeLatency(eventType EventType, d time.Duration) {
+	if h, ok := s.latency[eventType]; ok {
+		h.observe(d)
+	}
+}
+
+func (s *stats) load() Stats {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := Stats{
+		Groups:               make(map[string]GroupStats, len(s.groups)),
+		Channels:             make(map[uint16]ChannelStats, len(s.channels)),
+		UnsupportedTemplates: make(map[uint16]uint64, len(s.unsupported)),
+		MalformedPackages:    atomic.LoadUint64(&s.malformed),
+		Latency:              make(map[EventType]LatencyHistogram, len(s.latency)),
+	}
+	for addr, group := range s.groups {
+		result.Groups[addr] = group.load(now)
+	}
+	for id, ch := range s.channels {
+		result.Channels[id] = ch.load()
+	}
+	for id, n := range s.unsupported {
+		result.UnsupportedTemplates[id] = n
+	}
+	for eventType, h := range s.latency {
+		result.Latency[eventType] = h.load()
+	}
+	return result
+}
+
+// SetWatchdog configures the monitoring of the multicast groups, it applies to the next Start.
+func (c *Client) SetWatchdog(cfg WatchdogConfig) {
+	c.watchdog = cfg
+}
+
+// Stats returns the counters of the multicast groups and channels and the latency histograms.
+func (c *Client) Stats() Stats {
+	return c.stats.load()
+}
+
+// watch computes the packet rates of the groups every interval and emits a StaleEvent when a group
+// stays quiet past the threshold, until done is closed or ctx is done. A restart is requested for
+// the session generation.
+func (c *Client) watch(ctx context.Context, generation uint64, groups map[string]*groupStats, done <-chan struct{}) {
+	interval := c.watchdog.Interval
+	if interval <= 0 {
+		interval = defaultWatchdogInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastPackets := make(map[string]uint64, len(groups))
+	lastTick := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(lastTick).Seconds()
+			lastTick = now
+
+			stale := false
+			for addr, group := range groups {
+				packets := atomic.LoadUint64(&group.packets)
+				rate := float64(packets-lastPackets[addr]) / elapsed
+				atomic.StoreUint64(&group.rate, math.Float64bits(rate))
+				lastPackets[addr] = packets
+
+				if c.checkStale(addr, group, now) {
+					stale = true
+				}
+			}
+
+			if stale && c.watchdog.Restart {
+				c.log.Warnw("Restart stale connections", "generation", generation)
+				c.requestRestart(generation)
+				return
+			}
+		}
+	}
+}
+
+// checkStale updates the stale state of a group, it returns true when the group has just gone stale.
+func (c *Client) checkStale(addr string, group *groupStats, now time.Time) bool {
+	silence := group.silence(now)
+	if c.watchdog.Threshold <= 0 || silence <= c.watchdog.Threshold {
+		atomic.StoreInt32(&group.stale, 0)
+		return false
+	}
+	if !atomic.CompareAndSwapInt32(&group.stale, 0, 1) {
+		return false
+	}
+
+	event := StaleEvent{Address: addr, Silence: silence}
+	if ns := atomic.LoadInt64(&group.lastReceivedAt); ns != 0 {
+		event.LastReceivedAt = time.Unix(0, ns)
+	}
+	c.log.Warnw("Multicast group is stale", "address", addr, "silence", silence)
+	c.Emit(StaleEventChannel, &event)
+	return true
+}
@@ -0,0 +1,171 @@
+package multicast
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/multicast/sbe"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatencyHistogram(t *testing.T) {
+	h := newLatencyHistogram()
+	assert.Zero(t, h.load().Mean())
+	assert.Zero(t, h.load().Quantile(0.5))
+
+	for _, d := range []time.Duration{
+		-time.Millisecond, 300 * time.Microsecond, 800 * time.Microsecond, 800 * time.Microsecond,
+		4 * time.Millisecond, 3 * time.Second,
+	} {
+		h.observe(d)
+	}
+
+	got := h.load()
+	assert.Equal(t, latencyBounds, got.Bounds)
+	assert.EqualValues(t, 6, got.Count)
+	assert.Equal(t, []uint64{1, 1, 2, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}, got.Counts)
+	assert.Equal(t, 3*time.Second, got.Max)
+	assert.Equal(t, (3*time.Second+4*time.Millisecond+900*time.Microsecond)/6, got.Mean())
+	assert.Equal(t, time.Millisecond, got.Quantile(0.5))
+	assert.Equal(t, 5*time.Millisecond, got.Quantile(0.8))
+	assert.Equal(t, 3*time.Second, got.Quantile(1))
+}
+
+func (ts *MulticastTestSuite) TestStats() {
+	require := ts.Require()
+
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(err)
+	for _, ins := range ts.insMap {
+		c.setInstrument(ins)
+	}
+
+	// Sequence numbers of channel 1003: in order, duplicated, gap and reset.
+	channelIDSeq := make(map[uint16]uint32)
+	for _, seq := range []uint32{1, 2, 2, 5, 6} {
+		_ = c.checkPackageSeq(1003, seq, channelIDSeq)
+	}
+	require.ErrorIs(c.checkPackageSeq(1003, 0, channelIDSeq), ErrConnectionReset)
+
+	dec := newDecoder()
+	bookChangesMap := make(map[string][]sbe.BookChangesList)
+	snapshotLevelMap := make(map[string][]sbe.SnapshotLevelsList)
+
+	// A package of channel 1002 with an unsupported event before the book and trades of testPackage.
+	unsupported := append([]byte{
+		0x00, 0x00, 0xea, 0x03, 0x01, 0x00, 0x00, 0x00,
+		0x04, 0x00, 0xd0, 0x07, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03, 0x04,
+	}, testPackage[8:]...)
+	dec.receivedAt = time.UnixMilli(1664262399000)
+	require.NoError(c.handleBytes(dec, unsupported, channelIDSeq, bookChangesMap, snapshotLevelMap))
+
+	require.Error(c.handleBytes(dec, []byte{0x00, 0x00}, channelIDSeq, bookChangesMap, snapshotLevelMap))
+	require.Error(c.handleBytes(dec, testPackage[:30], channelIDSeq, bookChangesMap, snapshotLevelMap))
+
+	stats := c.Stats()
+	require.Empty(stats.Groups)
+	require.Equal(map[uint16]ChannelStats{
+		1001: {Packets: 1, DecodeErrors: 1, LastSeq: 1},
+		1002: {Packets: 1, LastSeq: 1},
+		1003: {Packets: 6, OutOfOrder: 1, Duplicates: 1, Resets: 1, LastSeq: 6},
+	}, stats.Channels)
+	require.Equal(map[uint16]uint64{2000: 1}, stats.UnsupportedTemplates)
+	require.EqualValues(1, stats.MalformedPackages)
+
+	book, trades := stats.Latency[EventTypeOrderBook], stats.Latency[EventTypeTrades]
+	require.EqualValues(1, book.Count)
+	require.EqualValues(1, trades.Count)
+	require.Zero(stats.Latency[EventTypeTicker].Count)
+	require.Equal(c.Latency(1662371873911, dec.receivedAt), book.Max)
+}
+
+func (ts *MulticastTestSuite) TestWatchdog() {
+	require := ts.Require()
+
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(err)
+	c.SetWatchdog(WatchdogConfig{Interval: 5 * time.Millisecond, Threshold: 50 * time.Millisecond})
+
+	staleCh := make(chan *StaleEvent, 2)
+	c.On(StaleEventChannel, func(e *StaleEvent) {
+		staleCh <- e
+	})
+
+	quiet, busy := newGroupStats(), newGroupStats()
+	quiet.openedAt = time.Now().Add(-time.Second).UnixNano()
+	groups := map[string]*groupStats{"239.111.111.1:6100": quiet, "239.111.111.2:6100": busy}
+	done := make(chan struct{})
+	go c.watch(context.Background(), 0, groups, done)
+
+	var event *StaleEvent
+	select {
+	case event = <-staleCh:
+	case <-time.After(time.Second):
+		require.Fail("no stale event")
+	}
+	require.Equal("239.111.111.1:6100", event.Address)
+	require.True(event.LastReceivedAt.IsZero())
+	require.GreaterOrEqual(event.Silence, time.Second)
+	require.Equal(int32(1), quiet.stale)
+
+	// The group recovers once it receives again, the busy one never went stale.
+	for i := 0; i < 10; i++ {
+		busy.received(100, time.Now().UnixNano())
+	}
+	quiet.received(100, time.Now().UnixNano())
+	require.Eventually(func() bool {
+		return !quiet.load(time.Now()).Stale
+	}, time.Second, time.Millisecond)
+	close(done)
+
+	require.Len(staleCh, 0)
+	require.EqualValues(10, busy.load(time.Now()).Packets)
+}
+
+func (ts *MulticastTestSuite) TestWatchdogRestart() {
+	require := ts.Require()
+
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(err)
+	c.SetWatchdog(WatchdogConfig{Interval: 5 * time.Millisecond, Threshold: 10 * time.Millisecond, Restart: true})
+	restartCh := make(chan struct{}, 1)
+	c.restartCh = restartCh
+
+	quiet := newGroupStats()
+	quiet.openedAt = time.Now().Add(-time.Second).UnixNano()
+	returned := make(chan struct{})
+	go func() {
+		c.watch(context.Background(), c.generation, map[string]*groupStats{"239.111.111.1:6100": quiet}, make(chan struct{}))
+		close(returned)
+	}()
+
+	// the restart is left to the supervisor of the session
+	select {
+	case <-restartCh:
+	case <-time.After(time.Second):
+		require.Fail("no restart")
+	}
+	<-returned
+}
+
+func TestWatchdogRate(t *testing.T) {
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(t, err)
+	c.SetWatchdog(WatchdogConfig{Interval: 20 * time.Millisecond})
+
+	group := newGroupStats()
+	done := make(chan struct{})
+	defer close(done)
+	go c.watch(context.Background(), 0, map[string]*groupStats{"239.111.111.1:6100": group}, done)
+
+	for i := 0; i < 100; i++ {
+		group.received(1, time.Now().UnixNano())
+	}
+	assert.Eventually(t, func() bool {
+		return group.load(time.Now()).PacketsPerSecond > 0
+	}, time.Second, time.Millisecond)
+	// Without threshold a group never goes stale.
+	assert.False(t, group.load(time.Now()).Stale)
+}
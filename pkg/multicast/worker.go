@@ -0,0 +1,143 @@
+package multicast
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync/atomic"
+
+	"github.com/KyberNetwork/deribit-api/pkg/multicast/sbe"
+)
+
+const defaultDecodeWorkers = 1
+
+// WorkerStats are the counters of a decode worker.
+type WorkerStats struct {
+	// Queued is the number of packages waiting to be decoded.
+	Queued int
+	// MaxQueued is the highest number of packages waiting since the worker started.
+	MaxQueued int
+	// Capacity is the size of the queue, the readers block when it is full.
+	Capacity int
+	// Handled is the number of packages decoded.
+	Handled uint64
+}
+
+// worker decodes the packages of the channels sharded to it. The sequence numbers and the split
+// books of a channel only ever reach one worker, so its state is not shared.
+type worker struct {
+	handled   uint64
+	maxQueued int64
+	// generation is the session of the worker, the restarts it requests are dropped once
+	// the connections have been restarted.
+	generation uint64
+
+	queue             chan packet
+	dec               *decoder
+	channelIDSeq      map[uint16]uint32
+	bookChangesMap    map[string][]sbe.BookChangesList
+	snapshotLevelsMap map[string][]sbe.SnapshotLevelsList
+}
+
+func newWorker(queueSize int) *worker {
+	return &worker{
+		queue:             make(chan packet, queueSize),
+		dec:               newDecoder(),
+		channelIDSeq:      make(map[uint16]uint32),
+		bookChangesMap:    make(map[string][]sbe.BookChangesList),
+		snapshotLevelsMap: make(map[string][]sbe.SnapshotLevelsList),
+	}
+}
+
+func (w *worker) observeQueued(n int) {
+	for {
+		prev := atomic.LoadInt64(&w.maxQueued)
+		if int64(n) <= prev || atomic.CompareAndSwapInt64(&w.maxQueued, prev, int64(n)) {
+			return
+		}
+	}
+}
+
+func (w *worker) stats() WorkerStats {
+	return WorkerStats{
+		Queued:    len(w.queue),
+		MaxQueued: int(atomic.LoadInt64(&w.maxQueued)),
+		Capacity:  cap(w.queue),
+		Handled:   atomic.LoadUint64(&w.handled),
+	}
+}
+
+// dispatcher shards the packages over the decode workers by channel ID. All the packages of
+// a channel are decoded by the same worker, so the events of a channel, and of each of
+// its instruments, are emitted in the order they were published.
+type dispatcher struct {
+	workers []*worker
+}
+
+func newDispatcher(numWorkers, queueSize int) *dispatcher {
+	d := &dispatcher{workers: make([]*worker, numWorkers)}
+	for i := range d.workers {
+		d.workers[i] = newWorker(queueSize)
+	}
+	return d
+}
+
+// shard returns the index of the worker of a package. A package too short to have a channel ID
+// goes to the first worker, which reports the decoding error.
+func (d *dispatcher) shard(data []byte) int {
+	if len(d.workers) == 1 || len(data) < 4 {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint16(data[2:])) % len(d.workers)
+}
+
+// dispatch queues a package to its worker, it blocks while the queue is full. It returns false if
+// ctx is done first, the package is then dropped.
+func (d *dispatcher) dispatch(ctx context.Context, pkt packet) bool {
+	w := d.workers[d.shard(pkt.data)]
+	select {
+	case w.queue <- pkt:
+		w.observeQueued(len(w.queue))
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// close closes the queues, the workers return once they have decoded the queued packages.
+// It must be called after the last dispatch.
+func (d *dispatcher) close() {
+	for _, w := range d.workers {
+		close(w.queue)
+	}
+}
+
+func (d *dispatcher) stats() []WorkerStats {
+	result := make([]WorkerStats, len(d.workers))
+	for i, w := range d.workers {
+		result[i] = w.stats()
+	}
+	return result
+}
+
+// runWorker decodes the packages of w until its queue is closed or ctx is done.
+func (c *Client) runWorker(ctx context.Context, w *worker, pool *Pool) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case pkt, ok := <-w.queue:
+			if !ok {
+				return
+			}
+			err := c.handleUDPPackage(ctx, w.dec, w.channelIDSeq, pkt, w.bookChangesMap, w.snapshotLevelsMap)
+			if errors.Is(err, ErrConnectionReset) {
+				c.requestRestart(w.generation)
+			} else if err != nil {
+				c.log.Errorw("Fail to handle UDP package", "error", err)
+			}
+			atomic.AddUint64(&w.handled, 1)
+			pool.Put(pkt.data)
+		}
+	}
+}
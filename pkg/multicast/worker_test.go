@@ -0,0 +1,123 @@
+package multicast
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+// testBookPackage returns testPackage with the book of instrumentID published on channelID.
+func testBookPackage(channelID uint16, seq uint32, instrumentID uint32, changeID uint64) []byte {
+	data := append([]byte(nil), testPackage...)
+	binary.LittleEndian.PutUint16(data[2:], channelID)
+	binary.LittleEndian.PutUint32(data[4:], seq)
+	binary.LittleEndian.PutUint32(data[20:], instrumentID)
+	binary.LittleEndian.PutUint64(data[32:], changeID-1)
+	binary.LittleEndian.PutUint64(data[40:], changeID)
+	return data
+}
+
+func (ts *MulticastTestSuite) TestDispatcherShard() {
+	require := ts.Require()
+
+	d := newDispatcher(3, 1)
+	require.Len(d.workers, 3)
+	require.Equal(0, d.shard(nil))
+	require.Equal(0, d.shard([]byte{0x00, 0x00, 0x01}))
+	require.Equal(1, d.shard(testBookPackage(1, 1, 210838, 1)))
+	require.Equal(2, d.shard(testBookPackage(2, 1, 210838, 1)))
+	require.Equal(0, d.shard(testBookPackage(3, 1, 210838, 1)))
+	require.Equal(1, d.shard(testBookPackage(1, 2, 210760, 2)))
+
+	require.Equal(0, newDispatcher(1, 1).shard(testBookPackage(1, 1, 210838, 1)))
+}
+
+func (ts *MulticastTestSuite) TestDecodeWorkers() {
+	require := ts.Require()
+	const numPackages = 50
+
+	c, err := NewClient("", nil, nil, nil)
+	require.NoError(err)
+	for _, ins := range ts.insMap {
+		c.setInstrument(ins)
+	}
+
+	var mu sync.Mutex
+	changeIDs := make(map[string][]int64)
+	onBook := func(b *models.OrderBookRawNotification) {
+		mu.Lock()
+		changeIDs[b.InstrumentName] = append(changeIDs[b.InstrumentName], b.ChangeID)
+		mu.Unlock()
+	}
+	c.On(newOrderBookNotificationChannel("BTC-PERPETUAL"), onBook)
+	c.On(newOrderBookNotificationChannel("ETH-PERPETUAL"), onBook)
+
+	// A small queue, the dispatch blocks until the workers catch up.
+	d := newDispatcher(2, 4)
+	pool := NewPool(maxPacketSize)
+	var wg sync.WaitGroup
+	for _, w := range d.workers {
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			c.runWorker(context.Background(), w, pool)
+		}(w)
+	}
+
+	// BTC-PERPETUAL is published on channel 1, ETH-PERPETUAL on channel 2.
+	for i := 1; i <= numPackages; i++ {
+		require.True(d.dispatch(context.Background(), packet{data: testBookPackage(1, uint32(i), 210838, uint64(i))}))
+		require.True(d.dispatch(context.Background(), packet{data: testBookPackage(2, uint32(i), 210760, uint64(i))}))
+	}
+	d.close()
+	wg.Wait()
+
+	expected := make([]int64, numPackages)
+	for i := range expected {
+		expected[i] = int64(i + 1)
+	}
+	require.Equal(expected, changeIDs["BTC-PERPETUAL"])
+	require.Equal(expected, changeIDs["ETH-PERPETUAL"])
+
+	for _, stats := range d.stats() {
+		require.EqualValues(numPackages, stats.Handled)
+		require.Zero(stats.Queued)
+		require.Equal(4, stats.Capacity)
+		require.LessOrEqual(stats.MaxQueued, stats.Capacity)
+	}
+
+	// The dispatch to a full queue gives up once the context is done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	d = newDispatcher(1, 1)
+	require.True(d.dispatch(context.Background(), packet{data: testPackage}))
+	require.False(d.dispatch(ctx, packet{data: testPackage}))
+	require.Equal(1, d.stats()[0].MaxQueued)
+}
+
+func (ts *MulticastTestSuite) TestDecodeWorkersSettings() {
+	require := ts.Require()
+
+	c, err := NewClient("", []string{"239.111.111.1:6102"}, nil, nil)
+	require.NoError(err)
+	require.Equal(defaultDecodeWorkers, c.decodeWorkers)
+	require.Nil(c.WorkerStats())
+
+	c.SetDecodeWorkers(0)
+	require.Equal(1, c.decodeWorkers)
+	c.SetDecodeWorkers(4)
+	require.Equal(4, c.decodeWorkers)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(c.ListenToEvents(ctx))
+	defer c.Stop()
+
+	stats := c.WorkerStats()
+	require.Len(stats, 4)
+	for _, s := range stats {
+		require.Equal(defaultDataChSize, s.Capacity)
+	}
+}
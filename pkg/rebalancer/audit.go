@@ -0,0 +1,138 @@
+package rebalancer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// AuditEntry is the state of a single transfer of a rebalancing run.
+type AuditEntry struct {
+	Key         string  `json:"key"`
+	RunID       string  `json:"run_id"`
+	Currency    string  `json:"currency"`
+	Source      int     `json:"source"`
+	Destination int     `json:"destination"`
+	Amount      float64 `json:"amount"`
+	Status      string  `json:"status"`
+	TransferID  int     `json:"transfer_id,omitempty"`
+	Error       string  `json:"error,omitempty"`
+	Timestamp   int64   `json:"timestamp"`
+}
+
+// AuditTrail stores the latest state of every transfer key.
+type AuditTrail interface {
+	Get(key string) (AuditEntry, bool, error)
+	Record(entry AuditEntry) error
+}
+
+// MemoryAuditTrail keeps audit entries in memory.
+type MemoryAuditTrail struct {
+	mu      sync.RWMutex
+	entries map[string]AuditEntry
+}
+
+func NewMemoryAuditTrail() *MemoryAuditTrail {
+	return &MemoryAuditTrail{
+		entries: make(map[string]AuditEntry),
+	}
+}
+
+func (t *MemoryAuditTrail) Get(key string) (AuditEntry, bool, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	entry, ok := t.entries[key]
+	return entry, ok, nil
+}
+
+func (t *MemoryAuditTrail) Record(entry AuditEntry) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries[entry.Key] = entry
+	return nil
+}
+
+// FileAuditTrail appends audit entries as JSON lines to a file.
+// The latest entry of each key wins when the file is loaded again.
+type FileAuditTrail struct {
+	*MemoryAuditTrail
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileAuditTrail opens or creates the audit file at path and loads its entries.
+func OpenFileAuditTrail(path string) (*FileAuditTrail, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &FileAuditTrail{
+		MemoryAuditTrail: NewMemoryAuditTrail(),
+		file:             file,
+	}
+	if err := t.load(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *FileAuditTrail) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return err
+		}
+		_ = t.MemoryAuditTrail.Record(entry)
+	}
+
+	return scanner.Err()
+}
+
+func (t *FileAuditTrail) Record(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return errors.New("audit trail is closed")
+	}
+	if _, err := t.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	if err := t.file.Sync(); err != nil {
+		return err
+	}
+
+	return t.MemoryAuditTrail.Record(entry)
+}
+
+// Close closes the underlying file.
+func (t *FileAuditTrail) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.file == nil {
+		return nil
+	}
+	err := t.file.Close()
+	t.file = nil
+	return err
+}
@@ -0,0 +1,229 @@
+package rebalancer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"go.uber.org/zap"
+)
+
+const (
+	AuditStatusPending = "pending"
+	AuditStatusDone    = "done"
+	AuditStatusFailed  = "failed"
+)
+
+// epsilon is the smallest amount transferred when there is no minimum.
+const epsilon = 1e-9
+
+var ErrUnconfirmedTransfer = errors.New("transfer was submitted but never confirmed")
+
+// Client is the subset of the Deribit API used by the Rebalancer.
+type Client interface {
+	GetAccountSummary(
+		ctx context.Context, params *models.GetAccountSummaryParams,
+	) (models.AccountSummary, error)
+	SubmitTransferBetweenSubaccounts(
+		ctx context.Context, params *models.SubmitTransferBetweenSubaccountsParams,
+	) (models.Transfer, error)
+}
+
+// Transfer is a planned movement of funds between two subaccounts.
+type Transfer struct {
+	Source      int
+	Destination int
+	Amount      float64
+}
+
+type Config struct {
+	// MinAmount is the smallest transfer the Rebalancer will submit.
+	MinAmount float64
+	// AuditTrail records every submitted transfer. Defaults to an in-memory trail.
+	AuditTrail AuditTrail
+}
+
+// Rebalancer moves funds between subaccounts to reach target balances.
+type Rebalancer struct {
+	log       *zap.SugaredLogger
+	client    Client
+	audit     AuditTrail
+	minAmount float64
+}
+
+// New creates a new Rebalancer instance.
+func New(client Client, cfg Config) *Rebalancer {
+	audit := cfg.AuditTrail
+	if audit == nil {
+		audit = NewMemoryAuditTrail()
+	}
+
+	return &Rebalancer{
+		log:       zap.S(),
+		client:    client,
+		audit:     audit,
+		minAmount: cfg.MinAmount,
+	}
+}
+
+type balance struct {
+	id     int
+	amount float64
+}
+
+// Plan fetches the account summary of every subaccount in targets and returns
+// the transfers needed to bring their balances to the target values.
+// Surpluses are capped by the available withdrawal funds of the source.
+func (r *Rebalancer) Plan(
+	ctx context.Context, currency string, targets map[int]float64,
+) ([]Transfer, error) {
+	ids := make([]int, 0, len(targets))
+	for id := range targets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var surpluses, deficits []balance
+	for _, id := range ids {
+		summary, err := r.client.GetAccountSummary(ctx, &models.GetAccountSummaryParams{
+			Currency:     currency,
+			SubaccountID: id,
+		})
+		if err != nil {
+			r.log.Errorw("Fail to get account summary", "subaccount_id", id, "error", err)
+			return nil, err
+		}
+
+		diff := summary.Balance - targets[id]
+		switch {
+		case diff > 0:
+			amount := math.Min(diff, summary.AvailableWithdrawalFunds)
+			if amount > 0 {
+				surpluses = append(surpluses, balance{id: id, amount: amount})
+			}
+		case diff < 0:
+			deficits = append(deficits, balance{id: id, amount: -diff})
+		}
+	}
+
+	sortBalances(surpluses)
+	sortBalances(deficits)
+
+	// A balance left below the minimum transfer, or a float residual, can not be moved any more.
+	threshold := math.Max(r.minAmount, epsilon)
+
+	var transfers []Transfer
+	for i, j := 0, 0; i < len(surpluses) && j < len(deficits); {
+		amount := math.Min(surpluses[i].amount, deficits[j].amount)
+		if amount >= threshold {
+			transfers = append(transfers, Transfer{
+				Source:      surpluses[i].id,
+				Destination: deficits[j].id,
+				Amount:      amount,
+			})
+			surpluses[i].amount -= amount
+			deficits[j].amount -= amount
+		}
+
+		if surpluses[i].amount < threshold {
+			i++
+		}
+		if deficits[j].amount < threshold {
+			j++
+		}
+	}
+
+	return transfers, nil
+}
+
+// Rebalance plans and executes the transfers needed to reach targets.
+// Every transfer is recorded in the audit trail under a key derived from runID,
+// so calling Rebalance again with the same runID never submits a pair twice.
+func (r *Rebalancer) Rebalance(
+	ctx context.Context, runID string, currency string, targets map[int]float64,
+) ([]models.Transfer, error) {
+	plan, err := r.Plan(ctx, currency, targets)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Transfer, 0, len(plan))
+	for _, t := range plan {
+		key := auditKey(runID, currency, t)
+		logger := r.log.With("key", key, "transfer", t)
+
+		entry, ok, err := r.audit.Get(key)
+		if err != nil {
+			logger.Errorw("Fail to read audit trail", "error", err)
+			return result, err
+		}
+		if ok {
+			switch entry.Status {
+			case AuditStatusDone:
+				logger.Infow("Transfer already done, skipping", "transfer_id", entry.TransferID)
+				continue
+			case AuditStatusPending:
+				return result, fmt.Errorf("%w: %s", ErrUnconfirmedTransfer, key)
+			}
+		}
+
+		entry = AuditEntry{
+			Key:         key,
+			RunID:       runID,
+			Currency:    currency,
+			Source:      t.Source,
+			Destination: t.Destination,
+			Amount:      t.Amount,
+			Status:      AuditStatusPending,
+			Timestamp:   time.Now().UnixMilli(),
+		}
+		if err := r.audit.Record(entry); err != nil {
+			logger.Errorw("Fail to record pending transfer", "error", err)
+			return result, err
+		}
+
+		transfer, err := r.client.SubmitTransferBetweenSubaccounts(
+			ctx,
+			&models.SubmitTransferBetweenSubaccountsParams{
+				Currency:    currency,
+				Amount:      t.Amount,
+				Destination: t.Destination,
+				Source:      t.Source,
+			},
+		)
+		entry.Timestamp = time.Now().UnixMilli()
+		if err != nil {
+			logger.Errorw("Fail to submit transfer", "error", err)
+			entry.Status = AuditStatusFailed
+			entry.Error = err.Error()
+			if err2 := r.audit.Record(entry); err2 != nil {
+				logger.Errorw("Fail to record failed transfer", "error", err2)
+			}
+			return result, err
+		}
+
+		entry.Status = AuditStatusDone
+		entry.TransferID = transfer.ID
+		if err := r.audit.Record(entry); err != nil {
+			logger.Errorw("Fail to record done transfer", "error", err)
+			return result, err
+		}
+		result = append(result, transfer)
+	}
+
+	return result, nil
+}
+
+func auditKey(runID, currency string, t Transfer) string {
+	return fmt.Sprintf("%s/%s/%d->%d", runID, currency, t.Source, t.Destination)
+}
+
+func sortBalances(balances []balance) {
+	sort.SliceStable(balances, func(i, j int) bool {
+		return balances[i].amount > balances[j].amount
+	})
+}
@@ -0,0 +1,196 @@
+package rebalancer
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockClient struct {
+	summaries map[int]models.AccountSummary
+	submitted []models.SubmitTransferBetweenSubaccountsParams
+	submitErr error
+}
+
+func (c *mockClient) GetAccountSummary(
+	_ context.Context, params *models.GetAccountSummaryParams,
+) (models.AccountSummary, error) {
+	summary, ok := c.summaries[params.SubaccountID]
+	if !ok {
+		return summary, errors.New("unknown subaccount")
+	}
+	return summary, nil
+}
+
+func (c *mockClient) SubmitTransferBetweenSubaccounts(
+	_ context.Context, params *models.SubmitTransferBetweenSubaccountsParams,
+) (models.Transfer, error) {
+	if c.submitErr != nil {
+		return models.Transfer{}, c.submitErr
+	}
+	c.submitted = append(c.submitted, *params)
+	return models.Transfer{
+		Amount:   params.Amount,
+		Currency: params.Currency,
+		ID:       len(c.submitted),
+		State:    "confirmed",
+		Type:     "subaccount",
+	}, nil
+}
+
+func newMockClient() *mockClient {
+	return &mockClient{
+		summaries: map[int]models.AccountSummary{
+			1: {Balance: 10, AvailableWithdrawalFunds: 10},
+			2: {Balance: 5, AvailableWithdrawalFunds: 2},
+			3: {Balance: 1, AvailableWithdrawalFunds: 1},
+			4: {Balance: 0, AvailableWithdrawalFunds: 0},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	r := New(newMockClient(), Config{MinAmount: 0.5})
+
+	plan, err := r.Plan(context.Background(), "BTC", map[int]float64{
+		1: 4,
+		2: 2,
+		3: 5,
+		4: 4.5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []Transfer{
+		{Source: 1, Destination: 4, Amount: 4.5},
+		{Source: 1, Destination: 3, Amount: 1.5},
+		{Source: 2, Destination: 3, Amount: 2},
+	}, plan)
+}
+
+func TestPlanSkipsSmallTransfers(t *testing.T) {
+	r := New(newMockClient(), Config{MinAmount: 1})
+
+	plan, err := r.Plan(context.Background(), "BTC", map[int]float64{
+		1: 9.5,
+		3: 1.5,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+}
+
+func TestPlanSkipsSmallLegs(t *testing.T) {
+	client := &mockClient{summaries: map[int]models.AccountSummary{
+		1: {Balance: 2, AvailableWithdrawalFunds: 2},
+		2: {Balance: 1.2, AvailableWithdrawalFunds: 1.2},
+		3: {Balance: 0},
+		4: {Balance: 0},
+	}}
+	r := New(client, Config{MinAmount: 1})
+
+	// The 0.5 left to subaccount 3 is below the minimum, the surplus of 2 still covers 4.
+	plan, err := r.Plan(context.Background(), "BTC", map[int]float64{1: 0, 2: 0, 3: 2.5, 4: 1.2})
+	require.NoError(t, err)
+	assert.Equal(t, []Transfer{
+		{Source: 1, Destination: 3, Amount: 2},
+		{Source: 2, Destination: 4, Amount: 1.2},
+	}, plan)
+}
+
+func TestPlanIgnoresResiduals(t *testing.T) {
+	client := &mockClient{summaries: map[int]models.AccountSummary{
+		1: {Balance: 0.3, AvailableWithdrawalFunds: 0.3},
+		2: {Balance: 0.1, AvailableWithdrawalFunds: 0.1},
+		3: {Balance: 0},
+		4: {Balance: 0},
+	}}
+	r := New(client, Config{})
+
+	plan, err := r.Plan(context.Background(), "BTC", map[int]float64{1: 0, 2: 0, 3: 0.2, 4: 0.2})
+	require.NoError(t, err)
+	require.Len(t, plan, 3)
+	var total float64
+	for _, transfer := range plan {
+		assert.Greater(t, transfer.Amount, epsilon)
+		total += transfer.Amount
+	}
+	assert.InDelta(t, 0.4, total, epsilon)
+}
+
+func TestRebalanceIsIdempotent(t *testing.T) {
+	client := newMockClient()
+	audit := NewMemoryAuditTrail()
+	r := New(client, Config{AuditTrail: audit})
+	targets := map[int]float64{1: 8, 4: 2}
+
+	res, err := r.Rebalance(context.Background(), "run-1", "BTC", targets)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Equal(t, []models.SubmitTransferBetweenSubaccountsParams{
+		{Currency: "BTC", Amount: 2, Destination: 4, Source: 1},
+	}, client.submitted)
+
+	entry, ok, err := audit.Get("run-1/BTC/1->4")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, AuditStatusDone, entry.Status)
+	assert.Equal(t, 1, entry.TransferID)
+
+	// Balances are unchanged in the mock, so only the audit trail prevents a second transfer.
+	res, err = r.Rebalance(context.Background(), "run-1", "BTC", targets)
+	require.NoError(t, err)
+	assert.Empty(t, res)
+	assert.Len(t, client.submitted, 1)
+}
+
+func TestRebalanceUnconfirmedTransfer(t *testing.T) {
+	client := newMockClient()
+	audit := NewMemoryAuditTrail()
+	require.NoError(t, audit.Record(AuditEntry{
+		Key:    "run-1/BTC/1->4",
+		Status: AuditStatusPending,
+	}))
+	r := New(client, Config{AuditTrail: audit})
+
+	_, err := r.Rebalance(context.Background(), "run-1", "BTC", map[int]float64{1: 8, 4: 2})
+	require.ErrorIs(t, err, ErrUnconfirmedTransfer)
+	assert.Empty(t, client.submitted)
+}
+
+func TestRebalanceSubmitError(t *testing.T) {
+	client := newMockClient()
+	client.submitErr = errors.New("not enough funds")
+	audit := NewMemoryAuditTrail()
+	r := New(client, Config{AuditTrail: audit})
+
+	_, err := r.Rebalance(context.Background(), "run-1", "BTC", map[int]float64{1: 8, 4: 2})
+	require.Error(t, err)
+
+	entry, ok, err := audit.Get("run-1/BTC/1->4")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, AuditStatusFailed, entry.Status)
+	assert.Equal(t, "not enough funds", entry.Error)
+}
+
+func TestFileAuditTrail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+
+	audit, err := OpenFileAuditTrail(path)
+	require.NoError(t, err)
+	require.NoError(t, audit.Record(AuditEntry{Key: "a", Status: AuditStatusPending}))
+	require.NoError(t, audit.Record(AuditEntry{Key: "a", Status: AuditStatusDone, TransferID: 7}))
+	require.NoError(t, audit.Close())
+
+	audit, err = OpenFileAuditTrail(path)
+	require.NoError(t, err)
+	defer audit.Close()
+
+	entry, ok, err := audit.Get("a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, AuditEntry{Key: "a", Status: AuditStatusDone, TransferID: 7}, entry)
+}
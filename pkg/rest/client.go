@@ -0,0 +1,306 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/api"
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/google/uuid"
+	"github.com/sourcegraph/jsonrpc2"
+	"go.uber.org/zap"
+)
+
+const (
+	RealBaseURL = "https://www.deribit.com/api/v2/"
+	TestBaseURL = "https://test.deribit.com/api/v2/"
+
+	defaultTimeout = 10 * time.Second
+	// tokenExpiryMargin renews the access token a little before it actually expires.
+	tokenExpiryMargin = 30 * time.Second
+	// errCodeUnauthorized is returned by Deribit when the access token is invalid or expired.
+	errCodeUnauthorized = 13009
+)
+
+var _ api.Client = (*Client)(nil)
+
+var ErrAuthenticationIsRequired = errors.New("authentication is required")
+
+// AuthMethod is the grant type used to obtain an access token.
+type AuthMethod string
+
+const (
+	AuthClientCredentials AuthMethod = "client_credentials"
+	AuthClientSignature   AuthMethod = "client_signature"
+)
+
+// RateLimiter delays outgoing calls to stay under Deribit's rate limits.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+type Configuration struct {
+	Addr        string     `json:"addr"`
+	APIKey      string     `json:"api_key"`
+	SecretKey   string     `json:"secret_key"`
+	AuthMethod  AuthMethod `json:"auth_method"`
+	HTTPClient  *http.Client
+	RateLimiter RateLimiter
+}
+
+// Client calls Deribit methods over HTTPS. Private methods are authenticated
+// with a Bearer access token, which is obtained on first use and refreshed before it expires.
+type Client struct {
+	api.Methods
+
+	l *zap.SugaredLogger
+
+	addr        string
+	apiKey      string
+	secretKey   string
+	authMethod  AuthMethod
+	httpClient  *http.Client
+	rateLimiter RateLimiter
+	now         func() time.Time
+	nextID      uint64
+
+	mu           sync.Mutex
+	accessToken  string
+	refreshToken string
+	expiresAt    time.Time
+}
+
+type request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type response struct {
+	Result json.RawMessage `json:"result"`
+	Error  *jsonrpc2.Error `json:"error"`
+}
+
+func New(l *zap.SugaredLogger, cfg *Configuration) *Client {
+	if cfg.Addr == "" {
+		cfg.Addr = RealBaseURL
+	}
+	if cfg.AuthMethod == "" {
+		cfg.AuthMethod = AuthClientCredentials
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	c := &Client{
+		l:           l,
+		addr:        strings.TrimSuffix(cfg.Addr, "/") + "/",
+		apiKey:      cfg.APIKey,
+		secretKey:   cfg.SecretKey,
+		authMethod:  cfg.AuthMethod,
+		httpClient:  cfg.HTTPClient,
+		rateLimiter: cfg.RateLimiter,
+		now:         time.Now,
+	}
+	c.Methods = api.NewMethods(c)
+	return c
+}
+
+// Call issues a JSONRPC v2 call over HTTP POST, adding the Bearer token for private methods.
+func (c *Client) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	if params == nil {
+		params = json.RawMessage("{}")
+	}
+	if !strings.HasPrefix(method, "private/") {
+		return c.call(ctx, method, params, "", result)
+	}
+
+	token, err := c.token(ctx)
+	if err != nil {
+		return err
+	}
+	err = c.call(ctx, method, params, token, result)
+
+	// the token may have been revoked before its expiry, e.g. by a logout from another session
+	var rpcErr *jsonrpc2.Error
+	if errors.As(err, &rpcErr) && rpcErr.Code == errCodeUnauthorized {
+		c.l.Infow("Access token is rejected, re-authenticating", "method", method)
+		c.resetToken()
+		if token, err = c.token(ctx); err != nil {
+			return err
+		}
+		err = c.call(ctx, method, params, token, result)
+	}
+
+	return err
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, token string, result interface{}) error {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	body, err := json.Marshal(request{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&c.nextID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addr+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	// Deribit returns JSONRPC errors with a non 200 status, so try to decode the body first.
+	var res response
+	if err = json.Unmarshal(data, &res); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, data)
+		}
+		return err
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	if result == nil || len(res.Result) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(res.Result, result)
+}
+
+// Auth requests a new access token using the configured grant type.
+func (c *Client) Auth(ctx context.Context) (result models.AuthResponse, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.auth(ctx)
+}
+
+func (c *Client) auth(ctx context.Context) (result models.AuthResponse, err error) {
+	if c.apiKey == "" || c.secretKey == "" {
+		return result, ErrAuthenticationIsRequired
+	}
+
+	var params interface{}
+	switch c.authMethod {
+	case AuthClientSignature:
+		params, err = c.signatureParams()
+		if err != nil {
+			return
+		}
+	default:
+		params = models.ClientCredentialsParams{
+			GrantType:    string(AuthClientCredentials),
+			ClientID:     c.apiKey,
+			ClientSecret: c.secretKey,
+		}
+	}
+
+	err = c.call(ctx, "public/auth", params, "", &result)
+	if err != nil {
+		return
+	}
+	c.setToken(result)
+	return
+}
+
+// signatureParams signs timestamp, nonce and data with the secret key so the secret is never sent.
+func (c *Client) signatureParams() (models.ClientSignatureParams, error) {
+	nonce, err := uuid.NewRandom()
+	if err != nil {
+		return models.ClientSignatureParams{}, err
+	}
+
+	params := models.ClientSignatureParams{
+		GrantType: string(AuthClientSignature),
+		ClientID:  c.apiKey,
+		Timestamp: c.now().UnixMilli(),
+		Nonce:     nonce.String(),
+	}
+	params.Signature = sign(c.secretKey, params.Timestamp, params.Nonce, params.Data)
+	return params, nil
+}
+
+func sign(secretKey string, timestamp int64, nonce, data string) string {
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + nonce + "\n" + data))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// token returns a valid access token, refreshing or requesting a new one when needed.
+func (c *Client) token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.accessToken != "" && c.now().Before(c.expiresAt) {
+		return c.accessToken, nil
+	}
+
+	if c.refreshToken != "" {
+		var result models.AuthResponse
+		err := c.call(ctx, "public/auth", models.RefreshTokenParams{
+			GrantType:    "refresh_token",
+			RefreshToken: c.refreshToken,
+		}, "", &result)
+		if err == nil {
+			c.setToken(result)
+			return c.accessToken, nil
+		}
+		c.l.Warnw("Fail to refresh access token", "error", err)
+	}
+
+	if _, err := c.auth(ctx); err != nil {
+		return "", fmt.Errorf("failed to auth: %w", err)
+	}
+	return c.accessToken, nil
+}
+
+func (c *Client) setToken(auth models.AuthResponse) {
+	c.accessToken = auth.AccessToken
+	c.refreshToken = auth.RefreshToken
+	c.expiresAt = c.now().Add(time.Duration(auth.ExpiresIn)*time.Second - tokenExpiryMargin)
+}
+
+func (c *Client) resetToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.accessToken = ""
+	c.expiresAt = time.Time{}
+}
@@ -0,0 +1,240 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/sourcegraph/jsonrpc2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type recordedRequest struct {
+	Path          string
+	Authorization string
+	Method        string          `json:"method"`
+	Params        json.RawMessage `json:"params"`
+}
+
+type fakeServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []recordedRequest
+	handle   func(req recordedRequest) (interface{}, *jsonrpc2.Error)
+}
+
+func newFakeServer(t *testing.T, handle func(req recordedRequest) (interface{}, *jsonrpc2.Error)) *fakeServer {
+	s := &fakeServer{handle: handle}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req recordedRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		req.Path = r.URL.Path
+		req.Authorization = r.Header.Get("Authorization")
+
+		s.mu.Lock()
+		s.requests = append(s.requests, req)
+		s.mu.Unlock()
+
+		result, rpcErr := s.handle(req)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": 1}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			resp["result"] = result
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeServer) Requests() []recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]recordedRequest(nil), s.requests...)
+}
+
+func newTestClient(addr string, method AuthMethod) *Client {
+	return New(zap.S(), &Configuration{
+		Addr:       addr,
+		APIKey:     "key",
+		SecretKey:  "secret",
+		AuthMethod: method,
+	})
+}
+
+func authResponse(token string) models.AuthResponse {
+	return models.AuthResponse{
+		AccessToken:  token,
+		RefreshToken: "refresh-" + token,
+		ExpiresIn:    900,
+		TokenType:    "bearer",
+	}
+}
+
+func TestPublicCall(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		return []models.Instrument{{InstrumentName: "BTC-PERPETUAL"}}, nil
+	})
+	c := newTestClient(s.URL, AuthClientCredentials)
+
+	res, err := c.GetInstruments(context.Background(), &models.GetInstrumentsParams{Currency: "BTC"})
+	require.NoError(t, err)
+	assert.Equal(t, []models.Instrument{{InstrumentName: "BTC-PERPETUAL"}}, res)
+
+	reqs := s.Requests()
+	require.Len(t, reqs, 1)
+	assert.Equal(t, "/public/get_instruments", reqs[0].Path)
+	assert.Equal(t, "public/get_instruments", reqs[0].Method)
+	assert.JSONEq(t, `{"currency":"BTC"}`, string(reqs[0].Params))
+	assert.Empty(t, reqs[0].Authorization)
+}
+
+func TestPrivateCallWithClientCredentials(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		if req.Method == "public/auth" {
+			return authResponse("token"), nil
+		}
+		return models.AccountSummary{Currency: "BTC"}, nil
+	})
+	c := newTestClient(s.URL, AuthClientCredentials)
+
+	for i := 0; i < 2; i++ {
+		res, err := c.GetAccountSummary(context.Background(), &models.GetAccountSummaryParams{Currency: "BTC"})
+		require.NoError(t, err)
+		assert.Equal(t, "BTC", res.Currency)
+	}
+
+	// the token is requested once and reused
+	reqs := s.Requests()
+	require.Len(t, reqs, 3)
+	assert.Equal(t, "public/auth", reqs[0].Method)
+	assert.JSONEq(t, `{"grant_type":"client_credentials","client_id":"key","client_secret":"secret"}`,
+		string(reqs[0].Params))
+	assert.Equal(t, "Bearer token", reqs[1].Authorization)
+	assert.Equal(t, "Bearer token", reqs[2].Authorization)
+}
+
+func TestPrivateCallWithClientSignature(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		if req.Method == "public/auth" {
+			return authResponse("token"), nil
+		}
+		return "ok", nil
+	})
+	c := newTestClient(s.URL, AuthClientSignature)
+	c.now = func() time.Time { return time.UnixMilli(1660000000000) }
+
+	_, err := c.ChangeSubaccountName(context.Background(), &models.ChangeSubaccountNameParams{Sid: 1, Name: "a"})
+	require.NoError(t, err)
+
+	reqs := s.Requests()
+	require.Len(t, reqs, 2)
+
+	var params models.ClientSignatureParams
+	require.NoError(t, json.Unmarshal(reqs[0].Params, &params))
+	assert.Equal(t, "client_signature", params.GrantType)
+	assert.Equal(t, "key", params.ClientID)
+	assert.Equal(t, int64(1660000000000), params.Timestamp)
+	assert.NotEmpty(t, params.Nonce)
+	assert.Equal(t, sign("secret", params.Timestamp, params.Nonce, ""), params.Signature)
+	assert.NotContains(t, string(reqs[0].Params), "secret")
+	assert.Equal(t, "Bearer token", reqs[1].Authorization)
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256("secret", "1660000000000\nabc\n")
+	assert.Equal(t,
+		"7c431555c27cbbdf6e4a99a62a96d8262fb31d6c60ee6809cc1bfde020d816d1",
+		sign("secret", 1660000000000, "abc", ""),
+	)
+}
+
+func TestTokenRefresh(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		if req.Method != "public/auth" {
+			return "ok", nil
+		}
+		if strings.Contains(string(req.Params), "refresh_token") {
+			return authResponse("refreshed"), nil
+		}
+		return authResponse("token"), nil
+	})
+	c := newTestClient(s.URL, AuthClientCredentials)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	_, err := c.GetEmailLanguage(context.Background())
+	require.NoError(t, err)
+
+	// expire the token
+	now = now.Add(time.Hour)
+	_, err = c.GetEmailLanguage(context.Background())
+	require.NoError(t, err)
+
+	reqs := s.Requests()
+	require.Len(t, reqs, 4)
+	assert.JSONEq(t, `{"grant_type":"refresh_token","refresh_token":"refresh-token"}`, string(reqs[2].Params))
+	assert.Equal(t, "Bearer refreshed", reqs[3].Authorization)
+}
+
+func TestRetryOnUnauthorized(t *testing.T) {
+	var tokens int
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		if req.Method == "public/auth" {
+			tokens++
+			return authResponse("token" + string(rune('0'+tokens))), nil
+		}
+		if req.Authorization == "Bearer token1" {
+			return nil, &jsonrpc2.Error{Code: errCodeUnauthorized, Message: "unauthorized"}
+		}
+		return "ok", nil
+	})
+	c := newTestClient(s.URL, AuthClientCredentials)
+
+	_, err := c.GetEmailLanguage(context.Background())
+	require.NoError(t, err)
+
+	reqs := s.Requests()
+	require.Len(t, reqs, 4)
+	assert.Equal(t, "Bearer token2", reqs[3].Authorization)
+}
+
+func TestCallError(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		return nil, &jsonrpc2.Error{Code: 10009, Message: "not_enough_funds"}
+	})
+	c := newTestClient(s.URL, AuthClientCredentials)
+
+	_, err := c.GetTime(context.Background())
+	var rpcErr *jsonrpc2.Error
+	require.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, int64(10009), rpcErr.Code)
+	assert.Equal(t, "not_enough_funds", rpcErr.Message)
+}
+
+func TestPrivateCallWithoutCredentials(t *testing.T) {
+	s := newFakeServer(t, func(req recordedRequest) (interface{}, *jsonrpc2.Error) {
+		return "ok", nil
+	})
+	c := New(zap.S(), &Configuration{Addr: s.URL})
+
+	_, err := c.GetEmailLanguage(context.Background())
+	assert.ErrorIs(t, err, ErrAuthenticationIsRequired)
+	assert.Empty(t, s.Requests())
+}
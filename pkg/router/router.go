@@ -0,0 +1,221 @@
+package router
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/chuckpreslar/emission"
+)
+
+const (
+	// Wildcard matches any run of characters within a segment of a channel name.
+	Wildcard = "*"
+
+	separator    = '.'
+	maxCacheSize = 1 << 14
+)
+
+var ErrInvalidPattern = errors.New("invalid channel pattern")
+
+// IsPattern reports whether an event is a channel pattern, a string with wildcards.
+func IsPattern(event interface{}) bool {
+	s, ok := event.(string)
+	return ok && strings.Contains(s, Wildcard)
+}
+
+// glob matches a segment against a pattern segment split around its wildcards.
+type glob struct {
+	parts []string // parts[0] is a prefix and parts[len-1] a suffix
+}
+
+func compileGlob(segment string) glob {
+	return glob{parts: strings.Split(segment, Wildcard)}
+}
+
+func (g glob) match(s string) bool {
+	first, last := g.parts[0], g.parts[len(g.parts)-1]
+	if len(s) < len(first)+len(last) || !strings.HasPrefix(s, first) || !strings.HasSuffix(s, last) {
+		return false
+	}
+	s = s[len(first) : len(s)-len(last)]
+	for _, part := range g.parts[1 : len(g.parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return true
+}
+
+type globNode struct {
+	segment string
+	glob    glob
+	node    *node
+}
+
+// node is a segment of the patterns, the children are the next segments.
+type node struct {
+	exact   map[string]*node
+	globs   []globNode
+	pattern string // the pattern ending at this node, if any
+}
+
+func (n *node) child(segment string) *node {
+	if !strings.Contains(segment, Wildcard) {
+		if n.exact == nil {
+			n.exact = make(map[string]*node)
+		}
+		child, ok := n.exact[segment]
+		if !ok {
+			child = &node{}
+			n.exact[segment] = child
+		}
+		return child
+	}
+
+	for _, g := range n.globs {
+		if g.segment == segment {
+			return g.node
+		}
+	}
+	child := &node{}
+	n.globs = append(n.globs, globNode{segment: segment, glob: compileGlob(segment), node: child})
+	return child
+}
+
+func (n *node) match(channel string, result []string) []string {
+	segment, rest, last := channel, "", true
+	if i := strings.IndexByte(channel, separator); i >= 0 {
+		segment, rest, last = channel[:i], channel[i+1:], false
+	}
+
+	if child, ok := n.exact[segment]; ok {
+		result = child.matchRest(rest, last, result)
+	}
+	for _, g := range n.globs {
+		if g.glob.match(segment) {
+			result = g.node.matchRest(rest, last, result)
+		}
+	}
+	return result
+}
+
+func (n *node) matchRest(rest string, last bool, result []string) []string {
+	if last {
+		if n.pattern != "" {
+			result = append(result, n.pattern)
+		}
+		return result
+	}
+	return n.match(rest, result)
+}
+
+// Router dispatches the events of a client to the listeners of the channel patterns matching
+// their channel. A pattern is a channel name whose segments, separated by dots, may contain
+// wildcards, e.g. "book.BTC-*", "ticker.*-PERPETUAL" or "trades.*.ETH".
+//
+// The patterns are kept in a trie of segments, the wildcard segments are compiled once, and the
+// patterns matching a channel are cached until the next change of the subscriptions.
+type Router struct {
+	emitter *emission.Emitter // by pattern
+
+	mu    sync.RWMutex
+	root  node
+	count map[string]int // listeners by pattern
+	cache map[string][]string
+}
+
+// New creates a new Router instance.
+func New() *Router {
+	return &Router{
+		emitter: emission.NewEmitter(),
+		count:   make(map[string]int),
+		cache:   make(map[string][]string),
+	}
+}
+
+func validate(pattern string) error {
+	for _, segment := range strings.Split(pattern, string(separator)) {
+		if segment == "" {
+			return ErrInvalidPattern
+		}
+	}
+	return nil
+}
+
+// On adds a listener to the channels matching a pattern.
+func (r *Router) On(pattern string, listener interface{}) error {
+	if err := validate(pattern); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := &r.root
+	for _, segment := range strings.Split(pattern, string(separator)) {
+		n = n.child(segment)
+	}
+	n.pattern = pattern
+	r.count[pattern]++
+	r.cache = make(map[string][]string)
+
+	r.emitter.On(pattern, listener)
+	return nil
+}
+
+// Off removes a listener of a pattern.
+func (r *Router) Off(pattern string, listener interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.count[pattern]; !ok {
+		return
+	}
+	r.emitter.Off(pattern, listener)
+	if r.count[pattern] = r.emitter.GetListenerCount(pattern); r.count[pattern] > 0 {
+		return
+	}
+
+	delete(r.count, pattern)
+	n := &r.root
+	for _, segment := range strings.Split(pattern, string(separator)) {
+		n = n.child(segment)
+	}
+	n.pattern = ""
+	r.cache = make(map[string][]string)
+}
+
+// Match returns the patterns matching a channel.
+func (r *Router) Match(channel string) []string {
+	r.mu.RLock()
+	patterns, ok := r.cache[channel]
+	empty := len(r.count) == 0
+	r.mu.RUnlock()
+	if ok || empty {
+		return patterns
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	patterns = r.root.match(channel, nil)
+	if len(r.cache) >= maxCacheSize {
+		r.cache = make(map[string][]string)
+	}
+	r.cache[channel] = patterns
+	return patterns
+}
+
+// Emit calls the listeners of the patterns matching the channel of an event.
+func (r *Router) Emit(event interface{}, arguments ...interface{}) {
+	channel, ok := event.(string)
+	if !ok {
+		return
+	}
+	for _, pattern := range r.Match(channel) {
+		r.emitter.Emit(pattern, arguments...)
+	}
+}
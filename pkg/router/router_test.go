@@ -0,0 +1,160 @@
+package router
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPattern(t *testing.T) {
+	assert.True(t, IsPattern("book.BTC-*"))
+	assert.True(t, IsPattern("*"))
+	assert.False(t, IsPattern("book.BTC-PERPETUAL"))
+	assert.False(t, IsPattern(1))
+}
+
+func TestGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		match   bool
+	}{
+		{"*", "BTC-PERPETUAL", true},
+		{"BTC-*", "BTC-PERPETUAL", true},
+		{"BTC-*", "ETH-PERPETUAL", false},
+		{"*-PERPETUAL", "ETH-PERPETUAL", true},
+		{"*-PERPETUAL", "BTC-30DEC22", false},
+		{"BTC-*-C", "BTC-30DEC22-20000-C", true},
+		{"BTC-*-C", "BTC-30DEC22-20000-P", false},
+		{"BTC-*-*-C", "BTC-30DEC22-20000-C", true},
+		{"BTC-*-*-C", "BTC-PERPETUAL-C", false},
+		{"*USD*", "btc_usd", false},
+		{"*usd*", "btc_usd", true},
+		{"a*a", "a", false},
+		{"a*a", "aa", true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.match, compileGlob(test.pattern).match(test.s), "%s %s", test.pattern, test.s)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	r := New()
+	assert.Empty(t, r.Match("book.BTC-PERPETUAL"))
+
+	patterns := []string{
+		"book.BTC-*",
+		"book.*",
+		"ticker.*-PERPETUAL",
+		"trades.*.ETH",
+		"trades.option.*",
+		"book.*.raw",
+		"*.BTC-PERPETUAL",
+	}
+	for _, pattern := range patterns {
+		require.NoError(t, r.On(pattern, func() {}))
+	}
+
+	tests := []struct {
+		channel  string
+		expected []string
+	}{
+		{"book.BTC-PERPETUAL", []string{"*.BTC-PERPETUAL", "book.*", "book.BTC-*"}},
+		{"book.ETH-PERPETUAL", []string{"book.*"}},
+		{"book.BTC-PERPETUAL.raw", []string{"book.*.raw"}},
+		{"ticker.ETH-PERPETUAL", []string{"ticker.*-PERPETUAL"}},
+		{"ticker.BTC-30DEC22", nil},
+		{"trades.option.ETH", []string{"trades.*.ETH", "trades.option.*"}},
+		{"trades.future.ETH", []string{"trades.*.ETH"}},
+		{"trades.future.BTC", nil},
+		{"trades", nil},
+		{"snapshot_start", nil},
+	}
+	for _, test := range tests {
+		got := r.Match(test.channel)
+		sort.Strings(got)
+		assert.Equal(t, test.expected, got, test.channel)
+	}
+}
+
+func TestOnInvalidPattern(t *testing.T) {
+	r := New()
+	assert.ErrorIs(t, r.On("book..*", func() {}), ErrInvalidPattern)
+	assert.ErrorIs(t, r.On("book.*.", func() {}), ErrInvalidPattern)
+	assert.Empty(t, r.Match("book..x"))
+}
+
+func TestEmit(t *testing.T) {
+	r := New()
+
+	var mu sync.Mutex
+	received := make(map[string][]int)
+	listener := func(name string) func(i *int) {
+		return func(i *int) {
+			mu.Lock()
+			received[name] = append(received[name], *i)
+			mu.Unlock()
+		}
+	}
+	books := listener("books")
+	btc := listener("btc")
+	require.NoError(t, r.On("book.*", books))
+	require.NoError(t, r.On("book.BTC-*", btc))
+
+	one, two, three := 1, 2, 3
+	r.Emit("book.BTC-PERPETUAL", &one)
+	r.Emit("book.ETH-PERPETUAL", &two)
+	r.Emit(1, &two)
+	assert.Equal(t, map[string][]int{"books": {1, 2}, "btc": {1}}, received)
+
+	// The pattern is forgotten with its last listener.
+	r.Off("book.BTC-*", btc)
+	r.Off("book.unknown*", btc)
+	assert.Equal(t, []string{"book.*"}, r.Match("book.BTC-PERPETUAL"))
+	r.Emit("book.BTC-PERPETUAL", &three)
+	assert.Equal(t, map[string][]int{"books": {1, 2, 3}, "btc": {1}}, received)
+
+	r.Off("book.*", books)
+	assert.Empty(t, r.Match("book.BTC-PERPETUAL"))
+}
+
+func TestConcurrent(t *testing.T) {
+	r := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pattern := fmt.Sprintf("book.%d-*", i)
+			listener := func() {}
+			for j := 0; j < 100; j++ {
+				assert.NoError(t, r.On(pattern, listener))
+				r.Emit(fmt.Sprintf("book.%d-%d", i, j))
+				r.Off(pattern, listener)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Empty(t, r.Match("book.1-1"))
+}
+
+func BenchmarkMatch(b *testing.B) {
+	r := New()
+	for i := 0; i < 1000; i++ {
+		_ = r.On(fmt.Sprintf("book.BTC-%d-*", i), func() {})
+	}
+	_ = r.On("book.*-PERPETUAL", func() {})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if len(r.Match("book.BTC-PERPETUAL")) != 1 {
+			b.Fatal("no match")
+		}
+	}
+}
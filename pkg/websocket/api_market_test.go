@@ -114,6 +114,31 @@ func TestGetFundingChartData(t *testing.T) {
 	}
 }
 
+func TestGetFundingRateHistory(t *testing.T) {
+	expect := models.GetFundingRateHistoryResponse{
+		{
+			Timestamp:      uint64(time.Now().UnixMilli()),
+			IndexPrice:     20000,
+			PrevIndexPrice: 19990,
+			Interest8H:     0.0001,
+			Interest1H:     0.0000125,
+		},
+	}
+	addResult(testClient.rpcConn, &expect)
+
+	res, err := testClient.GetFundingRateHistory(
+		context.Background(),
+		&models.GetFundingRateHistoryParams{
+			InstrumentName: "BTC-PERPETUAL",
+			StartTimestamp: uint64(time.Now().Add(-time.Hour).UnixMilli()),
+			EndTimestamp:   uint64(time.Now().UnixMilli()),
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expect, res)
+	}
+}
+
 func TestGetHistoricalVolatility(t *testing.T) {
 	expect := models.GetHistoricalVolatilityResponse{
 		{
@@ -187,6 +187,88 @@ func TestGetWithdrawals(t *testing.T) {
 	}
 }
 
+func TestSubmitTransferBetweenSubaccounts(t *testing.T) {
+	expect := models.Transfer{
+		Amount:           12.1234,
+		CreatedTimestamp: 1550579457727,
+		Currency:         "ETH",
+		Direction:        "payment",
+		ID:               2,
+		OtherSide:        "new_user_1_1",
+		State:            "confirmed",
+		Type:             "subaccount",
+		UpdatedTimestamp: 1550579457727,
+	}
+	addResult(testClient.rpcConn, &expect)
+
+	res, err := testClient.SubmitTransferBetweenSubaccounts(
+		context.Background(),
+		&models.SubmitTransferBetweenSubaccountsParams{
+			Currency:    "ETH",
+			Amount:      12.1234,
+			Destination: 20,
+			Source:      10,
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expect, res)
+	}
+}
+
+func TestSubmitTransferToSubaccount(t *testing.T) {
+	expect := models.Transfer{
+		Amount:           12.1234,
+		CreatedTimestamp: 1550579457727,
+		Currency:         "ETH",
+		Direction:        "payment",
+		ID:               2,
+		OtherSide:        "new_user_1_1",
+		State:            "confirmed",
+		Type:             "subaccount",
+		UpdatedTimestamp: 1550579457727,
+	}
+	addResult(testClient.rpcConn, &expect)
+
+	res, err := testClient.SubmitTransferToSubaccount(
+		context.Background(),
+		&models.SubmitTransferToSubaccountParams{
+			Currency:    "ETH",
+			Amount:      12.1234,
+			Destination: 20,
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expect, res)
+	}
+}
+
+func TestSubmitTransferToUser(t *testing.T) {
+	expect := models.Transfer{
+		Amount:           13.456,
+		CreatedTimestamp: 1550579457727,
+		Currency:         "ETH",
+		Direction:        "payment",
+		ID:               1,
+		OtherSide:        "0x4aa0753d798d668056920094d65321a8e8913e26",
+		State:            "prepared",
+		Type:             "user",
+		UpdatedTimestamp: 1550579457727,
+	}
+	addResult(testClient.rpcConn, &expect)
+
+	res, err := testClient.SubmitTransferToUser(
+		context.Background(),
+		&models.SubmitTransferToUserParams{
+			Currency:    "ETH",
+			Amount:      13.456,
+			Destination: "0x4aa0753d798d668056920094d65321a8e8913e26",
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, expect, res)
+	}
+}
+
 func TestWithdraw(t *testing.T) {
 	expect := models.Withdrawal{
 		Address:          "2NBqqD5GRJ8wHy1PYyCXTe9ke5226FhavBz",
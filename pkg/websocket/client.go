@@ -9,7 +9,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/KyberNetwork/deribit-api/pkg/api"
 	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/KyberNetwork/deribit-api/pkg/router"
 	"github.com/chuckpreslar/emission"
 	ws "github.com/gorilla/websocket"
 	"github.com/sourcegraph/jsonrpc2"
@@ -24,6 +26,8 @@ const (
 	heartbeatInterval = 30
 )
 
+var _ api.Client = (*Client)(nil)
+
 var (
 	ErrAuthenticationIsRequired = errors.New("authentication is required")
 	ErrNotConnected             = errors.New("not connected")
@@ -40,6 +44,11 @@ type JSONRPC2 interface {
 	DisconnectNotify() <-chan struct{}
 }
 
+// RateLimiter delays outgoing calls to stay under Deribit's rate limits.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
 type RPCConnector func(ctx context.Context, addr string, h jsonrpc2.Handler) (JSONRPC2, error)
 
 func NewRPCConn(ctx context.Context, addr string, h jsonrpc2.Handler) (JSONRPC2, error) {
@@ -67,9 +76,12 @@ type Configuration struct {
 	AutoReconnect bool   `json:"auto_reconnect"`
 	DebugMode     bool   `json:"debug_mode"`
 	NewRPCConn    RPCConnector
+	RateLimiter   RateLimiter
 }
 
 type Client struct {
+	api.Methods
+
 	l *zap.SugaredLogger
 
 	addr          string
@@ -79,6 +91,7 @@ type Client struct {
 	debugMode     bool
 
 	newRPCConn  RPCConnector
+	rateLimiter RateLimiter
 	rpcConn     JSONRPC2
 	mu          sync.RWMutex
 	heartCancel chan struct{}
@@ -90,6 +103,7 @@ type Client struct {
 	subscriptionsMap map[string]struct{}
 
 	emitter *emission.Emitter
+	router  *router.Router
 }
 
 func New(l *zap.SugaredLogger, cfg *Configuration) *Client {
@@ -97,7 +111,7 @@ func New(l *zap.SugaredLogger, cfg *Configuration) *Client {
 		cfg.NewRPCConn = NewRPCConn
 	}
 
-	return &Client{
+	c := &Client{
 		l:                l,
 		addr:             cfg.Addr,
 		apiKey:           cfg.APIKey,
@@ -105,10 +119,14 @@ func New(l *zap.SugaredLogger, cfg *Configuration) *Client {
 		autoReconnect:    cfg.AutoReconnect,
 		debugMode:        cfg.DebugMode,
 		newRPCConn:       cfg.NewRPCConn,
+		rateLimiter:      cfg.RateLimiter,
 		mu:               sync.RWMutex{},
 		subscriptionsMap: make(map[string]struct{}),
 		emitter:          emission.NewEmitter(),
+		router:           router.New(),
 	}
+	c.Methods = api.NewMethods(c)
+	return c
 }
 
 // setIsConnected sets state for isConnected
@@ -188,6 +206,11 @@ func (c *Client) Call(ctx context.Context, method string, params interface{}, re
 	if params == nil {
 		params = json.RawMessage("{}")
 	}
+	if c.rateLimiter != nil {
+		if err = c.rateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
 
 	err = c.rpcConn.Call(ctx, method, params, result)
 	// some case call connection return `broken pipe` or `connection reset by peer`
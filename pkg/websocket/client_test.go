@@ -159,6 +159,31 @@ func TestCall(t *testing.T) {
 	}
 }
 
+type countingRateLimiter struct {
+	calls int
+}
+
+func (l *countingRateLimiter) Wait(ctx context.Context) error {
+	l.calls++
+	return ctx.Err()
+}
+
+func TestCallWithRateLimiter(t *testing.T) {
+	limiter := &countingRateLimiter{}
+	client := newClient()
+	client.rateLimiter = limiter
+	err := client.Start()
+	require.NoError(t, err)
+	defer client.Stop()
+	assert.Equal(t, 2, limiter.calls) // auth and set_heartbeat
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Test(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, limiter.calls)
+}
+
 // nolint:lll,funlen,maintidx
 func TestHandle(t *testing.T) {
 	tests := []struct {
@@ -457,6 +482,7 @@ func TestHandle(t *testing.T) {
 				Timestamp: 1662721394017,
 				Stats: models.Stats{
 					Volume:      9678,
+					VolumeUSD:   float64Pointer(194393720),
 					PriceChange: float64Pointer(8.5),
 					Low:         19025,
 					High:        21100.5,
@@ -476,6 +502,9 @@ func TestHandle(t *testing.T) {
 				BestBidAmount:   460,
 				BestAskPrice:    float64Pointer(20987.5),
 				BestAskAmount:   400,
+
+				EstimatedDeliveryPrice: 21025.44,
+				InterestValue:          float64Pointer(-1.5464265205734715),
 			},
 		},
 		{
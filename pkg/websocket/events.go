@@ -1,18 +1,33 @@
 package websocket
 
-import "github.com/chuckpreslar/emission"
+import (
+	"github.com/KyberNetwork/deribit-api/pkg/router"
+	"github.com/chuckpreslar/emission"
+)
 
-// On adds a listener to a specific event
+// On adds a listener to a specific event, or to every channel matching a pattern with wildcards,
+// e.g. "book.BTC-*.raw" or "ticker.*-PERPETUAL.100ms". The channels still have to be subscribed to.
 func (c *Client) On(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		if err := c.router.On(event.(string), listener); err != nil {
+			c.l.Warnw("Fail to add listener", "pattern", event, "error", err)
+		}
+		return c.emitter
+	}
 	return c.emitter.On(event, listener)
 }
 
 // Emit emits an event
 func (c *Client) Emit(event interface{}, arguments ...interface{}) *emission.Emitter {
+	c.router.Emit(event, arguments...)
 	return c.emitter.Emit(event, arguments...)
 }
 
 // Off removes a listener for an event
 func (c *Client) Off(event interface{}, listener interface{}) *emission.Emitter {
+	if router.IsPattern(event) {
+		c.router.Off(event.(string), listener)
+		return c.emitter
+	}
 	return c.emitter.Off(event, listener)
 }
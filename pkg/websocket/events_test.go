@@ -28,3 +28,28 @@ func TestEventOnEmitOff(t *testing.T) {
 	client.Emit(expect)
 	assert.Len(t, eventCh, 0)
 }
+
+func TestEventPatternOnEmitOff(t *testing.T) {
+	client := newClient()
+
+	pattern := "book.BTC-*.100ms"
+	eventCh := make(chan *models.OrderBookNotification, 2)
+	listener := func(e *models.OrderBookNotification) {
+		eventCh <- e
+	}
+	client.On(pattern, listener)
+	client.On("book..*", listener)
+
+	expect := &models.OrderBookNotification{}
+	client.Emit("book.BTC-PERPETUAL.100ms", expect)
+	client.Emit("book.ETH-PERPETUAL.100ms", expect)
+	client.Emit("book.BTC-PERPETUAL.raw", expect)
+	if assert.Len(t, eventCh, 1) {
+		event := <-eventCh
+		assert.Equal(t, expect, event)
+	}
+
+	client.Off(pattern, listener)
+	client.Emit("book.BTC-PERPETUAL.100ms", expect)
+	assert.Len(t, eventCh, 0)
+}
@@ -0,0 +1,530 @@
+package websocket
+
+import (
+	"context"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+)
+
+const (
+	defaultPageSize = 100
+	sortingAsc      = "asc"
+	continuationEnd = "none"
+)
+
+// pageFetcher fetches the next page. It returns the page items and whether
+// more pages may follow, a page may be empty when more follow.
+type pageFetcher func(ctx context.Context) (items []interface{}, more bool, err error)
+
+// pageIterator lazily walks through the pages returned by fetch.
+type pageIterator struct {
+	ctx   context.Context
+	fetch pageFetcher
+	buf   []interface{}
+	cur   interface{}
+	done  bool
+	err   error
+}
+
+func newPageIterator(ctx context.Context, fetch pageFetcher) pageIterator {
+	return pageIterator{ctx: ctx, fetch: fetch}
+}
+
+func (it *pageIterator) next() bool {
+	for len(it.buf) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		items, more, err := it.fetch(it.ctx)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.buf = items
+		it.done = !more
+	}
+
+	it.cur = it.buf[0]
+	it.buf = it.buf[1:]
+	return true
+}
+
+// Err returns the first error met while fetching pages.
+func (it *pageIterator) Err() error {
+	return it.err
+}
+
+// UserTradeIterator iterates over user trades.
+type UserTradeIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more trades or an error occurred.
+func (it *UserTradeIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current trade.
+func (it *UserTradeIterator) Value() models.UserTrade {
+	return it.cur.(models.UserTrade)
+}
+
+// OrderIterator iterates over orders.
+type OrderIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more orders or an error occurred.
+func (it *OrderIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current order.
+func (it *OrderIterator) Value() models.Order {
+	return it.cur.(models.Order)
+}
+
+// TransferIterator iterates over transfers.
+type TransferIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more transfers or an error occurred.
+func (it *TransferIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current transfer.
+func (it *TransferIterator) Value() models.Transfer {
+	return it.cur.(models.Transfer)
+}
+
+// DepositIterator iterates over deposits.
+type DepositIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more deposits or an error occurred.
+func (it *DepositIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current deposit.
+func (it *DepositIterator) Value() models.Deposit {
+	return it.cur.(models.Deposit)
+}
+
+// WithdrawalIterator iterates over withdrawals.
+type WithdrawalIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more withdrawals or an error occurred.
+func (it *WithdrawalIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current withdrawal.
+func (it *WithdrawalIterator) Value() models.Withdrawal {
+	return it.cur.(models.Withdrawal)
+}
+
+// SettlementIterator iterates over settlements.
+type SettlementIterator struct {
+	pageIterator
+}
+
+// Next advances the iterator. It returns false when there are no more settlements or an error occurred.
+func (it *SettlementIterator) Next() bool {
+	return it.next()
+}
+
+// Value returns the current settlement.
+func (it *SettlementIterator) Value() models.Settlement {
+	return it.cur.(models.Settlement)
+}
+
+// newUserTrades drops the trades of page which were already returned by the previous page.
+// Deribit's trade id and timestamp bounds are inclusive, so consecutive pages overlap.
+func newUserTrades(page []models.UserTrade, seen map[string]struct{}) []interface{} {
+	items := make([]interface{}, 0, len(page))
+	for _, trade := range page {
+		if _, ok := seen[trade.TradeID]; ok {
+			continue
+		}
+		items = append(items, trade)
+	}
+
+	for id := range seen {
+		delete(seen, id)
+	}
+	for _, trade := range page {
+		seen[trade.TradeID] = struct{}{}
+	}
+
+	return items
+}
+
+// IterUserTradesByCurrency returns an iterator over all user trades matching params.
+// It pages using start_id/end_id depending on params.Sorting.
+func (c *Client) IterUserTradesByCurrency(
+	ctx context.Context,
+	params *models.GetUserTradesByCurrencyParams,
+) *UserTradeIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+	seen := make(map[string]struct{})
+
+	return &UserTradeIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetUserTradesByCurrency(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(res.Trades) == 0 {
+			return nil, false, nil
+		}
+
+		last := res.Trades[len(res.Trades)-1]
+		var prev string
+		if p.Sorting == sortingAsc {
+			prev, p.StartID = p.StartID, last.TradeID
+		} else {
+			prev, p.EndID = p.EndID, last.TradeID
+		}
+		// The cursor does not move when the page only holds the trade it starts from.
+		return newUserTrades(res.Trades, seen), res.HasMore && last.TradeID != prev, nil
+	})}
+}
+
+// IterUserTradesByInstrument returns an iterator over all user trades matching params.
+// It pages using start_seq/end_seq depending on params.Sorting.
+func (c *Client) IterUserTradesByInstrument(
+	ctx context.Context,
+	params *models.GetUserTradesByInstrumentParams,
+) *UserTradeIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &UserTradeIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetUserTradesByInstrument(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(res.Trades) == 0 {
+			return nil, false, nil
+		}
+
+		last := res.Trades[len(res.Trades)-1]
+		if p.Sorting == sortingAsc {
+			p.StartSeq = int64(last.TradeSeq) + 1
+		} else {
+			p.EndSeq = int64(last.TradeSeq) - 1
+		}
+
+		items := make([]interface{}, len(res.Trades))
+		for i, trade := range res.Trades {
+			items[i] = trade
+		}
+		return items, res.HasMore && (p.Sorting == sortingAsc || p.EndSeq > 0), nil
+	})}
+}
+
+// IterUserTradesByInstrumentAndTime returns an iterator over all user trades matching params.
+// It pages by moving start_timestamp/end_timestamp depending on params.Sorting. When the trades
+// of a single millisecond fill a whole page it pages through them with start_seq/end_seq instead.
+// nolint:cyclop
+func (c *Client) IterUserTradesByInstrumentAndTime(
+	ctx context.Context,
+	params *models.GetUserTradesByInstrumentAndTimeParams,
+) *UserTradeIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+	asc := p.Sorting == sortingAsc
+	cursor := &p.EndTimestamp
+	if asc {
+		cursor = &p.StartTimestamp
+	}
+	seen := make(map[string]struct{})
+	// bySeq is set while paging by trade sequence.
+	var bySeq *models.GetUserTradesByInstrumentParams
+
+	return &UserTradeIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		var res models.GetUserTradesResponse
+		var err error
+		if bySeq != nil {
+			res, err = c.GetUserTradesByInstrument(ctx, bySeq)
+		} else {
+			res, err = c.GetUserTradesByInstrumentAndTime(ctx, &p)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		trades, more := res.Trades, res.HasMore
+		if bySeq != nil {
+			var past bool
+			trades, past = trimUserTrades(trades, &p)
+			more = more && !past
+		}
+		if len(trades) == 0 {
+			return nil, false, nil
+		}
+
+		last := trades[len(trades)-1]
+		switch {
+		case bySeq == nil && more && last.Timestamp == *cursor:
+			// The next page would be this one again.
+			bySeq = &models.GetUserTradesByInstrumentParams{
+				InstrumentName: p.InstrumentName,
+				Count:          p.Count,
+				IncludeOld:     p.IncludeOld,
+				Sorting:        p.Sorting,
+			}
+		case bySeq != nil && last.Timestamp != *cursor:
+			// The crowded millisecond is behind, the timestamp cursor overlaps with this page
+			// again and seen drops the trades returned twice.
+			bySeq = nil
+		}
+
+		if bySeq == nil {
+			*cursor = last.Timestamp
+		} else if asc {
+			bySeq.StartSeq = int64(last.TradeSeq) + 1
+		} else {
+			bySeq.EndSeq = int64(last.TradeSeq) - 1
+			more = more && bySeq.EndSeq > 0
+		}
+		return newUserTrades(trades, seen), more, nil
+	})}
+}
+
+// trimUserTrades cuts trades paged by sequence at the first trade outside of the time range of p
+// and reports whether it did.
+func trimUserTrades(
+	trades []models.UserTrade,
+	p *models.GetUserTradesByInstrumentAndTimeParams,
+) ([]models.UserTrade, bool) {
+	for i, trade := range trades {
+		if trade.Timestamp < p.StartTimestamp || trade.Timestamp > p.EndTimestamp {
+			return trades[:i], true
+		}
+	}
+	return trades, false
+}
+
+// IterOrderHistoryByCurrency returns an iterator over the order history matching params.
+func (c *Client) IterOrderHistoryByCurrency(
+	ctx context.Context,
+	params *models.GetOrderHistoryByCurrencyParams,
+) *OrderIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &OrderIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetOrderHistoryByCurrency(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		p.Offset += len(res)
+		items := make([]interface{}, len(res))
+		for i, order := range res {
+			items[i] = order
+		}
+		return items, len(res) == p.Count, nil
+	})}
+}
+
+// IterTransfers returns an iterator over all transfers matching params.
+func (c *Client) IterTransfers(
+	ctx context.Context,
+	params *models.GetTransfersParams,
+) *TransferIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &TransferIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetTransfers(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		p.Offset += len(res.Data)
+		items := make([]interface{}, len(res.Data))
+		for i, transfer := range res.Data {
+			items[i] = transfer
+		}
+		return items, len(items) > 0 && p.Offset < res.Count, nil
+	})}
+}
+
+// IterDeposits returns an iterator over all deposits matching params.
+func (c *Client) IterDeposits(
+	ctx context.Context,
+	params *models.GetDepositsParams,
+) *DepositIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &DepositIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetDeposits(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		p.Offset += len(res.Data)
+		items := make([]interface{}, len(res.Data))
+		for i, deposit := range res.Data {
+			items[i] = deposit
+		}
+		return items, len(items) > 0 && p.Offset < res.Count, nil
+	})}
+}
+
+// IterWithdrawals returns an iterator over all withdrawals matching params.
+func (c *Client) IterWithdrawals(
+	ctx context.Context,
+	params *models.GetWithdrawalsParams,
+) *WithdrawalIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &WithdrawalIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetWithdrawals(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		p.Offset += len(res)
+		items := make([]interface{}, len(res))
+		for i, withdrawal := range res {
+			items[i] = withdrawal
+		}
+		return items, len(res) == p.Count, nil
+	})}
+}
+
+// IterSettlementHistoryByCurrency returns an iterator over the settlement history matching params.
+func (c *Client) IterSettlementHistoryByCurrency(
+	ctx context.Context,
+	params *models.GetSettlementHistoryByCurrencyParams,
+) *SettlementIterator {
+	p := *params
+	if p.Count == 0 {
+		p.Count = defaultPageSize
+	}
+
+	return &SettlementIterator{newPageIterator(ctx, func(ctx context.Context) ([]interface{}, bool, error) {
+		res, err := c.GetSettlementHistoryByCurrency(ctx, &p)
+		if err != nil {
+			return nil, false, err
+		}
+
+		more := res.Continuation != "" && res.Continuation != continuationEnd && res.Continuation != p.Continuation
+		p.Continuation = res.Continuation
+		items := make([]interface{}, len(res.Settlements))
+		for i, settlement := range res.Settlements {
+			items[i] = settlement
+		}
+		return items, more, nil
+	})}
+}
+
+// HistorySink receives the records streamed by StreamAccountHistory.
+type HistorySink interface {
+	WriteUserTrade(trade models.UserTrade) error
+	WriteOrder(order models.Order) error
+	WriteTransfer(transfer models.Transfer) error
+	WriteDeposit(deposit models.Deposit) error
+	WriteWithdrawal(withdrawal models.Withdrawal) error
+	WriteSettlement(settlement models.Settlement) error
+}
+
+// StreamAccountHistory streams the full trade, order, transfer, deposit, withdrawal
+// and settlement history of currency to sink, one page at a time.
+// nolint:cyclop
+func (c *Client) StreamAccountHistory(ctx context.Context, currency string, sink HistorySink) error {
+	includeOld := true
+
+	trades := c.IterUserTradesByCurrency(ctx, &models.GetUserTradesByCurrencyParams{
+		Currency:   currency,
+		IncludeOld: &includeOld,
+		Sorting:    sortingAsc,
+	})
+	for trades.Next() {
+		if err := sink.WriteUserTrade(trades.Value()); err != nil {
+			return err
+		}
+	}
+	if err := trades.Err(); err != nil {
+		return err
+	}
+
+	orders := c.IterOrderHistoryByCurrency(ctx, &models.GetOrderHistoryByCurrencyParams{
+		Currency:   currency,
+		IncludeOld: &includeOld,
+	})
+	for orders.Next() {
+		if err := sink.WriteOrder(orders.Value()); err != nil {
+			return err
+		}
+	}
+	if err := orders.Err(); err != nil {
+		return err
+	}
+
+	transfers := c.IterTransfers(ctx, &models.GetTransfersParams{Currency: currency})
+	for transfers.Next() {
+		if err := sink.WriteTransfer(transfers.Value()); err != nil {
+			return err
+		}
+	}
+	if err := transfers.Err(); err != nil {
+		return err
+	}
+
+	deposits := c.IterDeposits(ctx, &models.GetDepositsParams{Currency: currency})
+	for deposits.Next() {
+		if err := sink.WriteDeposit(deposits.Value()); err != nil {
+			return err
+		}
+	}
+	if err := deposits.Err(); err != nil {
+		return err
+	}
+
+	withdrawals := c.IterWithdrawals(ctx, &models.GetWithdrawalsParams{Currency: currency})
+	for withdrawals.Next() {
+		if err := sink.WriteWithdrawal(withdrawals.Value()); err != nil {
+			return err
+		}
+	}
+	if err := withdrawals.Err(); err != nil {
+		return err
+	}
+
+	settlements := c.IterSettlementHistoryByCurrency(ctx, &models.GetSettlementHistoryByCurrencyParams{
+		Currency: currency,
+	})
+	for settlements.Next() {
+		if err := sink.WriteSettlement(settlements.Value()); err != nil {
+			return err
+		}
+	}
+	return settlements.Err()
+}
@@ -0,0 +1,218 @@
+package websocket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/KyberNetwork/deribit-api/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIterUserTradesByCurrency(t *testing.T) {
+	addResult(testClient.rpcConn, &models.GetUserTradesResponse{
+		Trades: []models.UserTrade{
+			{TradeID: "ETH-3", TradeSeq: 3},
+			{TradeID: "ETH-2", TradeSeq: 2},
+		},
+		HasMore: true,
+	})
+	addResult(testClient.rpcConn, &models.GetUserTradesResponse{
+		Trades: []models.UserTrade{
+			{TradeID: "ETH-2", TradeSeq: 2},
+			{TradeID: "ETH-1", TradeSeq: 1},
+		},
+		HasMore: false,
+	})
+
+	it := testClient.IterUserTradesByCurrency(
+		context.Background(),
+		&models.GetUserTradesByCurrencyParams{Currency: "ETH", Count: 2},
+	)
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Value().TradeID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"ETH-3", "ETH-2", "ETH-1"}, ids)
+}
+
+func TestIterUserTradesByInstrument(t *testing.T) {
+	addResult(testClient.rpcConn, &models.GetUserTradesResponse{
+		Trades: []models.UserTrade{
+			{TradeID: "ETH-1", TradeSeq: 1},
+			{TradeID: "ETH-2", TradeSeq: 2},
+		},
+		HasMore: true,
+	})
+	addResult(testClient.rpcConn, &models.GetUserTradesResponse{
+		Trades: []models.UserTrade{
+			{TradeID: "ETH-3", TradeSeq: 3},
+		},
+		HasMore: false,
+	})
+
+	it := testClient.IterUserTradesByInstrument(
+		context.Background(),
+		&models.GetUserTradesByInstrumentParams{
+			InstrumentName: "ETH-PERPETUAL",
+			Count:          2,
+			Sorting:        "asc",
+		},
+	)
+	var seqs []uint64
+	for it.Next() {
+		seqs = append(seqs, it.Value().TradeSeq)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []uint64{1, 2, 3}, seqs)
+}
+
+func TestIterUserTradesByInstrumentAndTime(t *testing.T) {
+	params := models.GetUserTradesByInstrumentAndTimeParams{
+		InstrumentName: "ETH-PERPETUAL",
+		StartTimestamp: 5,
+		EndTimestamp:   10,
+		Count:          2,
+		Sorting:        "asc",
+	}
+	collect := func(pages [][]models.UserTrade, more []bool) []string {
+		for i, page := range pages {
+			addResult(testClient.rpcConn, &models.GetUserTradesResponse{Trades: page, HasMore: more[i]})
+		}
+		it := testClient.IterUserTradesByInstrumentAndTime(context.Background(), &params)
+		var ids []string
+		for it.Next() {
+			ids = append(ids, it.Value().TradeID)
+		}
+		require.NoError(t, it.Err())
+		return ids
+	}
+
+	// The first page shares one timestamp, the second one is paged by sequence and leaves that
+	// millisecond, the third one is paged by time again and overlaps with the second one.
+	ids := collect([][]models.UserTrade{
+		{{TradeID: "ETH-1", TradeSeq: 1, Timestamp: 5}, {TradeID: "ETH-2", TradeSeq: 2, Timestamp: 5}},
+		{{TradeID: "ETH-3", TradeSeq: 3, Timestamp: 5}, {TradeID: "ETH-4", TradeSeq: 4, Timestamp: 6}},
+		{{TradeID: "ETH-4", TradeSeq: 4, Timestamp: 6}, {TradeID: "ETH-5", TradeSeq: 5, Timestamp: 7}},
+		{{TradeID: "ETH-5", TradeSeq: 5, Timestamp: 7}},
+	}, []bool{true, true, true, false})
+	assert.Equal(t, []string{"ETH-1", "ETH-2", "ETH-3", "ETH-4", "ETH-5"}, ids)
+
+	// Paging by sequence stops at the end of the time range.
+	ids = collect([][]models.UserTrade{
+		{{TradeID: "ETH-1", TradeSeq: 1, Timestamp: 5}, {TradeID: "ETH-2", TradeSeq: 2, Timestamp: 5}},
+		{{TradeID: "ETH-3", TradeSeq: 3, Timestamp: 5}, {TradeID: "ETH-4", TradeSeq: 4, Timestamp: 11}},
+	}, []bool{true, true})
+	assert.Equal(t, []string{"ETH-1", "ETH-2", "ETH-3"}, ids)
+}
+
+func TestIterTransfers(t *testing.T) {
+	addResult(testClient.rpcConn, &models.GetTransfersResponse{
+		Count: 3,
+		Data:  []models.Transfer{{ID: 1}, {ID: 2}},
+	})
+	addResult(testClient.rpcConn, &models.GetTransfersResponse{
+		Count: 3,
+		Data:  []models.Transfer{{ID: 3}},
+	})
+
+	it := testClient.IterTransfers(
+		context.Background(),
+		&models.GetTransfersParams{Currency: "BTC", Count: 2},
+	)
+	var ids []int
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []int{1, 2, 3}, ids)
+}
+
+func TestIterSettlementHistoryByCurrency(t *testing.T) {
+	addResult(testClient.rpcConn, &models.GetSettlementHistoryResponse{
+		Settlements:  []models.Settlement{{Timestamp: 2}},
+		Continuation: "xY7T6cutS3t2B9YtaDkE6TS379oKnkzTvmEDUnEUP2Msa9xKWNNaT",
+	})
+	addResult(testClient.rpcConn, &models.GetSettlementHistoryResponse{
+		Settlements:  []models.Settlement{{Timestamp: 1}},
+		Continuation: "none",
+	})
+
+	it := testClient.IterSettlementHistoryByCurrency(
+		context.Background(),
+		&models.GetSettlementHistoryByCurrencyParams{Currency: "BTC"},
+	)
+	var timestamps []uint64
+	for it.Next() {
+		timestamps = append(timestamps, it.Value().Timestamp)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []uint64{2, 1}, timestamps)
+}
+
+type recordingSink struct {
+	records []interface{}
+}
+
+func (s *recordingSink) WriteUserTrade(trade models.UserTrade) error {
+	s.records = append(s.records, trade)
+	return nil
+}
+
+func (s *recordingSink) WriteOrder(order models.Order) error {
+	s.records = append(s.records, order)
+	return nil
+}
+
+func (s *recordingSink) WriteTransfer(transfer models.Transfer) error {
+	s.records = append(s.records, transfer)
+	return nil
+}
+
+func (s *recordingSink) WriteDeposit(deposit models.Deposit) error {
+	s.records = append(s.records, deposit)
+	return nil
+}
+
+func (s *recordingSink) WriteWithdrawal(withdrawal models.Withdrawal) error {
+	s.records = append(s.records, withdrawal)
+	return nil
+}
+
+func (s *recordingSink) WriteSettlement(settlement models.Settlement) error {
+	s.records = append(s.records, settlement)
+	return nil
+}
+
+func TestStreamAccountHistory(t *testing.T) {
+	addResult(testClient.rpcConn, &models.GetUserTradesResponse{
+		Trades: []models.UserTrade{{TradeID: "BTC-1"}},
+	})
+	addResult(testClient.rpcConn, &[]models.Order{{OrderID: "1"}})
+	addResult(testClient.rpcConn, &models.GetTransfersResponse{
+		Count: 1,
+		Data:  []models.Transfer{{ID: 1}},
+	})
+	addResult(testClient.rpcConn, &models.GetDepositsResponse{
+		Count: 1,
+		Data:  []models.Deposit{{TransactionID: "tx"}},
+	})
+	addResult(testClient.rpcConn, &[]models.Withdrawal{{ID: 1}})
+	addResult(testClient.rpcConn, &models.GetSettlementHistoryResponse{
+		Settlements:  []models.Settlement{{Timestamp: 1}},
+		Continuation: "none",
+	})
+
+	var sink recordingSink
+	err := testClient.StreamAccountHistory(context.Background(), "BTC", &sink)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		models.UserTrade{TradeID: "BTC-1"},
+		models.Order{OrderID: "1"},
+		models.Transfer{ID: 1},
+		models.Deposit{TransactionID: "tx"},
+		models.Withdrawal{ID: 1},
+		models.Settlement{Timestamp: 1},
+	}, sink.records)
+}